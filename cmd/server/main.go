@@ -1,10 +1,17 @@
 package main
 
 import (
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
 	"github.com/gadz82/go-api-boilerplate/internal/di"
 	"go.uber.org/fx"
 )
 
+// stopMargin is the time the application is given to stop, on top of the shutdown delay
+// and the grace period of the server, for the other hooks.
+const stopMargin = 15 * time.Second
+
 func initDb() {
 
 }
@@ -24,8 +31,19 @@ func initDb() {
 // @host      localhost:8080
 // @BasePath  /api
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 "Bearer <jwt>" or "Bearer <api key>"
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+
 func main() {
+	cfg := config.LoadConfig()
 	fx.New(
-		di.NewModule(),
+		di.NewModule(cfg),
+		fx.StopTimeout(cfg.ShutdownDelay+cfg.ShutdownTimeout+stopMargin),
 	).Run()
 }
@@ -24,47 +24,61 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/items": {
+        "/v1/admin/api_keys": {
             "get": {
-                "description": "get items",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "List all API keys (admin only). Only prefixes are returned, never the keys themselves.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
-                ],
-                "summary": "List items",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Include related resources (e.g. item_properties)",
-                        "name": "include",
-                        "in": "query"
-                    }
+                    "api_keys"
                 ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
-                        "description": "Items",
+                        "description": "API keys",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemListResponse"
+                            "$ref": "#/definitions/apikeys.JSONAPIAPIKeyListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new item (ID is auto-generated)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issue a new API key (admin only). The plaintext key is returned in the \"key\" attribute of this response only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -72,261 +86,263 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "api_keys"
                 ],
-                "summary": "Create an item",
+                "summary": "Issue an API key",
                 "parameters": [
                     {
-                        "description": "Item data",
-                        "name": "item",
+                        "description": "API key data",
+                        "name": "api_key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItem"
+                            "$ref": "#/definitions/apikeys.JSONAPIAPIKey"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created Item",
+                        "description": "Issued API key",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                            "$ref": "#/definitions/apikeys.JSONAPIAPIKeyResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
             }
         },
-        "/v1/items/{id}": {
-            "get": {
-                "description": "get item by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+        "/v1/admin/api_keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Revoke an API key (admin only). Revoked keys are kept for auditing but can no longer authenticate.",
                 "tags": [
-                    "items"
+                    "api_keys"
                 ],
-                "summary": "Show an item",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
+                        "description": "API key ID (UUID format)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Include related resources (e.g. item_properties)",
-                        "name": "include",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Item",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an item by ID (ID in request body is ignored, path parameter is used)",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/v1/admin/api_keys/{id}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Replace the secret of an API key (admin only). The previous key stops working immediately; the new plaintext key is returned once.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "api_keys"
                 ],
-                "summary": "Update an item",
+                "summary": "Rotate an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID (UUID format)",
+                        "description": "API key ID (UUID format)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Item data",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItem"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Item",
+                        "description": "Rotated API key",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                            "$ref": "#/definitions/apikeys.JSONAPIAPIKeyResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete an item by ID",
-                "tags": [
-                    "items"
-                ],
-                "summary": "Delete an item",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Item ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
             }
         },
-        "/v1/items/{id}/properties": {
+        "/v1/admin/trash/items": {
             "get": {
-                "description": "get item properties for a specific item",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "get a page of the soft-deleted items (admin only), most recently deleted first; each carries the time it was deleted in meta.deleted_at",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "item_properties"
+                    "trash"
                 ],
-                "summary": "List item properties",
+                "summary": "List deleted items",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Item ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item Properties",
+                        "description": "Deleted items",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemPropertyListResponse"
+                            "$ref": "#/definitions/items.JSONAPIDeletedItemListResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new item property for a specific item (ID is auto-generated)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            }
+        },
+        "/v1/admin/trash/items/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "permanently delete a soft-deleted item and all of its properties (admin only); items that are not deleted cannot be purged",
                 "tags": [
-                    "item_properties"
+                    "trash"
                 ],
-                "summary": "Create an item property",
+                "summary": "Purge a deleted item",
                 "parameters": [
                     {
                         "type": "string",
@@ -334,58 +350,63 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Property data",
-                        "name": "property",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemProperty"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created Item Property",
-                        "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
             }
         },
-        "/v1/items/{id}/properties/{property_id}": {
-            "get": {
-                "description": "get item property by ID for a specific item",
-                "consumes": [
-                    "application/json"
+        "/v1/admin/trash/items/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "undelete a soft-deleted item along with the properties deleted with it (admin only); properties deleted before the item stay deleted",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "item_properties"
+                    "trash"
                 ],
-                "summary": "Show an item property",
+                "summary": "Restore a deleted item",
                 "parameters": [
                     {
                         "type": "string",
@@ -393,186 +414,2973 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Property ID (UUID format)",
-                        "name": "property_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item Property",
+                        "description": "Restored item",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the item"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an item property by ID for a specific item (ID in request body is ignored, path parameter is used)",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/v1/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "get a page of the audit log of the tenant (admin only), most recent first: every change made to items and item properties, with its actor, request and the attributes it changed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "item_properties"
+                    "audit"
                 ],
-                "summary": "Update an item property",
+                "summary": "List audit events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Resource type (items, item_properties), or one resource as \u003ctype\u003e/\u003cid\u003e",
+                        "name": "resource",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Property ID (UUID format)",
-                        "name": "property_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Events of the actor (principal subject)",
+                        "name": "filter[actor]",
+                        "in": "query"
                     },
                     {
-                        "description": "Property data",
-                        "name": "property",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Events of the action (create, update, patch, revert, delete, restore, purge)",
+                        "name": "filter[action]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Events recorded at or after the time (RFC 3339 or YYYY-MM-DD); gt, lte and lt are supported too",
+                        "name": "filter[created_at][gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit events",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemProperty"
+                            "$ref": "#/definitions/items.JSONAPIAuditEventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
+                }
+            }
+        },
+        "/v1/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get a page of items, filtered and sorted, by number (page[number]) or after a cursor from a previous page (page[cursor]); answers If-None-Match and If-Modified-Since with 304",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "List items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to include (item_properties)",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of items to return (title, description, owner_id, created_at, updated_at, item_properties)",
+                        "name": "fields[items]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of included item properties to return (item_id, name, value)",
+                        "name": "fields[item_properties]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Items whose title starts with the value",
+                        "name": "filter[title]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Items created at or after the time (RFC 3339 or YYYY-MM-DD); gt, lte and lt are supported too",
+                        "name": "filter[created_at][gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Items having a property with the name after 'property.' and the value",
+                        "name": "filter[property.name]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields (title, created_at, updated_at), '-' for descending, e.g. -created_at,title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the page held by the client",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the page held by the client",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Item Property",
+                        "description": "Items",
                         "schema": {
-                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                            "$ref": "#/definitions/items.JSONAPIItemListResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Digest of the page"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the list last changed"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Delete an item property by ID for a specific item",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new item (ID is auto-generated, the owner is the authenticated caller), optionally with properties: those linked in relationships.item_properties, with their attributes in included, are created with it atomically. Their IDs only link the two and are replaced by generated ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "item_properties"
+                    "items"
                 ],
-                "summary": "Delete an item property",
+                "summary": "Create an item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Item ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Item data, with the properties to create in included",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPINewItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created Item",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/bulk": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "replace the title and description of the items of an array of resources, each identified by its id and, optionally, only at the version in its meta.version (required when REQUIRE_IF_MATCH is set). Results and modes are those of the bulk creation; each updated item carries its new version in meta.version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Update items in bulk",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Property ID (UUID format)",
-                        "name": "property_id",
-                        "in": "path",
-                        "required": true
+                        "description": "all_or_nothing (default) or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items to update",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkItems"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Results",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Results, some failed",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Document"
                         }
                     }
                 }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create the items of an array of resources, without properties. The whole array is written unless mode is best_effort; each resource gets a result in meta.results, in the order of the request, with the created item or its errors, pointing into the request document. The response is 200 when every item was created, 207 when some were not in best_effort mode, and otherwise carries the status of the errors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Create items in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "all_or_nothing (default) or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items to create",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkItems"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Results, some failed",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "soft-delete the items of an array of resource identifiers, each optionally only at the version in its meta.version (required when REQUIRE_IF_MATCH is set). Results and modes are those of the bulk creation; deleted items have a 204 result without data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Delete items in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "all_or_nothing (default) or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items to delete; attributes are ignored",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkItems"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Results, some failed",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIBulkResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "full-text search of item titles, descriptions and property values, best matches first; each result carries its relevance in meta.score",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Search items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words to search for; items matching any of them are returned",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to include (item_properties)",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of items to return (title, description, owner_id, created_at, updated_at, item_properties)",
+                        "name": "fields[items]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of included item properties to return (item_id, name, value)",
+                        "name": "fields[item_properties]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Items",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get item by ID; the ETag header holds its version, for If-Match on later changes, and answers If-None-Match and If-Modified-Since with 304",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Show an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to include (item_properties)",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of the item to return (title, description, owner_id, created_at, updated_at, item_properties)",
+                        "name": "fields[items]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of included item properties to return (item_id, name, value)",
+                        "name": "fields[item_properties]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Read the item as it was at this time (RFC 3339 or YYYY-MM-DD), from its revisions; such reads carry no validators",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the representation held by the client",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the representation held by the client",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the item and digest of the representation"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the representation last changed"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an item by ID (ID in request body is ignored, path parameter is used); with If-Match, only if the item is still at that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Update an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Item",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an item by ID; with If-Match, only if the item is still at that version",
+                "tags": [
+                    "items"
+                ],
+                "summary": "Delete an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to delete, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the attributes of an item present in the document (title, description), keeping the others; the merged item is validated. With If-Match, only if the item is still at that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Partially update an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Attributes to change",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Item",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}/properties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get a page of item properties for a specific item, by number (page[number]) or after a cursor from a previous page (page[cursor])",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "List item properties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of item properties to return (item_id, name, value)",
+                        "name": "fields[item_properties]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item Properties",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertyListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new item property for a specific item (ID is auto-generated)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Create an item property",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Property data",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemProperty"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created Item Property",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the item property"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}/properties/{property_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get item property by ID for a specific item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Show an item property",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Property ID (UUID format)",
+                        "name": "property_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of the item property to return (item_id, name, value)",
+                        "name": "fields[item_properties]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item Property",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the item property"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an item property by ID for a specific item (ID in request body is ignored, path parameter is used); with If-Match, only if the property is still at that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Update an item property",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Property ID (UUID format)",
+                        "name": "property_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Property data",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemProperty"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Item Property",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the item property"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an item property by ID for a specific item; with If-Match, only if the property is still at that version",
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Delete an item property",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Property ID (UUID format)",
+                        "name": "property_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to delete, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the attributes of an item property present in the document (name, value), keeping the others; the merged property is validated. With If-Match, only if the property is still at that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Partially update an item property",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Property ID (UUID format)",
+                        "name": "property_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Attributes to change",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemProperty"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Item Property",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertyResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the item property"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}/relationships/item_properties": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get a page of the identifiers of the properties of an item, paginated like the properties themselves",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "List the item_properties relationship of an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item Properties Relationship",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertiesRelationshipResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Attach the given properties to an item, moving them from the items they belong to, in one transaction; properties already attached are left alone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Attach item properties to an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Properties to attach",
+                        "name": "linkage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertiesLinkage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Detach the given properties from an item, which deletes them since a property cannot exist without its item; properties of other items are left alone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Detach item properties from an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Properties to detach",
+                        "name": "linkage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertiesLinkage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Make the given properties those of an item, in one transaction: they are moved from the items they belong to, and the other properties of the item are deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "item_properties"
+                ],
+                "summary": "Replace the item properties of an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Properties the item has",
+                        "name": "linkage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemPropertiesLinkage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get a page of the revisions of an item, most recent first; each is a snapshot of the item and its properties stored by a change to either, including the deletion of the item",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "List item revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page[number]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to the configured maximum",
+                        "name": "page[size]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the next link of a previous page",
+                        "name": "page[cursor]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item revisions",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemRevisionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/items/{id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "set the title and description of an item back to those of one of its revisions; the revert is saved as an update, with a new version and revision, and recorded in the audit log as a revert. Properties are not reverted. With If-Match, only if the item is still at that version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Revert an item to a revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version to revert, or *; required when REQUIRE_IF_MATCH is set",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reverted item",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIItemResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/operations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "add, update and remove items and item properties in one transaction, as described by the JSON:API Atomic Operations extension (https://jsonapi.org/ext/atomic). The request must be sent with the ext=\"https://jsonapi.org/ext/atomic\" media type parameter. An added resource may be given a lid, by which later operations refer to it (for instance in the item relationship of a property); item properties are updated and removed by id alone. Updates are partial, and updates and removals apply only at the version in meta.version when it is given (required when REQUIRE_IF_MATCH is set). Either every operation is applied, with a result for each in atomic:results, or none is, and the errors of the operation that failed point into it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "operations"
+                ],
+                "summary": "Apply atomic operations",
+                "parameters": [
+                    {
+                        "description": "Operations",
+                        "name": "operations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIOperations"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results",
+                        "schema": {
+                            "$ref": "#/definitions/items.JSONAPIOperationsResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "Applied, with no resource to return"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Document"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apierror.Document": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apierror.Error"
+                    }
+                }
+            }
+        },
+        "apierror.Error": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "meta": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "source": {
+                    "$ref": "#/definitions/apierror.Source"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "apierror.Source": {
+            "type": "object",
+            "properties": {
+                "header": {
+                    "description": "Header names the request header, e.g. \"If-Match\".",
+                    "type": "string"
+                },
+                "parameter": {
+                    "description": "Parameter names the query parameter, e.g. \"page[size]\".",
+                    "type": "string"
+                },
+                "pointer": {
+                    "description": "Pointer is a JSON pointer to the value in the request document, e.g. \"/data/attributes/title\".",
+                    "type": "string"
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKey": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apikeys.JSONAPIAPIKeyRequestData"
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyAttributes": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "ak_1a2b3c4d_c2VjcmV0"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2024-01-02T00:00:00Z"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "billing-service"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "1a2b3c4d"
+                },
+                "revoked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "items:read"
+                    ]
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/apikeys.JSONAPIAPIKeyAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "api_keys"
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apikeys.JSONAPIAPIKeyData"
+                    }
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyRequestAttributes": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "billing-service"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "items:read"
+                    ]
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyRequestData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/apikeys.JSONAPIAPIKeyRequestAttributes"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "api_keys"
+                }
+            }
+        },
+        "apikeys.JSONAPIAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apikeys.JSONAPIAPIKeyData"
+                }
+            }
+        },
+        "items.JSONAPIAuditEventAttributes": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "patch"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "after": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "before": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "5d0c4b1e-5b0a-4a8e-9d8f-2f3c7a1b6e90"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "resource_type": {
+                    "type": "string",
+                    "example": "items"
+                }
+            }
+        },
+        "items.JSONAPIAuditEventData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIAuditEventAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "audit_events"
+                }
+            }
+        },
+        "items.JSONAPIAuditEventListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIAuditEventData"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
+            }
+        },
+        "items.JSONAPIBulkItemData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIItemAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "item_1"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIBulkItemMeta"
+                },
+                "relationships": {
+                    "$ref": "#/definitions/items.JSONAPIItemRelationships"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "items"
+                }
+            }
+        },
+        "items.JSONAPIBulkItemMeta": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "description": "Version is the version an update or deletion applies to, or the version of the stored item",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "items.JSONAPIBulkItems": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIBulkItemData"
+                    }
+                }
+            }
+        },
+        "items.JSONAPIBulkMeta": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIBulkResult"
+                    }
+                }
+            }
+        },
+        "items.JSONAPIBulkResponse": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIBulkMeta"
+                }
+            }
+        },
+        "items.JSONAPIBulkResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIBulkItemData"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apierror.Error"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "201"
+                }
+            }
+        },
+        "items.JSONAPIDeletedItemData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIItemAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "item_1"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIDeletedItemMeta"
+                },
+                "relationships": {
+                    "$ref": "#/definitions/items.JSONAPIItemRelationships"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "items"
+                }
+            }
+        },
+        "items.JSONAPIDeletedItemListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIDeletedItemData"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
+            }
+        },
+        "items.JSONAPIDeletedItemMeta": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                }
+            }
+        },
+        "items.JSONAPIItem": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIItemData"
+                }
+            }
+        },
+        "items.JSONAPIItemAttributes": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Item Description"
+                },
+                "owner_id": {
+                    "description": "OwnerID is read-only: it is set to the authenticated caller on creation",
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Item Title"
+                }
+            }
+        },
+        "items.JSONAPIItemData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIItemAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "item_1"
+                },
+                "relationships": {
+                    "$ref": "#/definitions/items.JSONAPIItemRelationships"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "items"
+                }
+            }
+        },
+        "items.JSONAPIItemListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemData"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertiesLinkage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemPropertyDataIdentifier"
+                    }
+                }
+            }
+        },
+        "items.JSONAPIItemPropertiesRel": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemPropertyDataIdentifier"
+                    }
+                },
+                "links": {
+                    "description": "Links are set in responses only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/items.JSONAPIRelationshipLinks"
+                        }
+                    ]
+                }
+            }
+        },
+        "items.JSONAPIItemPropertiesRelationshipResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemPropertyDataIdentifier"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIRelationshipPageLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
+            }
+        },
+        "items.JSONAPIItemProperty": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertyAttributes": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "string",
+                    "example": "item_1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Property Name"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "Property Value"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertyData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIItemPropertyAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "prop_1"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "item_properties"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertyDataIdentifier": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "prop_1"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "item_properties"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertyListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
             }
-        }
-    },
-    "definitions": {
-        "items.JSONAPIItem": {
+        },
+        "items.JSONAPIItemPropertyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                }
+            }
+        },
+        "items.JSONAPIItemPropertySnapshot": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "prop_1"
+                },
+                "item_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Property Name"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "Property Value"
+                }
+            }
+        },
+        "items.JSONAPIItemRelationships": {
+            "type": "object",
+            "properties": {
+                "item_properties": {
+                    "$ref": "#/definitions/items.JSONAPIItemPropertiesRel"
+                }
+            }
+        },
+        "items.JSONAPIItemResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "$ref": "#/definitions/items.JSONAPIItemData"
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemProperty"
+                    }
                 }
             }
         },
-        "items.JSONAPIItemAttributes": {
+        "items.JSONAPIItemRevisionAttributes": {
             "type": "object",
             "properties": {
-                "description": {
+                "created_at": {
                     "type": "string",
-                    "example": "Item Description"
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "title": {
+                "item": {
+                    "description": "Item is the snapshot of the item, with its properties; deleted_at is set in the revision of its deletion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/items.JSONAPIItemSnapshot"
+                        }
+                    ]
+                },
+                "item_id": {
                     "type": "string",
-                    "example": "Item Title"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "items.JSONAPIItemData": {
+        "items.JSONAPIItemRevisionData": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/items.JSONAPIItemRevisionAttributes"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "item_revisions"
+                }
+            }
+        },
+        "items.JSONAPIItemRevisionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemRevisionData"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
+                }
+            }
+        },
+        "items.JSONAPIItemSearchData": {
             "type": "object",
             "properties": {
                 "attributes": {
@@ -582,6 +3390,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "item_1"
                 },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIItemSearchMeta"
+                },
                 "relationships": {
                     "$ref": "#/definitions/items.JSONAPIItemRelationships"
                 },
@@ -591,123 +3402,307 @@ const docTemplate = `{
                 }
             }
         },
-        "items.JSONAPIItemListResponse": {
+        "items.JSONAPIItemSearchMeta": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "description": "Score is higher for better matches, and only comparable within one search",
+                    "type": "number",
+                    "example": 1.83
+                }
+            }
+        },
+        "items.JSONAPIItemSearchResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/items.JSONAPIItemData"
+                        "$ref": "#/definitions/items.JSONAPIItemSearchData"
+                    }
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemProperty"
                     }
+                },
+                "links": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIPaginationMeta"
                 }
             }
         },
-        "items.JSONAPIItemPropertiesRel": {
+        "items.JSONAPIItemSnapshot": {
             "type": "object",
             "properties": {
-                "data": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Item Description"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "item_properties": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/items.JSONAPIItemPropertyDataIdentifier"
+                        "$ref": "#/definitions/items.JSONAPIItemPropertySnapshot"
                     }
+                },
+                "owner_id": {
+                    "type": "string",
+                    "example": "user-123"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Item Title"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "items.JSONAPIItemProperty": {
+        "items.JSONAPINewItem": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                    "$ref": "#/definitions/items.JSONAPIItemData"
+                },
+                "included": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                    }
                 }
             }
         },
-        "items.JSONAPIItemPropertyAttributes": {
+        "items.JSONAPIOperation": {
             "type": "object",
             "properties": {
-                "item_id": {
-                    "type": "string",
-                    "example": "item_1"
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIOperationData"
                 },
-                "name": {
+                "op": {
                     "type": "string",
-                    "example": "Property Name"
+                    "enum": [
+                        "add",
+                        "update",
+                        "remove"
+                    ],
+                    "example": "add"
                 },
-                "value": {
-                    "type": "string",
-                    "example": "Property Value"
+                "ref": {
+                    "$ref": "#/definitions/items.JSONAPIOperationRef"
                 }
             }
         },
-        "items.JSONAPIItemPropertyData": {
+        "items.JSONAPIOperationData": {
             "type": "object",
             "properties": {
                 "attributes": {
-                    "$ref": "#/definitions/items.JSONAPIItemPropertyAttributes"
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "id": {
                     "type": "string",
-                    "example": "prop_1"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "lid": {
+                    "type": "string",
+                    "example": "lamp"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIBulkItemMeta"
+                },
+                "relationships": {
+                    "$ref": "#/definitions/items.JSONAPIOperationDataRelationships"
                 },
                 "type": {
                     "type": "string",
-                    "example": "item_properties"
+                    "enum": [
+                        "items",
+                        "item_properties"
+                    ],
+                    "example": "items"
                 }
             }
         },
-        "items.JSONAPIItemPropertyDataIdentifier": {
+        "items.JSONAPIOperationDataRelationships": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/items.JSONAPIOperationItemRel"
+                }
+            }
+        },
+        "items.JSONAPIOperationItemRel": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/items.JSONAPIOperationRef"
+                }
+            }
+        },
+        "items.JSONAPIOperationRef": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "string",
-                    "example": "prop_1"
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "lid": {
+                    "type": "string",
+                    "example": "lamp"
+                },
+                "meta": {
+                    "$ref": "#/definitions/items.JSONAPIBulkItemMeta"
                 },
                 "type": {
                     "type": "string",
-                    "example": "item_properties"
+                    "enum": [
+                        "items",
+                        "item_properties"
+                    ],
+                    "example": "items"
                 }
             }
         },
-        "items.JSONAPIItemPropertyListResponse": {
+        "items.JSONAPIOperationResult": {
             "type": "object",
             "properties": {
                 "data": {
+                    "$ref": "#/definitions/items.JSONAPIOperationData"
+                }
+            }
+        },
+        "items.JSONAPIOperations": {
+            "type": "object",
+            "properties": {
+                "atomic:operations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                        "$ref": "#/definitions/items.JSONAPIOperation"
                     }
                 }
             }
         },
-        "items.JSONAPIItemPropertyResponse": {
+        "items.JSONAPIOperationsResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/items.JSONAPIItemPropertyData"
+                "atomic:results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/items.JSONAPIOperationResult"
+                    }
                 }
             }
         },
-        "items.JSONAPIItemRelationships": {
+        "items.JSONAPIPaginationLinks": {
             "type": "object",
             "properties": {
-                "item_properties": {
-                    "$ref": "#/definitions/items.JSONAPIItemPropertiesRel"
+                "first": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=1\u0026page%5Bsize%5D=20"
+                },
+                "last": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=5\u0026page%5Bsize%5D=20"
+                },
+                "next": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=3\u0026page%5Bsize%5D=20"
+                },
+                "prev": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=1\u0026page%5Bsize%5D=20"
+                },
+                "self": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=2\u0026page%5Bsize%5D=20"
                 }
             }
         },
-        "items.JSONAPIItemResponse": {
+        "items.JSONAPIPaginationMeta": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/items.JSONAPIItemData"
+                "total": {
+                    "type": "integer",
+                    "example": 97
+                }
+            }
+        },
+        "items.JSONAPIRelationshipLinks": {
+            "type": "object",
+            "properties": {
+                "related": {
+                    "type": "string",
+                    "example": "/api/v1/items/550e8400-e29b-41d4-a716-446655440000/item_properties"
                 },
-                "included": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/items.JSONAPIItemProperty"
-                    }
+                "self": {
+                    "type": "string",
+                    "example": "/api/v1/items/550e8400-e29b-41d4-a716-446655440000/relationships/item_properties"
+                }
+            }
+        },
+        "items.JSONAPIRelationshipPageLinks": {
+            "type": "object",
+            "properties": {
+                "first": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=1\u0026page%5Bsize%5D=20"
+                },
+                "last": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=5\u0026page%5Bsize%5D=20"
+                },
+                "next": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=3\u0026page%5Bsize%5D=20"
+                },
+                "prev": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=1\u0026page%5Bsize%5D=20"
+                },
+                "related": {
+                    "type": "string",
+                    "example": "/api/v1/items/550e8400-e29b-41d4-a716-446655440000/item_properties"
+                },
+                "self": {
+                    "type": "string",
+                    "example": "/api/v1/items?page%5Bnumber%5D=2\u0026page%5Bsize%5D=20"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "\"Bearer \u003cjwt\u003e\" or \"Bearer \u003capi key\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
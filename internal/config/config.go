@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,29 @@ type Config struct {
 	DBPort string
 	DBName string
 
+	// HTTP server configuration
+	// The server listens on ServerAddr, over TLS when TLSCertFile and TLSKeyFile
+	// are set, and also requires client certificates signed by TLSClientCAFile
+	// when it is set (mTLS). On shutdown, in-flight requests are given up to
+	// ShutdownTimeout to complete before their connections are closed. Before that,
+	// the server keeps serving for ShutdownDelay while /readyz reports it unready, so
+	// that load balancers stop sending it requests.
+	ServerAddr        string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	ShutdownTimeout   time.Duration
+	ShutdownDelay     time.Duration
+
+	// Health check configuration
+	// /readyz fails a dependency that does not answer within HealthCheckTimeout.
+	HealthCheckTimeout time.Duration
+
 	// Redis configuration
 	RedisHost     string
 	RedisPort     string
@@ -28,6 +52,56 @@ type Config struct {
 	// LoggingLevel defines the verbosity of logs:
 	// 1 = Error, 2 = Warn, 3 = Info, 4 = Debug
 	LoggingLevel int
+
+	// JWT authentication configuration
+	// JWTSecret enables HS256 tokens; JWTPublicKeyFile (PEM) and JWTJWKSFile (local JWKS)
+	// enable RS256/ES256 tokens. Any combination may be configured at once.
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWTJWKSFile      string
+	JWTIssuer        string
+	JWTAudience      string
+	JWTLeeway        time.Duration
+
+	// Multi-tenancy configuration
+	// The tenant of a request is read from TenantHeader, or from the subdomain of
+	// TenantBaseDomain (e.g. "acme" in acme.api.example.com), and must match the
+	// tenant the credential is bound to (the TenantClaim JWT claim or the API key's
+	// tenant). Unbound credentials and requests naming no tenant use DefaultTenant.
+	TenantHeader     string
+	TenantBaseDomain string
+	TenantClaim      string
+	DefaultTenant    string
+
+	// Pagination configuration
+	// List endpoints return PageDefaultSize results unless page[size] asks for
+	// another size, which may not exceed PageMaxSize.
+	PageDefaultSize int
+	PageMaxSize     int
+
+	// Bulk write configuration
+	// Bulk endpoints refuse (413) documents of more than BulkMaxItems resources, and the
+	// atomic operations endpoint documents of more than BulkMaxItems operations.
+	BulkMaxItems int
+
+	// Concurrency control configuration
+	// Writes to items and item properties honour If-Match whenever it is sent;
+	// with RequireIfMatch they are refused (428) without it.
+	RequireIfMatch bool
+
+	// Idempotency configuration
+	// The response to a POST request sent with an Idempotency-Key is kept for
+	// IdempotencyTTL and replayed for its retries. While the request is processed
+	// its key is locked for up to IdempotencyLockTTL, and retries get 409.
+	IdempotencyTTL     time.Duration
+	IdempotencyLockTTL time.Duration
+
+	// Trash configuration
+	// Deleted items and item properties are kept for TrashRetention, then purged
+	// by a job running every TrashPurgeInterval. A retention of 0 keeps them until
+	// an admin purges them.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
 }
 
 func LoadConfig() *Config {
@@ -42,6 +116,22 @@ func LoadConfig() *Config {
 		DBPort: getEnv("DB_PORT", "3306"),
 		DBName: getEnv("DB_NAME", "test"),
 
+		// HTTP server
+		ServerAddr:        getEnv("SERVER_ADDR", ":8080"),
+		ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:       getEnvDuration("SERVER_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", 2*time.Minute),
+		MaxHeaderBytes:    getEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+		TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+		ShutdownTimeout:   getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		ShutdownDelay:     getEnvDuration("SHUTDOWN_DELAY", 5*time.Second),
+
+		// Health checks
+		HealthCheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+
 		// Redis
 		RedisHost:     getEnv("REDIS_HOST", "127.0.0.1"),
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
@@ -52,11 +142,46 @@ func LoadConfig() *Config {
 
 		// Logging (default to 3=Info)
 		LoggingLevel: getEnvInt("LOGGING_LEVEL", 3),
+
+		// JWT
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTJWKSFile:      getEnv("JWT_JWKS_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
+		JWTLeeway:        getEnvDuration("JWT_LEEWAY", 30*time.Second),
+
+		// Multi-tenancy
+		TenantHeader:     getEnv("TENANT_HEADER", "X-Tenant-ID"),
+		TenantBaseDomain: getEnv("TENANT_BASE_DOMAIN", ""),
+		TenantClaim:      getEnv("TENANT_CLAIM", "tenant_id"),
+		DefaultTenant:    getEnv("DEFAULT_TENANT", "default"),
+
+		// Pagination
+		PageDefaultSize: getEnvInt("PAGE_DEFAULT_SIZE", 20),
+		PageMaxSize:     getEnvInt("PAGE_MAX_SIZE", 100),
+
+		// Bulk writes
+		BulkMaxItems: getEnvInt("BULK_MAX_ITEMS", 1000),
+
+		// Concurrency control
+		RequireIfMatch: getEnvBool("REQUIRE_IF_MATCH", false),
+
+		// Idempotency
+		IdempotencyTTL:     getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLockTTL: getEnvDuration("IDEMPOTENCY_LOCK_TTL", time.Minute),
+
+		// Trash
+		TrashRetention:     getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
 	}
 }
 
+// GetMySQLDSN returns the MySQL DSN. clientFoundRows makes updates report the
+// rows they matched rather than the rows they changed, which repositories rely
+// on to tell a missing row from an unchanged one.
 func (c *Config) GetMySQLDSN() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&clientFoundRows=true",
 		c.DBUser, c.DBPass, c.DBHost, c.DBPort, c.DBName)
 }
 
@@ -80,3 +205,21 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return fallback
+}
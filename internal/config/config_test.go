@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,11 +15,39 @@ func TestLoadConfig_Defaults(t *testing.T) {
 	os.Unsetenv("DB_HOST")
 	os.Unsetenv("DB_PORT")
 	os.Unsetenv("DB_NAME")
+	os.Unsetenv("SERVER_ADDR")
+	os.Unsetenv("SERVER_READ_HEADER_TIMEOUT")
+	os.Unsetenv("SERVER_READ_TIMEOUT")
+	os.Unsetenv("SERVER_WRITE_TIMEOUT")
+	os.Unsetenv("SERVER_IDLE_TIMEOUT")
+	os.Unsetenv("SERVER_MAX_HEADER_BYTES")
+	os.Unsetenv("TLS_CERT_FILE")
+	os.Unsetenv("TLS_KEY_FILE")
+	os.Unsetenv("TLS_CLIENT_CA_FILE")
+	os.Unsetenv("SHUTDOWN_TIMEOUT")
+	os.Unsetenv("SHUTDOWN_DELAY")
+	os.Unsetenv("HEALTH_CHECK_TIMEOUT")
 	os.Unsetenv("REDIS_HOST")
 	os.Unsetenv("REDIS_PORT")
 	os.Unsetenv("REDIS_PASSWORD")
 	os.Unsetenv("CACHE_DIR")
 	os.Unsetenv("LOGGING_LEVEL")
+	os.Unsetenv("JWT_SECRET")
+	os.Unsetenv("JWT_ISSUER")
+	os.Unsetenv("JWT_AUDIENCE")
+	os.Unsetenv("JWT_LEEWAY")
+	os.Unsetenv("TENANT_HEADER")
+	os.Unsetenv("TENANT_BASE_DOMAIN")
+	os.Unsetenv("TENANT_CLAIM")
+	os.Unsetenv("DEFAULT_TENANT")
+	os.Unsetenv("PAGE_DEFAULT_SIZE")
+	os.Unsetenv("PAGE_MAX_SIZE")
+	os.Unsetenv("BULK_MAX_ITEMS")
+	os.Unsetenv("REQUIRE_IF_MATCH")
+	os.Unsetenv("IDEMPOTENCY_TTL")
+	os.Unsetenv("IDEMPOTENCY_LOCK_TTL")
+	os.Unsetenv("TRASH_RETENTION")
+	os.Unsetenv("TRASH_PURGE_INTERVAL")
 
 	cfg := LoadConfig()
 
@@ -27,11 +56,39 @@ func TestLoadConfig_Defaults(t *testing.T) {
 	assert.Equal(t, "127.0.0.1", cfg.DBHost)
 	assert.Equal(t, "3306", cfg.DBPort)
 	assert.Equal(t, "test", cfg.DBName)
+	assert.Equal(t, ":8080", cfg.ServerAddr)
+	assert.Equal(t, 10*time.Second, cfg.ReadHeaderTimeout)
+	assert.Equal(t, 30*time.Second, cfg.ReadTimeout)
+	assert.Equal(t, 30*time.Second, cfg.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, cfg.IdleTimeout)
+	assert.Equal(t, 1<<20, cfg.MaxHeaderBytes)
+	assert.Equal(t, "", cfg.TLSCertFile)
+	assert.Equal(t, "", cfg.TLSKeyFile)
+	assert.Equal(t, "", cfg.TLSClientCAFile)
+	assert.Equal(t, 30*time.Second, cfg.ShutdownTimeout)
+	assert.Equal(t, 5*time.Second, cfg.ShutdownDelay)
+	assert.Equal(t, 2*time.Second, cfg.HealthCheckTimeout)
 	assert.Equal(t, "127.0.0.1", cfg.RedisHost)
 	assert.Equal(t, "6379", cfg.RedisPort)
 	assert.Equal(t, "", cfg.RedisPassword)
 	assert.Equal(t, ".cache", cfg.CacheDir)
 	assert.Equal(t, 3, cfg.LoggingLevel)
+	assert.Equal(t, "", cfg.JWTSecret)
+	assert.Equal(t, "", cfg.JWTIssuer)
+	assert.Equal(t, "", cfg.JWTAudience)
+	assert.Equal(t, 30*time.Second, cfg.JWTLeeway)
+	assert.Equal(t, "X-Tenant-ID", cfg.TenantHeader)
+	assert.Equal(t, "", cfg.TenantBaseDomain)
+	assert.Equal(t, "tenant_id", cfg.TenantClaim)
+	assert.Equal(t, "default", cfg.DefaultTenant)
+	assert.Equal(t, 20, cfg.PageDefaultSize)
+	assert.Equal(t, 100, cfg.PageMaxSize)
+	assert.Equal(t, 1000, cfg.BulkMaxItems)
+	assert.False(t, cfg.RequireIfMatch)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, time.Minute, cfg.IdempotencyLockTTL)
+	assert.Equal(t, 30*24*time.Hour, cfg.TrashRetention)
+	assert.Equal(t, time.Hour, cfg.TrashPurgeInterval)
 }
 
 func TestLoadConfig_WithEnvVars(t *testing.T) {
@@ -95,7 +152,7 @@ func TestConfig_GetMySQLDSN(t *testing.T) {
 
 	dsn := cfg.GetMySQLDSN()
 
-	expected := "myuser:mypass@tcp(myhost:3306)/mydb?charset=utf8mb4&parseTime=True&loc=Local"
+	expected := "myuser:mypass@tcp(myhost:3306)/mydb?charset=utf8mb4&parseTime=True&loc=Local&clientFoundRows=true"
 	assert.Equal(t, expected, dsn)
 }
 
@@ -142,3 +199,43 @@ func TestGetEnvInt(t *testing.T) {
 	result = getEnvInt("NON_EXISTING_INT", 10)
 	assert.Equal(t, 10, result)
 }
+
+func TestGetEnvDuration(t *testing.T) {
+	// Test with valid duration
+	os.Setenv("TEST_DURATION", "1m30s")
+	defer os.Unsetenv("TEST_DURATION")
+
+	result := getEnvDuration("TEST_DURATION", time.Second)
+	assert.Equal(t, 90*time.Second, result)
+
+	// Test with invalid duration
+	os.Setenv("TEST_INVALID_DURATION", "soon")
+	defer os.Unsetenv("TEST_INVALID_DURATION")
+
+	result = getEnvDuration("TEST_INVALID_DURATION", time.Second)
+	assert.Equal(t, time.Second, result)
+
+	// Test with non-existing env var
+	result = getEnvDuration("NON_EXISTING_DURATION", time.Second)
+	assert.Equal(t, time.Second, result)
+}
+
+func TestGetEnvBool(t *testing.T) {
+	// Test with valid bool
+	os.Setenv("TEST_BOOL", "true")
+	defer os.Unsetenv("TEST_BOOL")
+
+	result := getEnvBool("TEST_BOOL", false)
+	assert.True(t, result)
+
+	// Test with invalid bool
+	os.Setenv("TEST_INVALID_BOOL", "sometimes")
+	defer os.Unsetenv("TEST_INVALID_BOOL")
+
+	result = getEnvBool("TEST_INVALID_BOOL", false)
+	assert.False(t, result)
+
+	// Test with non-existing env var
+	result = getEnvBool("NON_EXISTING_BOOL", true)
+	assert.True(t, result)
+}
@@ -0,0 +1,21 @@
+-- Versions of items and item properties, counting their changes from 1, used for
+-- optimistic concurrency: conditional writes (If-Match) apply only to the version
+-- they expect. Existing rows start at version 1.
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE item_properties ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE item_properties DROP COLUMN version;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- Append-only audit log of the changes made to items and item properties. Events
+-- keep no foreign key, so they outlive the resources they record. created_at keeps
+-- microseconds so that events are listed in the order they were recorded.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS audit_events (
+    id CHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id CHAR(36) NOT NULL,
+    before_values TEXT NULL,
+    after_values TEXT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NULL DEFAULT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_audit_events_resource ON audit_events(tenant_id, resource_type, resource_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_audit_events_created_at ON audit_events(tenant_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- Items created before ownership existed are assigned to LEGACY_ITEM_OWNER
+-- (defaults to "legacy", which only admins can see).
+
+-- +goose Up
+-- +goose ENVSUB ON
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE items SET owner_id = '${LEGACY_ITEM_OWNER:-legacy}' WHERE owner_id = '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_items_owner_id ON items(owner_id);
+-- +goose StatementEnd
+-- +goose ENVSUB OFF
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_items_owner_id ON items;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN owner_id;
+-- +goose StatementEnd
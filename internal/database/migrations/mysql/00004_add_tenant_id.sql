@@ -0,0 +1,47 @@
+-- Rows created before multi-tenancy existed are assigned to DEFAULT_TENANT
+-- (defaults to "default", the tenant of requests that name no tenant).
+-- Item properties belong to the tenant of their item and have no column of their own.
+
+-- +goose Up
+-- +goose ENVSUB ON
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE items SET tenant_id = '${DEFAULT_TENANT:-default}' WHERE tenant_id = '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_items_tenant_id ON items(tenant_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE api_keys SET tenant_id = '${DEFAULT_TENANT:-default}' WHERE tenant_id = '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id);
+-- +goose StatementEnd
+-- +goose ENVSUB OFF
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_api_keys_tenant_id ON api_keys;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_items_tenant_id ON items;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- Full-text indexes used by item search. Each searched column has an index of
+-- its own so that the columns can be weighted separately.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE FULLTEXT INDEX ft_items_title ON items(title);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FULLTEXT INDEX ft_items_description ON items(description);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FULLTEXT INDEX ft_item_properties_value ON item_properties(value);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ft_item_properties_value ON item_properties;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX ft_items_description ON items;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX ft_items_title ON items;
+-- +goose StatementEnd
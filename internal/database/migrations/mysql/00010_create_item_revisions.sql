@@ -0,0 +1,67 @@
+-- Revisions of items: a snapshot of an item and its properties (JSON) stored with every
+-- change to either, for point-in-time reads. History starts with this migration: every
+-- item gets a first revision of its current state, dated by its last update. created_at
+-- keeps microseconds so that revisions stored in the same second are told apart.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS item_revisions (
+    id CHAR(36) PRIMARY KEY,
+    item_id CHAR(36) NOT NULL,
+    revision BIGINT NOT NULL,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
+    owner_id VARCHAR(255) NOT NULL DEFAULT '',
+    snapshot MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP(6) NULL DEFAULT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_item_revisions_item_revision ON item_revisions(item_id, revision);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_item_revisions_created_at ON item_revisions(item_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_item_revisions_tenant_id ON item_revisions(tenant_id);
+-- +goose StatementEnd
+
+-- The snapshot is the item as the application encodes it, with its properties that are
+-- not deleted. Times are converted to UTC from the instant UNIX_TIMESTAMP gives, whatever
+-- the time zone of the session.
+-- +goose StatementBegin
+INSERT INTO item_revisions (id, item_id, revision, tenant_id, owner_id, snapshot, created_at)
+SELECT UUID(), i.id, 1, i.tenant_id, i.owner_id,
+    JSON_OBJECT(
+        'id', i.id,
+        'title', i.title,
+        'description', COALESCE(i.description, ''),
+        'owner_id', i.owner_id,
+        'tenant_id', i.tenant_id,
+        'created_at', DATE_FORMAT(TIMESTAMPADD(SECOND, UNIX_TIMESTAMP(i.created_at), '1970-01-01'), '%Y-%m-%dT%H:%i:%sZ'),
+        'updated_at', DATE_FORMAT(TIMESTAMPADD(SECOND, UNIX_TIMESTAMP(i.updated_at), '1970-01-01'), '%Y-%m-%dT%H:%i:%sZ'),
+        'version', i.version,
+        'deleted_at', DATE_FORMAT(TIMESTAMPADD(SECOND, UNIX_TIMESTAMP(i.deleted_at), '1970-01-01'), '%Y-%m-%dT%H:%i:%sZ'),
+        'item_properties', CAST((
+            SELECT JSON_ARRAYAGG(JSON_OBJECT(
+                'id', p.id,
+                'item_id', p.item_id,
+                'name', p.name,
+                'value', p.value,
+                'version', p.version,
+                'deleted_at', NULL
+            ))
+            FROM (SELECT * FROM item_properties WHERE deleted_at IS NULL ORDER BY id) p
+            WHERE p.item_id = i.id
+        ) AS JSON)
+    ),
+    i.updated_at
+FROM items i;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_revisions;
+-- +goose StatementEnd
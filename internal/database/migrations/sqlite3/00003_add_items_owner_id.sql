@@ -0,0 +1,28 @@
+-- Items created before ownership existed are assigned to LEGACY_ITEM_OWNER
+-- (defaults to "legacy", which only admins can see).
+
+-- SQLite drops an index by its name alone: its DROP INDEX takes no table, unlike MySQL.
+
+-- +goose Up
+-- +goose ENVSUB ON
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN owner_id VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE items SET owner_id = '${LEGACY_ITEM_OWNER:-legacy}' WHERE owner_id = '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_items_owner_id ON items(owner_id);
+-- +goose StatementEnd
+-- +goose ENVSUB OFF
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_items_owner_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN owner_id;
+-- +goose StatementEnd
@@ -0,0 +1,92 @@
+-- FTS5 index used by item search, with one row per item sharing the item's rowid.
+-- Its properties column holds the values of all of the item's properties and is
+-- kept up to date by the triggers below. Requires SQLite built with FTS5, which
+-- go-sqlite3 enables with the sqlite_fts5 build tag.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE VIRTUAL TABLE items_search USING fts5(title, description, properties);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO items_search (rowid, title, description, properties)
+SELECT items.rowid, items.title, COALESCE(items.description, ''),
+       COALESCE((SELECT group_concat(item_properties.value, ' ') FROM item_properties WHERE item_properties.item_id = items.id), '')
+FROM items;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_items_insert AFTER INSERT ON items BEGIN
+    INSERT INTO items_search (rowid, title, description, properties)
+    VALUES (new.rowid, new.title, COALESCE(new.description, ''),
+            COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.id), ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_items_update AFTER UPDATE OF title, description ON items BEGIN
+    UPDATE items_search SET title = new.title, description = COALESCE(new.description, '') WHERE rowid = new.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_items_delete AFTER DELETE ON items BEGIN
+    DELETE FROM items_search WHERE rowid = old.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_insert AFTER INSERT ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_update AFTER UPDATE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_delete AFTER DELETE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_items_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_items_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_items_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS items_search;
+-- +goose StatementEnd
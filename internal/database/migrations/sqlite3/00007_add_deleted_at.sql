@@ -0,0 +1,39 @@
+-- Soft delete of items and item properties: deleting sets deleted_at, which hides
+-- the row from every query but the trash endpoints, until it is restored or purged
+-- (by an admin, or by the retention job once TRASH_RETENTION has passed).
+
+-- Unlike MySQL, SQLite names no table when dropping an index.
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE items ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_items_deleted_at ON items(deleted_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE item_properties ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_item_properties_deleted_at ON item_properties(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_item_properties_deleted_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE item_properties DROP COLUMN deleted_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_items_deleted_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE items DROP COLUMN deleted_at;
+-- +goose StatementEnd
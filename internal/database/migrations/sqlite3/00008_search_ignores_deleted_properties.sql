@@ -0,0 +1,83 @@
+-- The properties column of items_search only holds the values of the properties
+-- that are not soft-deleted. Soft-deleting or restoring a property updates it,
+-- which the update trigger handles like any other change.
+
+-- +goose Up
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_insert AFTER INSERT ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id AND deleted_at IS NULL), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_update AFTER UPDATE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id AND deleted_at IS NULL), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id AND deleted_at IS NULL), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_delete AFTER DELETE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id AND deleted_at IS NULL), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS items_search_properties_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_insert AFTER INSERT ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_update AFTER UPDATE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = new.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER items_search_properties_delete AFTER DELETE ON item_properties BEGIN
+    UPDATE items_search
+    SET properties = COALESCE((SELECT group_concat(value, ' ') FROM item_properties WHERE item_id = old.item_id), '')
+    WHERE rowid = (SELECT rowid FROM items WHERE id = old.item_id);
+END;
+-- +goose StatementEnd
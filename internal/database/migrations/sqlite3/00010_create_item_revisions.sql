@@ -0,0 +1,69 @@
+-- Revisions of items: a snapshot of an item and its properties (JSON) stored with every
+-- change to either, for point-in-time reads. History starts with this migration: every
+-- item gets a first revision of its current state, dated by its last update. created_at
+-- is a plain TIMESTAMP, the only declaration the SQLite driver reads times from: SQLite
+-- keeps the fractional seconds in the text anyway, so that revisions stored in the same
+-- second are told apart.
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS item_revisions (
+    id CHAR(36) PRIMARY KEY,
+    item_id CHAR(36) NOT NULL,
+    revision BIGINT NOT NULL,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
+    owner_id VARCHAR(255) NOT NULL DEFAULT '',
+    snapshot MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP NULL DEFAULT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_item_revisions_item_revision ON item_revisions(item_id, revision);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_item_revisions_created_at ON item_revisions(item_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_item_revisions_tenant_id ON item_revisions(tenant_id);
+-- +goose StatementEnd
+
+-- The snapshot is the item as the application encodes it, with its properties that are
+-- not deleted. Times are stored as text, which strftime turns into UTC RFC 3339.
+-- +goose StatementBegin
+INSERT INTO item_revisions (id, item_id, revision, tenant_id, owner_id, snapshot, created_at)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+        substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+    i.id, 1, i.tenant_id, i.owner_id,
+    json_object(
+        'id', i.id,
+        'title', i.title,
+        'description', COALESCE(i.description, ''),
+        'owner_id', i.owner_id,
+        'tenant_id', i.tenant_id,
+        'created_at', strftime('%Y-%m-%dT%H:%M:%fZ', i.created_at),
+        'updated_at', strftime('%Y-%m-%dT%H:%M:%fZ', i.updated_at),
+        'version', i.version,
+        'deleted_at', strftime('%Y-%m-%dT%H:%M:%fZ', i.deleted_at),
+        'item_properties', json(NULLIF((
+            SELECT json_group_array(json_object(
+                'id', p.id,
+                'item_id', p.item_id,
+                'name', p.name,
+                'value', p.value,
+                'version', p.version,
+                'deleted_at', NULL
+            ))
+            FROM (SELECT * FROM item_properties WHERE item_id = i.id AND deleted_at IS NULL ORDER BY id) p
+        ), '[]'))
+    ),
+    i.updated_at
+FROM items i;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_revisions;
+-- +goose StatementEnd
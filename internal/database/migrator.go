@@ -1,17 +1,61 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
 
 	"github.com/pressly/goose/v3"
 )
 
-//go:embed migrations/*.sql
+// Migrations that cannot be written portably have a version per dialect,
+// kept in migrations/<dialect>, e.g. migrations/mysql.
+//
+//go:embed migrations/*.sql migrations/*/*.sql
 var embedMigrations embed.FS
 
+// migrationsDir is the directory goose reads migrations from
+const migrationsDir = "migrations"
+
+// dialectFS presents the migrations shared by every dialect and the ones of a
+// single dialect as the one directory goose expects.
+type dialectFS struct {
+	fs.FS
+	dialect string
+}
+
+func (d dialectFS) dialectDir() string {
+	return path.Join(migrationsDir, d.dialect)
+}
+
+func (d dialectFS) Open(name string) (fs.File, error) {
+	if dir, file := path.Split(name); path.Clean(dir) == migrationsDir {
+		if f, err := d.FS.Open(path.Join(d.dialectDir(), file)); err == nil {
+			return f, nil
+		}
+	}
+	return d.FS.Open(name)
+}
+
+func (d dialectFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(d.FS, name)
+	if err != nil || path.Clean(name) != migrationsDir {
+		return entries, err
+	}
+	own, err := fs.ReadDir(d.FS, d.dialectDir())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	entries = append(entries, own...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
 // Migrator handles database migrations using goose
 type Migrator struct {
 	db      *sql.DB
@@ -26,13 +70,13 @@ func NewMigrator(db *sql.DB, dialect string) *Migrator {
 
 // Up runs all available migrations
 func (m *Migrator) Up() error {
-	goose.SetBaseFS(embedMigrations)
+	goose.SetBaseFS(dialectFS{FS: embedMigrations, dialect: m.dialect})
 
 	if err := goose.SetDialect(m.dialect); err != nil {
 		return fmt.Errorf("failed to set dialect %s: %w", m.dialect, err)
 	}
 
-	if err := goose.Up(m.db, "migrations"); err != nil {
+	if err := goose.Up(m.db, migrationsDir); err != nil {
 		// ErrNoNextVersion means the database is already up to date - not an error
 		if errors.Is(err, goose.ErrNoNextVersion) {
 			return nil
@@ -45,13 +89,13 @@ func (m *Migrator) Up() error {
 
 // Down rolls back the last migration
 func (m *Migrator) Down() error {
-	goose.SetBaseFS(embedMigrations)
+	goose.SetBaseFS(dialectFS{FS: embedMigrations, dialect: m.dialect})
 
 	if err := goose.SetDialect(m.dialect); err != nil {
 		return fmt.Errorf("failed to set dialect %s: %w", m.dialect, err)
 	}
 
-	if err := goose.Down(m.db, "migrations"); err != nil {
+	if err := goose.Down(m.db, migrationsDir); err != nil {
 		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
 
@@ -60,15 +104,33 @@ func (m *Migrator) Down() error {
 
 // Status prints the status of all migrations
 func (m *Migrator) Status() error {
-	goose.SetBaseFS(embedMigrations)
+	goose.SetBaseFS(dialectFS{FS: embedMigrations, dialect: m.dialect})
 
 	if err := goose.SetDialect(m.dialect); err != nil {
 		return fmt.Errorf("failed to set dialect %s: %w", m.dialect, err)
 	}
 
-	if err := goose.Status(m.db, "migrations"); err != nil {
+	if err := goose.Status(m.db, migrationsDir); err != nil {
 		return fmt.Errorf("failed to get migration status: %w", err)
 	}
 
 	return nil
 }
+
+// HasPending reports whether some migrations are not applied to the database. Unlike the
+// other methods, it leaves the global state of goose alone, so that it can run concurrently.
+func (m *Migrator) HasPending(ctx context.Context) (bool, error) {
+	fsys, err := fs.Sub(dialectFS{FS: embedMigrations, dialect: m.dialect}, migrationsDir)
+	if err != nil {
+		return false, err
+	}
+	provider, err := goose.NewProvider(goose.Dialect(m.dialect), m.db, fsys)
+	if err != nil {
+		return false, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	pending, err := provider.HasPending(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get pending migrations: %w", err)
+	}
+	return pending, nil
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestSQLite returns an in-memory SQLite database, skipping the test when SQLite was
+// built without FTS5, which the migrations need.
+func newTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own
+	sqlDB.SetMaxOpenConns(1)
+
+	var fts5 bool
+	require.NoError(t, db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error)
+	if !fts5 {
+		t.Skip("SQLite was built without FTS5, run the tests with -tags sqlite_fts5")
+	}
+	return sqlDB
+}
+
+func TestMigrator_HasPending(t *testing.T) {
+	migrator := NewMigrator(newTestSQLite(t), "sqlite3")
+	pending, err := migrator.HasPending(context.Background())
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	require.NoError(t, migrator.Up())
+	pending, err = migrator.HasPending(context.Background())
+	require.NoError(t, err)
+	assert.False(t, pending)
+
+	// Rolling back the last migration leaves it pending
+	require.NoError(t, migrator.Down())
+	pending, err = migrator.HasPending(context.Background())
+	require.NoError(t, err)
+	assert.True(t, pending)
+}
+
+func TestMigrator_DownToFirst(t *testing.T) {
+	sqlDB := newTestSQLite(t)
+	migrator := NewMigrator(sqlDB, "sqlite3")
+	require.NoError(t, migrator.Up())
+	latest, err := goose.GetDBVersion(sqlDB)
+	require.NoError(t, err)
+
+	// Every migration rolls back in the dialect, and applies again afterwards
+	for {
+		version, err := goose.GetDBVersion(sqlDB)
+		require.NoError(t, err)
+		if version == 0 {
+			break
+		}
+		require.NoError(t, migrator.Down(), "rolling back version %d", version)
+	}
+	require.NoError(t, migrator.Up())
+	version, err := goose.GetDBVersion(sqlDB)
+	require.NoError(t, err)
+	require.Equal(t, latest, version)
+}
+
+func TestMigrator_RevisionsOfExistingItems(t *testing.T) {
+	sqlDB := newTestSQLite(t)
+	goose.SetBaseFS(dialectFS{FS: embedMigrations, dialect: "sqlite3"})
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.UpTo(sqlDB, migrationsDir, 9))
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{})
+	require.NoError(t, err)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	lamp := &domain.Item{ID: uuid.NewString(), Title: "Lamp", OwnerID: "alice", TenantID: "acme", CreatedAt: &createdAt, UpdatedAt: createdAt.Add(time.Hour), Version: 2}
+	desk := &domain.Item{ID: uuid.NewString(), Title: "Desk", OwnerID: "bob", TenantID: "acme", UpdatedAt: createdAt, Version: 1}
+	require.NoError(t, db.Create(lamp).Error)
+	require.NoError(t, db.Create(desk).Error)
+	color := &domain.ItemProperty{ID: uuid.NewString(), ItemID: lamp.ID, Name: "color", Value: "red", Version: 1}
+	size := &domain.ItemProperty{ID: uuid.NewString(), ItemID: lamp.ID, Name: "size", Value: "large", Version: 1}
+	require.NoError(t, db.Create([]*domain.ItemProperty{color, size}).Error)
+	require.NoError(t, db.Delete(size).Error)
+	require.NoError(t, db.Delete(desk).Error)
+
+	require.NoError(t, NewMigrator(sqlDB, "sqlite3").Up())
+
+	// Every item, deleted or not, gets a first revision of its current state
+	var revisions []*domain.ItemRevision
+	require.NoError(t, db.Order("owner_id").Find(&revisions).Error)
+	require.Len(t, revisions, 2)
+
+	lampRevision := revisions[0]
+	assert.Equal(t, lamp.ID, lampRevision.ItemID)
+	assert.Equal(t, int64(1), lampRevision.Revision)
+	assert.Equal(t, "acme", lampRevision.TenantID)
+	assert.Equal(t, "alice", lampRevision.OwnerID)
+	assert.True(t, lampRevision.CreatedAt.Equal(lamp.UpdatedAt))
+	assert.False(t, lampRevision.Deleted())
+	assert.Equal(t, "Lamp", lampRevision.Item.Title)
+	assert.Equal(t, int64(2), lampRevision.Item.Version)
+	if assert.NotNil(t, lampRevision.Item.CreatedAt) {
+		assert.True(t, lampRevision.Item.CreatedAt.Equal(createdAt))
+	}
+	assert.True(t, lampRevision.Item.UpdatedAt.Equal(lamp.UpdatedAt))
+	// Deleted properties are left out
+	if assert.Len(t, lampRevision.Item.ItemProperties, 1) {
+		assert.Equal(t, color.ID, lampRevision.Item.ItemProperties[0].ID)
+		assert.Equal(t, "red", lampRevision.Item.ItemProperties[0].Value)
+	}
+
+	deskRevision := revisions[1]
+	assert.Equal(t, desk.ID, deskRevision.ItemID)
+	assert.True(t, deskRevision.Deleted())
+	assert.Nil(t, deskRevision.Item.CreatedAt)
+	assert.Empty(t, deskRevision.Item.ItemProperties)
+	_, err = uuid.Parse(deskRevision.ID)
+	assert.NoError(t, err)
+}
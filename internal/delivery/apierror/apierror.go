@@ -0,0 +1,227 @@
+// Package apierror renders errors as JSON:API error documents
+// (https://jsonapi.org/format/#errors), the only error bodies the API sends.
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"gorm.io/gorm"
+)
+
+// Source locates the part of the request an error comes from.
+type Source struct {
+	// Pointer is a JSON pointer to the value in the request document, e.g. "/data/attributes/title".
+	Pointer string `json:"pointer,omitempty"`
+	// Parameter names the query parameter, e.g. "page[size]".
+	Parameter string `json:"parameter,omitempty"`
+	// Header names the request header, e.g. "If-Match".
+	Header string `json:"header,omitempty"`
+}
+
+// Error is a JSON:API error object.
+type Error struct {
+	Status string                 `json:"status"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *Source                `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Document is a JSON:API document carrying errors instead of data.
+type Document struct {
+	Errors []*Error `json:"errors"`
+}
+
+// New returns an error object with the given status, the standard text of the status as title, code and detail.
+func New(status int, code string, detail string) *Error {
+	return &Error{Status: strconv.Itoa(status), Code: code, Title: http.StatusText(status), Detail: detail}
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// StatusCode returns the HTTP status of the error, 500 when it has none.
+func (e *Error) StatusCode() int {
+	if status, err := strconv.Atoi(e.Status); err == nil {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// WithPointer locates the error at a JSON pointer into the request document.
+func (e *Error) WithPointer(pointer string) *Error {
+	e.source().Pointer = pointer
+	return e
+}
+
+// WithParameter locates the error at a query parameter.
+func (e *Error) WithParameter(parameter string) *Error {
+	e.source().Parameter = parameter
+	return e
+}
+
+// WithHeader locates the error at a request header.
+func (e *Error) WithHeader(header string) *Error {
+	e.source().Header = header
+	return e
+}
+
+// WithMeta adds a member to the meta of the error.
+func (e *Error) WithMeta(key string, value interface{}) *Error {
+	if e.Meta == nil {
+		e.Meta = map[string]interface{}{}
+	}
+	e.Meta[key] = value
+	return e
+}
+
+func (e *Error) source() *Source {
+	if e.Source == nil {
+		e.Source = &Source{}
+	}
+	return e.Source
+}
+
+// Unauthorized reports a request without valid credentials.
+func Unauthorized() *Error {
+	return New(http.StatusUnauthorized, "unauthorized", "Authentication is required")
+}
+
+// NotFound reports a resource that does not exist or is not visible to the caller.
+func NotFound(detail string) *Error {
+	return New(http.StatusNotFound, "not_found", detail)
+}
+
+// BadRequest reports a request that cannot be served as sent, e.g. with a malformed path parameter.
+func BadRequest(detail string) *Error {
+	return New(http.StatusBadRequest, "bad_request", detail)
+}
+
+// InvalidParameter reports a query parameter the request cannot be served with.
+// The parameter is named in source.parameter, and in meta.parameter for clients
+// written before error sources were reported.
+func InvalidParameter(parameter string, detail string) *Error {
+	return New(http.StatusBadRequest, "invalid_query_parameter", detail).WithParameter(parameter).WithMeta("parameter", parameter)
+}
+
+// InvalidDocument reports a request document that is not a valid JSON:API document of the expected resource.
+func InvalidDocument(err error) *Error {
+	return New(http.StatusBadRequest, "invalid_document", err.Error()).WithPointer("/data")
+}
+
+// PreconditionRequired reports a conditional request sent without the header it requires.
+func PreconditionRequired(header string) *Error {
+	return New(http.StatusPreconditionRequired, "precondition_required", header+" is required to change the resource").WithHeader(header)
+}
+
+// Internal reports an unexpected failure, without details that could leak internals.
+func Internal() *Error {
+	return New(http.StatusInternalServerError, "internal_error", "An internal error occurred")
+}
+
+// FromError maps err to the error objects describing it, centrally for every handler:
+//   - *Error is used as is;
+//   - domain.ValidationErrors give a 400 error per invalid value, located by its pointer;
+//   - other invalid resources (domain.ErrValidation) give 400;
+//   - missing rows (domain.ErrNotFound, gorm.ErrRecordNotFound) give 404;
+//   - conflicts (domain.ErrConflict, gorm.ErrDuplicatedKey) give 409;
+//   - conditional changes of a resource changed since (domain.ErrPreconditionFailed) give 412;
+//   - invalid cursors give 400 located at page[cursor];
+//   - missing credentials or tenant give 401;
+//   - forbidden operations (domain.ErrForbidden) give 403;
+//   - elements of all-or-nothing bulk writes not applied because others failed (domain.ErrBulkAborted) give 424;
+//   - unavailable storage and timeouts (domain.ErrUnavailable, context.DeadlineExceeded) give 503;
+//   - anything else gives 500, without details that could leak internals.
+func FromError(err error) []*Error {
+	var apiErr *Error
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &apiErr):
+		return []*Error{apiErr}
+	case errors.As(err, &validationErrs) && len(validationErrs) > 0:
+		errs := make([]*Error, 0, len(validationErrs))
+		for _, v := range validationErrs {
+			e := New(http.StatusBadRequest, "validation_failed", v.Message).WithMeta("field", v.Field)
+			if v.Pointer != "" {
+				e.WithPointer(v.Pointer)
+			}
+			errs = append(errs, e)
+		}
+		return errs
+	case errors.Is(err, domain.ErrValidation):
+		return []*Error{New(http.StatusBadRequest, "validation_failed", "The resource is invalid")}
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return []*Error{NotFound("The resource does not exist")}
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, gorm.ErrDuplicatedKey):
+		return []*Error{New(http.StatusConflict, "conflict", "The resource conflicts with an existing one")}
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return []*Error{New(http.StatusPreconditionFailed, "precondition_failed", "The resource has changed since the version the request expects").WithHeader("If-Match")}
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return []*Error{InvalidParameter("page[cursor]", err.Error())}
+	case errors.Is(err, domain.ErrUnauthenticated), errors.Is(err, domain.ErrTenantRequired):
+		return []*Error{Unauthorized()}
+	case errors.Is(err, domain.ErrForbidden):
+		return []*Error{New(http.StatusForbidden, "forbidden", "The operation is not allowed")}
+	case errors.Is(err, domain.ErrBulkAborted):
+		return []*Error{New(http.StatusFailedDependency, "bulk_aborted", "The resource was not written because other resources of the request failed")}
+	case errors.Is(err, context.DeadlineExceeded):
+		return []*Error{New(http.StatusServiceUnavailable, "timeout", "The request timed out")}
+	case errors.Is(err, domain.ErrUnavailable):
+		return []*Error{New(http.StatusServiceUnavailable, "unavailable", "The service is temporarily unavailable")}
+	default:
+		return []*Error{Internal()}
+	}
+}
+
+// Respond writes err, mapped by FromError, as a JSON:API error document and aborts the request.
+// Server errors are logged with the request and its ID first, the document leaving out their cause.
+func Respond(c *gin.Context, err error) {
+	errs := FromError(err)
+	for _, e := range errs {
+		if e.StatusCode() >= http.StatusInternalServerError {
+			requestID := "-"
+			if info, ok := domain.RequestInfoFromContext(c.Request.Context()); ok {
+				requestID = info.ID
+			}
+			log.Printf("Request %s %s %s failed: %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+			break
+		}
+	}
+	Write(c, errs...)
+}
+
+// Status returns the status of a response reporting the errors: their status when
+// they share one, otherwise the most general one: 400 for client errors only, 500 otherwise.
+func Status(errs ...*Error) int {
+	status := http.StatusInternalServerError
+	for i, e := range errs {
+		switch s := e.StatusCode(); {
+		case i == 0:
+			status = s
+		case s == status:
+		case s < 500 && status < 500:
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	return status
+}
+
+// Write writes the error objects as a JSON:API error document and aborts the request,
+// with the status Status gives them.
+func Write(c *gin.Context, errs ...*Error) {
+	c.Header("Content-Type", jsonapi.MediaType)
+	c.AbortWithStatus(Status(errs...))
+	_ = json.NewEncoder(c.Writer).Encode(Document{Errors: errs})
+}
@@ -0,0 +1,146 @@
+package apierror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status string
+		code   string
+	}{
+		{"api error", NotFound("Item not found"), "404", "not_found"},
+		{"domain not found", fmt.Errorf("get item: %w", domain.ErrNotFound), "404", "not_found"},
+		{"record not found", gorm.ErrRecordNotFound, "404", "not_found"},
+		{"duplicate key", gorm.ErrDuplicatedKey, "409", "conflict"},
+		{"domain conflict", fmt.Errorf("%w: %w", domain.ErrConflict, gorm.ErrDuplicatedKey), "409", "conflict"},
+		{"revoked api key", domain.ErrAPIKeyRevoked, "409", "conflict"},
+		{"precondition failed", fmt.Errorf("%w: version 2 is not the current version", domain.ErrPreconditionFailed), "412", "precondition_failed"},
+		{"validation", domain.ErrValidation, "400", "validation_failed"},
+		{"forbidden", domain.ErrForbidden, "403", "forbidden"},
+		{"unavailable", fmt.Errorf("%w: connection refused", domain.ErrUnavailable), "503", "unavailable"},
+		{"bulk aborted", domain.ErrBulkAborted, "424", "bulk_aborted"},
+		{"invalid cursor", domain.ErrInvalidCursor, "400", "invalid_query_parameter"},
+		{"unauthenticated", domain.ErrUnauthenticated, "401", "unauthorized"},
+		{"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), "503", "timeout"},
+		{"unexpected", errors.New("connection refused"), "500", "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := FromError(tt.err)
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.status, errs[0].Status)
+			assert.Equal(t, tt.code, errs[0].Code)
+			assert.NotContains(t, errs[0].Detail, "connection refused")
+		})
+	}
+}
+
+func TestFromError_ValidationErrors(t *testing.T) {
+	errs := FromError(domain.ValidationErrors{
+		{Field: "title", Message: "title is required", Pointer: "/data/attributes/title"},
+		{Field: "item_id", Message: "item_id must be a valid UUID"},
+	})
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "400", errs[0].Status)
+	assert.Equal(t, "validation_failed", errs[0].Code)
+	assert.Equal(t, "title is required", errs[0].Detail)
+	assert.Equal(t, "/data/attributes/title", errs[0].Source.Pointer)
+	assert.Equal(t, "title", errs[0].Meta["field"])
+	// Values outside the request document have no source
+	assert.Nil(t, errs[1].Source)
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []*Error
+		status int
+	}{
+		{"single error", []*Error{NotFound("Item not found")}, http.StatusNotFound},
+		{"shared status", []*Error{New(http.StatusConflict, "a", ""), New(http.StatusConflict, "b", "")}, http.StatusConflict},
+		{"client errors", []*Error{NotFound(""), Unauthorized()}, http.StatusBadRequest},
+		{"server error", []*Error{NotFound(""), Internal()}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			Write(c, tt.errs...)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.True(t, c.IsAborted())
+			assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+			var doc struct {
+				Errors []Error `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			assert.Len(t, doc.Errors, len(tt.errs))
+		})
+	}
+}
+
+func TestRespond_LogsServerErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/items", nil)
+	c.Request = c.Request.WithContext(domain.ContextWithRequestInfo(c.Request.Context(), domain.RequestInfo{ID: "request-1"}))
+
+	Respond(c, fmt.Errorf("reading items: %w", errors.New("disk on fire")))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "disk on fire")
+	assert.Contains(t, buf.String(), "request-1 GET /items")
+	assert.Contains(t, buf.String(), "reading items: disk on fire")
+
+	// Client errors are not logged
+	buf.Reset()
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	Respond(c, domain.ErrNotFound)
+	assert.Empty(t, buf.String())
+}
+
+func TestInvalidParameter(t *testing.T) {
+	e := InvalidParameter("page[size]", "page[size] must be between 1 and 100")
+
+	assert.Equal(t, http.StatusBadRequest, e.StatusCode())
+	assert.Equal(t, "page[size]", e.Source.Parameter)
+	assert.Equal(t, "page[size]", e.Meta["parameter"])
+}
+
+func TestPreconditionRequired(t *testing.T) {
+	e := PreconditionRequired("If-Match")
+
+	assert.Equal(t, http.StatusPreconditionRequired, e.StatusCode())
+	assert.Equal(t, "precondition_required", e.Code)
+	assert.Equal(t, "If-Match", e.Source.Header)
+}
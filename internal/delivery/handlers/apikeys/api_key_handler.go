@@ -0,0 +1,184 @@
+package apikeys
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+)
+
+// isValidUUID checks if a string is a valid UUID v4
+func isValidUUID(u string) bool {
+	_, err := uuid.Parse(u)
+	return err == nil
+}
+
+type APIKeyHandler struct {
+	Service   domain.APIKeyService
+	Validator domain.Validator
+	Logger    logging.Logger
+}
+
+func NewAPIKeyHandler(service domain.APIKeyService, validator domain.Validator, logger logging.Logger) *APIKeyHandler {
+	return &APIKeyHandler{Service: service, Validator: validator, Logger: logger}
+}
+
+// GetAll lists all API keys
+// @Summary      List API keys
+// @Description  List all API keys (admin only). Only prefixes are returned, never the keys themselves.
+// @Tags         api_keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200  {object}  JSONAPIAPIKeyListResponse "API keys"
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/admin/api_keys [get]
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	apiKeys, err := h.Service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := jsonapi.MarshalPayload(c.Writer, apiKeys); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Create issues a new API key
+// @Summary      Issue an API key
+// @Description  Issue a new API key (admin only). The plaintext key is returned in the "key" attribute of this response only.
+// @Tags         api_keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        api_key  body      JSONAPIAPIKey  true  "API key data"
+// @Success      201      {object}  JSONAPIAPIKeyResponse "Issued API key"
+// @Failure      400      {object}  apierror.Document
+// @Failure      401      {object}  apierror.Document
+// @Failure      403      {object}  apierror.Document
+// @Failure      500      {object}  apierror.Document
+// @Router       /v1/admin/api_keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	h.Logger.LogRequest(c)
+
+	apiKey := new(domain.APIKey)
+	if err := jsonapi.UnmarshalPayload(c.Request.Body, apiKey); err != nil {
+		apierror.Write(c, apierror.InvalidDocument(err))
+		return
+	}
+
+	// Default the owner to the caller issuing the key
+	if apiKey.Owner == "" {
+		if principal, ok := domain.PrincipalFromContext(c.Request.Context()); ok {
+			apiKey.Owner = principal.Subject
+		}
+	}
+
+	// Validate the api key using the injected validator
+	if validationErrors := h.Validator.Validate(apiKey); len(validationErrors) > 0 {
+		apierror.Respond(c, validationErrors)
+		return
+	}
+
+	if err := h.Service.IssueAPIKey(c.Request.Context(), apiKey); err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	// The plaintext key is returned once, and kept nowhere (see middleware.IdempotencyMiddleware)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusCreated)
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := jsonapi.MarshalPayload(c.Writer, apiKey); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Rotate replaces the secret of an API key
+// @Summary      Rotate an API key
+// @Description  Replace the secret of an API key (admin only). The previous key stops working immediately; the new plaintext key is returned once.
+// @Tags         api_keys
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "API key ID (UUID format)"
+// @Success      200  {object}  JSONAPIAPIKeyResponse "Rotated API key"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      409  {object}  apierror.Document
+// @Router       /v1/admin/api_keys/{id}/rotate [post]
+func (h *APIKeyHandler) Rotate(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	apiKey, err := h.Service.RotateAPIKey(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyRevoked) {
+			apierror.Write(c, apierror.New(http.StatusConflict, "api_key_revoked", "API key is revoked"))
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("API key not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	// Like the key issued, the new plaintext key is kept nowhere
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := jsonapi.MarshalPayload(c.Writer, apiKey); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Revoke revokes an API key
+// @Summary      Revoke an API key
+// @Description  Revoke an API key (admin only). Revoked keys are kept for auditing but can no longer authenticate.
+// @Tags         api_keys
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "API key ID (UUID format)"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Router       /v1/admin/api_keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	if err := h.Service.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("API key not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,266 @@
+package apikeys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) IssueAPIKey(ctx context.Context, apiKey *domain.APIKey) error {
+	args := m.Called(ctx, apiKey)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RotateAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	args := m.Called(ctx, plaintext)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Principal), args.Error(1)
+}
+
+// MockLogger implements logging.Logger for testing
+type MockLogger struct{}
+
+func (m *MockLogger) Error(format string, args ...interface{}) {}
+func (m *MockLogger) Warn(format string, args ...interface{})  {}
+func (m *MockLogger) Info(format string, args ...interface{})  {}
+func (m *MockLogger) Debug(format string, args ...interface{}) {}
+func (m *MockLogger) LogRequest(c *gin.Context)                {}
+
+const testUUID = "550e8400-e29b-41d4-a716-446655440000"
+
+func newTestHandler(svc domain.APIKeyService) *APIKeyHandler {
+	return NewAPIKeyHandler(svc, validation.NewValidator(), &MockLogger{})
+}
+
+// decodeAttributes returns the attributes of a single-resource JSON:API response
+func decodeAttributes(t *testing.T, body []byte) map[string]interface{} {
+	var payload struct {
+		Data struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return payload.Data.Attributes
+}
+
+func TestAPIKeyHandler_GetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	svc.On("ListAPIKeys", mock.Anything).Return([]*domain.APIKey{
+		{ID: testUUID, Prefix: "0123abcd", KeyHash: "secret-hash", Owner: "billing-service"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/admin/api_keys", nil)
+
+	handler.GetAll(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "0123abcd")
+	assert.NotContains(t, w.Body.String(), "secret-hash")
+	svc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	svc.On("IssueAPIKey", mock.Anything, mock.MatchedBy(func(k *domain.APIKey) bool {
+		return k.Owner == "billing-service"
+	})).Run(func(args mock.Arguments) {
+		k := args.Get(1).(*domain.APIKey)
+		k.ID = testUUID
+		k.Prefix = "0123abcd"
+		k.KeyHash = "secret-hash"
+		k.Key = "ak_0123abcd_plaintext"
+	}).Return(nil)
+
+	body := `{"data":{"type":"api_keys","attributes":{"owner":"billing-service","scopes":["items:read"]}}}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/api_keys", bytes.NewBufferString(body))
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	attributes := decodeAttributes(t, w.Body.Bytes())
+	assert.Equal(t, "ak_0123abcd_plaintext", attributes["key"])
+	assert.NotContains(t, w.Body.String(), "secret-hash")
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	svc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Create_DefaultsOwnerToCaller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	svc.On("IssueAPIKey", mock.Anything, mock.MatchedBy(func(k *domain.APIKey) bool {
+		return k.Owner == "admin-1"
+	})).Return(nil)
+
+	body := `{"data":{"type":"api_keys","attributes":{}}}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/api_keys", bytes.NewBufferString(body))
+	c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), &domain.Principal{Subject: "admin-1"}))
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Create_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	// No owner and no authenticated caller to default it from
+	body := `{"data":{"type":"api_keys","attributes":{}}}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/api_keys", bytes.NewBufferString(body))
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "IssueAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyHandler_Rotate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		rotated        *domain.APIKey
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			id:             testUUID,
+			rotated:        &domain.APIKey{ID: testUUID, Prefix: "89abcdef", Owner: "billing-service", Key: "ak_89abcdef_plaintext"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid UUID",
+			id:             "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not Found",
+			id:             testUUID,
+			err:            domain.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Revoked",
+			id:             testUUID,
+			err:            domain.ErrAPIKeyRevoked,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockAPIKeyService)
+			handler := newTestHandler(svc)
+			if tt.rotated != nil || tt.err != nil {
+				svc.On("RotateAPIKey", mock.Anything, tt.id).Return(tt.rotated, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request, _ = http.NewRequest(http.MethodPost, "/admin/api_keys/"+tt.id+"/rotate", nil)
+
+			handler.Rotate(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.rotated != nil {
+				assert.Equal(t, tt.rotated.Key, decodeAttributes(t, w.Body.Bytes())["key"])
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyHandler_Revoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	svc.On("RevokeAPIKey", mock.Anything, testUUID).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/admin/api_keys/"+testUUID, nil)
+
+	handler.Revoke(c)
+
+	assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	svc.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Revoke_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAPIKeyService)
+	handler := newTestHandler(svc)
+
+	svc.On("RevokeAPIKey", mock.Anything, testUUID).Return(domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/admin/api_keys/"+testUUID, nil)
+
+	handler.Revoke(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
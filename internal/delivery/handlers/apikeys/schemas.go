@@ -0,0 +1,41 @@
+package apikeys
+
+type JSONAPIAPIKey struct {
+	Data JSONAPIAPIKeyRequestData `json:"data"`
+}
+
+type JSONAPIAPIKeyRequestData struct {
+	Type       string                         `json:"type" example:"api_keys"`
+	Attributes JSONAPIAPIKeyRequestAttributes `json:"attributes"`
+}
+
+type JSONAPIAPIKeyRequestAttributes struct {
+	Owner     string   `json:"owner,omitempty" example:"billing-service"`
+	Scopes    []string `json:"scopes" example:"items:read"`
+	ExpiresAt string   `json:"expires_at,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+type JSONAPIAPIKeyData struct {
+	Type       string                  `json:"type" example:"api_keys"`
+	ID         string                  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Attributes JSONAPIAPIKeyAttributes `json:"attributes"`
+}
+
+type JSONAPIAPIKeyAttributes struct {
+	Prefix     string   `json:"prefix" example:"1a2b3c4d"`
+	Owner      string   `json:"owner" example:"billing-service"`
+	Scopes     []string `json:"scopes" example:"items:read"`
+	CreatedAt  string   `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	LastUsedAt string   `json:"last_used_at,omitempty" example:"2024-01-02T00:00:00Z"`
+	ExpiresAt  string   `json:"expires_at,omitempty" example:"2030-01-01T00:00:00Z"`
+	Revoked    bool     `json:"revoked" example:"false"`
+	Key        string   `json:"key,omitempty" example:"ak_1a2b3c4d_c2VjcmV0"`
+}
+
+type JSONAPIAPIKeyResponse struct {
+	Data JSONAPIAPIKeyData `json:"data"`
+}
+
+type JSONAPIAPIKeyListResponse struct {
+	Data []JSONAPIAPIKeyData `json:"data"`
+}
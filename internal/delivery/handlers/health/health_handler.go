@@ -0,0 +1,78 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Statuses of the application reported by the probes
+const (
+	statusAlive   = "alive"
+	statusReady   = "ready"
+	statusUnready = "unready"
+)
+
+// dependencyResponse is the health of a dependency in a readiness response
+type dependencyResponse struct {
+	Status    string  `json:"status"`
+	Backend   string  `json:"backend"`
+	Fallback  bool    `json:"fallback"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// readinessResponse is the body of a readiness response
+type readinessResponse struct {
+	Status       string                        `json:"status"`
+	ShuttingDown bool                          `json:"shutting_down"`
+	Degraded     bool                          `json:"degraded"`
+	Dependencies map[string]dependencyResponse `json:"dependencies"`
+}
+
+// HealthHandler serves the liveness and readiness probes. They are served outside of
+// the API, unauthenticated, and answer in plain JSON for the orchestrators polling them.
+type HealthHandler struct {
+	Service domain.HealthService
+}
+
+func NewHealthHandler(service domain.HealthService) *HealthHandler {
+	return &HealthHandler{Service: service}
+}
+
+// Live reports that the process serves requests, without checking its dependencies,
+// so that an unavailable database never gets the process restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": statusAlive})
+}
+
+// Ready reports whether the application can serve requests, with 503 when a dependency
+// is down or the application is shutting down, and details the health of each dependency.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	readiness := h.Service.Readiness(c.Request.Context())
+
+	response := readinessResponse{
+		Status:       statusReady,
+		ShuttingDown: readiness.ShuttingDown,
+		Degraded:     readiness.Degraded,
+		Dependencies: make(map[string]dependencyResponse, len(readiness.Dependencies)),
+	}
+	for name, dependency := range readiness.Dependencies {
+		response.Dependencies[name] = dependencyResponse{
+			Status:    dependency.Status,
+			Backend:   dependency.Backend,
+			Fallback:  dependency.Fallback,
+			LatencyMs: float64(dependency.Latency) / float64(time.Millisecond),
+		}
+	}
+	status := http.StatusOK
+	if !readiness.Ready {
+		response.Status = statusUnready
+		status = http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, response)
+}
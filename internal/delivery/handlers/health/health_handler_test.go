@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHealthService is a mock implementation of domain.HealthService
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) Readiness(ctx context.Context) *domain.Readiness {
+	args := m.Called(ctx)
+	return args.Get(0).(*domain.Readiness)
+}
+
+func (m *MockHealthService) ShutDown() {
+	m.Called()
+}
+
+// serveProbe serves a request to the probe at path
+func serveProbe(handler *HealthHandler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.GET("/healthz", handler.Live)
+	r.GET("/readyz", handler.Ready)
+
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockHealthService)
+
+	w := serveProbe(NewHealthHandler(svc), "/healthz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"alive"}`, w.Body.String())
+	// Liveness does not check the dependencies
+	svc.AssertNotCalled(t, "Readiness", mock.Anything)
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockHealthService)
+	svc.On("Readiness", mock.Anything).Return(&domain.Readiness{
+		Ready:    true,
+		Degraded: true,
+		Dependencies: map[string]domain.DependencyHealth{
+			"database": {Status: domain.HealthStatusUp, Backend: "sqlite", Fallback: true, Latency: 1500 * time.Microsecond},
+			"cache":    {Status: domain.HealthStatusUp, Backend: "redis", Latency: time.Millisecond},
+		},
+	})
+
+	w := serveProbe(NewHealthHandler(svc), "/readyz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ready",
+		"shutting_down": false,
+		"degraded": true,
+		"dependencies": {
+			"database": {"status": "up", "backend": "sqlite", "fallback": true, "latency_ms": 1.5},
+			"cache": {"status": "up", "backend": "redis", "fallback": false, "latency_ms": 1}
+		}
+	}`, w.Body.String())
+}
+
+func TestHealthHandler_Unready(t *testing.T) {
+	tests := []struct {
+		name      string
+		readiness *domain.Readiness
+	}{
+		{
+			name: "Dependency Down",
+			readiness: &domain.Readiness{Dependencies: map[string]domain.DependencyHealth{
+				"database": {Status: domain.HealthStatusDown, Backend: "mysql"},
+			}},
+		},
+		{
+			name: "Shutting Down",
+			readiness: &domain.Readiness{ShuttingDown: true, Dependencies: map[string]domain.DependencyHealth{
+				"database": {Status: domain.HealthStatusUp, Backend: "mysql"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockHealthService)
+			svc.On("Readiness", mock.Anything).Return(tt.readiness)
+
+			w := serveProbe(NewHealthHandler(svc), "/readyz")
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			var response readinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "unready", response.Status)
+			assert.Equal(t, tt.readiness.ShuttingDown, response.ShuttingDown)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package items
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	Service         domain.AuditService
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+func NewAuditHandler(service domain.AuditService, cfg *config.Config) *AuditHandler {
+	return &AuditHandler{
+		Service:         service,
+		DefaultPageSize: cfg.PageDefaultSize,
+		MaxPageSize:     cfg.PageMaxSize,
+	}
+}
+
+// auditResourceTypes lists the resource types the audit log records changes of.
+var auditResourceTypes = []string{domain.ResourceItems, domain.ResourceItemProperties}
+
+// parseAuditQuery reads the resource, filter and page query parameters of the audit log.
+// resource is a resource type ("items") or one resource ("items/<id>").
+func parseAuditQuery(c *gin.Context, defaultSize int, maxSize int) (domain.AuditQuery, error) {
+	page, err := parsePageRequest(c, defaultSize, maxSize)
+	if err != nil {
+		return domain.AuditQuery{}, err
+	}
+	query := domain.AuditQuery{Page: page}
+
+	if resource, ok := c.GetQuery("resource"); ok {
+		resourceType, resourceID, hasID := strings.Cut(resource, "/")
+		if !slices.Contains(auditResourceTypes, resourceType) || (hasID && !isValidUUID(resourceID)) {
+			return query, invalidParameter("resource", fmt.Sprintf("resource must be a resource type among %s, optionally followed by /<id>", strings.Join(auditResourceTypes, ", ")))
+		}
+		query.ResourceType, query.ResourceID = resourceType, resourceID
+	}
+
+	params := c.Request.URL.Query()
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name == "filter" || strings.HasPrefix(name, "filter[") {
+			names = append(names, name)
+		}
+	}
+	// Report the first invalid filter by name rather than at random
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := params.Get(name)
+		switch name {
+		case "filter[actor]":
+			query.Actor = value
+		case "filter[action]":
+			if !slices.Contains(domain.AuditActions, value) {
+				return query, invalidParameter(name, fmt.Sprintf("filter[action] must be one of %s", strings.Join(domain.AuditActions, ", ")))
+			}
+			query.Action = value
+		case "filter[created_at][gte]":
+			if query.CreatedAt.GTE, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case "filter[created_at][gt]":
+			if query.CreatedAt.GT, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case "filter[created_at][lte]":
+			if query.CreatedAt.LTE, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case "filter[created_at][lt]":
+			if query.CreatedAt.LT, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		default:
+			return query, invalidParameter(name, fmt.Sprintf("Unknown filter %s", name))
+		}
+	}
+
+	return query, nil
+}
+
+// GetAll gets a page of the audit log
+// @Summary      List audit events
+// @Description  get a page of the audit log of the tenant (admin only), most recent first: every change made to items and item properties, with its actor, request and the attributes it changed
+// @Tags         audit
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        resource                 query  string  false  "Resource type (items, item_properties), or one resource as <type>/<id>"
+// @Param        filter[actor]            query  string  false  "Events of the actor (principal subject)"
+// @Param        filter[action]           query  string  false  "Events of the action (create, update, patch, revert, delete, restore, purge)"
+// @Param        filter[created_at][gte]  query  string  false  "Events recorded at or after the time (RFC 3339 or YYYY-MM-DD); gt, lte and lt are supported too"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Success      200  {object}  JSONAPIAuditEventListResponse "Audit events"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/audit [get]
+func (h *AuditHandler) GetAll(c *gin.Context) {
+	query, err := parseAuditQuery(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	events, err := h.Service.ListAuditEvents(c.Request.Context(), query)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := writePage(c, query.Page, events, domain.QueryOptions{}); err != nil {
+		apierror.Respond(c, err)
+	}
+}
@@ -0,0 +1,125 @@
+package items
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditService is a mock implementation of domain.AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.Page[*domain.AuditEvent], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.AuditEvent]), args.Error(1)
+}
+
+func TestParseAuditQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected domain.AuditQuery
+		invalid  string
+	}{
+		{"none", "", domain.AuditQuery{}, ""},
+		{"resource type", "resource=items", domain.AuditQuery{ResourceType: domain.ResourceItems}, ""},
+		{"resource", "resource=items/" + itemID, domain.AuditQuery{ResourceType: domain.ResourceItems, ResourceID: itemID}, ""},
+		{"filters", "filter[actor]=user-1&filter[action]=patch&filter[created_at][gte]=2026-03-01",
+			domain.AuditQuery{Actor: "user-1", Action: domain.AuditActionPatch, CreatedAt: domain.TimeRange{GTE: &since}}, ""},
+		{"unknown resource type", "resource=api_keys", domain.AuditQuery{}, "resource"},
+		{"invalid resource ID", "resource=items/42", domain.AuditQuery{}, "resource"},
+		{"unknown action", "filter[action]=read", domain.AuditQuery{}, "filter[action]"},
+		{"unknown filter", "filter[title]=Lamp", domain.AuditQuery{}, "filter[title]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/audit?"+tt.query, nil)
+
+			query, err := parseAuditQuery(c, 20, 100)
+
+			if tt.invalid != "" {
+				assert.ErrorContains(t, err, tt.invalid)
+				return
+			}
+			require.NoError(t, err)
+			tt.expected.Page = domain.PageRequest{Size: 20}
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestAuditHandler_GetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAuditService)
+	svc.On("ListAuditEvents", mock.Anything, domain.AuditQuery{ResourceType: domain.ResourceItems, Page: domain.PageRequest{Size: 20}}).
+		Return(&domain.Page[*domain.AuditEvent]{
+			Items: []*domain.AuditEvent{{
+				ID: "0f8fad5b-d9cb-469f-a165-70867728950e", Actor: "user-1", Action: domain.AuditActionPatch,
+				ResourceType: domain.ResourceItems, ResourceID: "550e8400-e29b-41d4-a716-446655440000",
+				Before: map[string]any{"title": "Lamp"}, After: map[string]any{"title": "Desk lamp"},
+				RequestID: "req-42", IP: "203.0.113.7", CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			}},
+			Total: 1,
+		}, nil)
+	handler := NewAuditHandler(svc, newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/audit?resource=items", nil)
+
+	handler.GetAll(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Data []struct {
+			Type       string         `json:"type"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+		Meta map[string]any `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	if assert.Len(t, doc.Data, 1) {
+		assert.Equal(t, "audit_events", doc.Data[0].Type)
+		assert.Equal(t, "patch", doc.Data[0].Attributes["action"])
+		assert.Equal(t, map[string]any{"title": "Lamp"}, doc.Data[0].Attributes["before"])
+		assert.Equal(t, map[string]any{"title": "Desk lamp"}, doc.Data[0].Attributes["after"])
+		assert.Equal(t, "req-42", doc.Data[0].Attributes["request_id"])
+	}
+	assert.Equal(t, float64(1), doc.Meta["total"])
+}
+
+func TestAuditHandler_GetAll_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockAuditService)
+	handler := NewAuditHandler(svc, newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/audit?resource=users", nil)
+
+	handler.GetAll(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "resource", decodeErrors(t, w)[0].Source.Parameter)
+	svc.AssertNotCalled(t, "ListAuditEvents", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,373 @@
+package items
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+)
+
+// bulkResult is the outcome of one resource of a bulk request: its status, and either
+// the resource as stored or the errors that kept it from being written.
+type bulkResult struct {
+	Status string            `json:"status"`
+	Data   *jsonapi.Node     `json:"data,omitempty"`
+	Errors []*apierror.Error `json:"errors,omitempty"`
+}
+
+// bulkDocument is the response document of a bulk request, with the result of each
+// resource of the request in meta.results, in the same order.
+type bulkDocument struct {
+	Meta struct {
+		Results []bulkResult `json:"results"`
+	} `json:"meta"`
+}
+
+// bulkWriter writes the items of a bulk request, in the given mode (see domain.ItemService).
+type bulkWriter func(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error)
+
+// BulkCreate creates many items at once
+// @Summary      Create items in bulk
+// @Description  create the items of an array of resources, without properties. The whole array is written unless mode is best_effort; each resource gets a result in meta.results, in the order of the request, with the created item or its errors, pointing into the request document. The response is 200 when every item was created, 207 when some were not in best_effort mode, and otherwise carries the status of the errors
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode   query     string           false  "all_or_nothing (default) or best_effort"
+// @Param        items  body      JSONAPIBulkItems true   "Items to create"
+// @Success      200    {object}  JSONAPIBulkResponse "Results"
+// @Success      207    {object}  JSONAPIBulkResponse "Results, some failed"
+// @Failure      400    {object}  JSONAPIBulkResponse
+// @Failure      401    {object}  apierror.Document
+// @Failure      403    {object}  apierror.Document
+// @Failure      413    {object}  apierror.Document
+// @Failure      500    {object}  apierror.Document
+// @Router       /v1/items/bulk [post]
+func (h *ItemHandler) BulkCreate(c *gin.Context) {
+	principal, ok := domain.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		apierror.Write(c, apierror.Unauthorized())
+		return
+	}
+	now := time.Now()
+
+	h.bulkWrite(c, http.StatusCreated, h.Service.CreateItems, func(item *domain.Item) error {
+		// Like Create: new IDs, owned by the caller; properties are created on their own
+		item.ID = uuid.New().String()
+		item.OwnerID = principal.Subject
+		item.CreatedAt = &now
+		item.ItemProperties = nil
+		return nil
+	})
+}
+
+// BulkUpdate updates many items at once
+// @Summary      Update items in bulk
+// @Description  replace the title and description of the items of an array of resources, each identified by its id and, optionally, only at the version in its meta.version (required when REQUIRE_IF_MATCH is set). Results and modes are those of the bulk creation; each updated item carries its new version in meta.version
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode   query     string           false  "all_or_nothing (default) or best_effort"
+// @Param        items  body      JSONAPIBulkItems true   "Items to update"
+// @Success      200    {object}  JSONAPIBulkResponse "Results"
+// @Success      207    {object}  JSONAPIBulkResponse "Results, some failed"
+// @Failure      400    {object}  JSONAPIBulkResponse
+// @Failure      401    {object}  apierror.Document
+// @Failure      403    {object}  apierror.Document
+// @Failure      404    {object}  JSONAPIBulkResponse
+// @Failure      412    {object}  JSONAPIBulkResponse
+// @Failure      413    {object}  apierror.Document
+// @Failure      428    {object}  JSONAPIBulkResponse
+// @Failure      500    {object}  apierror.Document
+// @Router       /v1/items/bulk [put]
+func (h *ItemHandler) BulkUpdate(c *gin.Context) {
+	h.bulkWrite(c, http.StatusOK, h.Service.UpdateItems, func(item *domain.Item) error {
+		if err := h.bulkTarget(item); err != nil {
+			return err
+		}
+		item.ItemProperties = nil
+		return nil
+	})
+}
+
+// BulkDelete deletes many items at once
+// @Summary      Delete items in bulk
+// @Description  soft-delete the items of an array of resource identifiers, each optionally only at the version in its meta.version (required when REQUIRE_IF_MATCH is set). Results and modes are those of the bulk creation; deleted items have a 204 result without data
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        mode   query     string           false  "all_or_nothing (default) or best_effort"
+// @Param        items  body      JSONAPIBulkItems true   "Items to delete; attributes are ignored"
+// @Success      200    {object}  JSONAPIBulkResponse "Results"
+// @Success      207    {object}  JSONAPIBulkResponse "Results, some failed"
+// @Failure      400    {object}  JSONAPIBulkResponse
+// @Failure      401    {object}  apierror.Document
+// @Failure      403    {object}  apierror.Document
+// @Failure      404    {object}  JSONAPIBulkResponse
+// @Failure      412    {object}  JSONAPIBulkResponse
+// @Failure      413    {object}  apierror.Document
+// @Failure      428    {object}  JSONAPIBulkResponse
+// @Failure      500    {object}  apierror.Document
+// @Router       /v1/items/bulk [delete]
+func (h *ItemHandler) BulkDelete(c *gin.Context) {
+	h.bulkWrite(c, http.StatusNoContent, h.Service.DeleteItems, func(item *domain.Item) error {
+		if err := h.bulkTarget(item); err != nil {
+			return err
+		}
+		*item = domain.Item{ID: item.ID, Version: item.Version}
+		return nil
+	})
+}
+
+// bulkTarget checks the identifier and version of an item of a bulk update or deletion.
+func (h *ItemHandler) bulkTarget(item *domain.Item) error {
+	if !isValidUUID(item.ID) {
+		return apierror.BadRequest("Invalid UUID format").WithPointer("/data/id")
+	}
+	if item.Version == 0 && h.RequireIfMatch {
+		return versionRequired("/data/meta/version")
+	}
+	return nil
+}
+
+// versionRequired reports a change sent without the meta.version at pointer while
+// RequireIfMatch is set, as a missing If-Match header would be.
+func versionRequired(pointer string) *apierror.Error {
+	return apierror.New(http.StatusPreconditionRequired, "precondition_required", "meta.version is required to change the resource").
+		WithPointer(pointer)
+}
+
+// metaVersion reads the version in the meta.version of a resource, at pointer in the
+// request document: a positive integer, or 0 when meta has no version.
+func metaVersion(meta map[string]json.RawMessage, pointer string) (int64, error) {
+	raw, ok := meta["version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int64
+	if err := json.Unmarshal(raw, &version); err != nil || version < 1 {
+		return 0, apierror.InvalidDocument(errors.New("meta.version must be a positive integer")).WithPointer(pointer)
+	}
+	return version, nil
+}
+
+// bulkWrite serves a bulk request: it decodes the resources of the request document,
+// prepares the item of each one, writes those that are ready with write, and responds
+// with the result of every resource, success when it was written. Resources that fail
+// before they are written fail the whole request, unless its mode is best effort.
+func (h *ItemHandler) bulkWrite(c *gin.Context, success int, write bulkWriter, prepare func(item *domain.Item) error) {
+	h.Logger.LogRequest(c)
+
+	mode, err := parseBulkMode(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	items, errs, err := decodeBulk(c, h.BulkMaxItems)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	ready := make([]*domain.Item, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		if errs[i] == nil {
+			errs[i] = prepare(item)
+		}
+		if errs[i] == nil {
+			ready = append(ready, item)
+			indexes = append(indexes, i)
+		}
+	}
+
+	switch {
+	case mode == domain.BulkAllOrNothing && len(ready) < len(items):
+		for _, i := range indexes {
+			errs[i] = domain.ErrBulkAborted
+		}
+	case len(ready) > 0:
+		written, err := write(c.Request.Context(), ready, mode)
+		if err != nil {
+			apierror.Respond(c, err)
+			return
+		}
+		for j, err := range written {
+			errs[indexes[j]] = err
+		}
+	}
+
+	if err := writeBulkResults(c, mode, success, items, errs); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// parseBulkMode reads the mode query parameter of a bulk request, all_or_nothing by default.
+func parseBulkMode(c *gin.Context) (domain.BulkMode, error) {
+	switch mode := domain.BulkMode(c.Query("mode")); mode {
+	case "":
+		return domain.BulkAllOrNothing, nil
+	case domain.BulkAllOrNothing, domain.BulkBestEffort:
+		return mode, nil
+	default:
+		return "", apierror.InvalidParameter("mode", fmt.Sprintf("mode must be %s or %s", domain.BulkAllOrNothing, domain.BulkBestEffort))
+	}
+}
+
+// decodeBulk reads the primary data of a bulk request document, an array of at most
+// maxItems resources, into items, with the version in the meta.version of each. Every
+// resource is decoded on its own, so that an invalid one only fails itself: the error
+// of each is returned, indexed like items, and the error of the document as a whole.
+func decodeBulk(c *gin.Context, maxItems int) ([]*domain.Item, []error, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, nil, apierror.InvalidDocument(err)
+	}
+	var doc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, nil, apierror.InvalidDocument(err)
+	}
+	var resources []json.RawMessage
+	if err := json.Unmarshal(doc.Data, &resources); err != nil || len(resources) == 0 {
+		return nil, nil, apierror.InvalidDocument(errors.New("the primary data must be a non-empty array of resources"))
+	}
+	if len(resources) > maxItems {
+		return nil, nil, apierror.New(http.StatusRequestEntityTooLarge, "too_many_resources",
+			fmt.Sprintf("A bulk request may not have more than %d resources", maxItems)).WithPointer("/data")
+	}
+
+	items := make([]*domain.Item, len(resources))
+	errs := make([]error, len(resources))
+	for i, resource := range resources {
+		items[i] = new(domain.Item)
+		var envelope struct {
+			Meta map[string]json.RawMessage `json:"meta"`
+		}
+		if err := json.Unmarshal(resource, &envelope); err != nil {
+			errs[i] = apierror.InvalidDocument(err)
+			continue
+		}
+		version, err := metaVersion(envelope.Meta, "/data/meta/version")
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		payload, _ := json.Marshal(map[string]json.RawMessage{"data": resource})
+		if err := jsonapi.UnmarshalPayload(bytes.NewReader(payload), items[i]); err != nil {
+			errs[i] = apierror.InvalidDocument(err)
+			continue
+		}
+		items[i].Version = version
+	}
+	return items, errs, nil
+}
+
+// writeBulkResults writes the result of each item of a bulk request: success for those
+// with no error in errs, with the stored item unless success is 204, and the errors
+// of the others, pointing into the request document. The response is 200 when every
+// item succeeded, 207 when some failed in best effort mode, and otherwise has the status
+// of the errors that aborted the request.
+func writeBulkResults(c *gin.Context, mode domain.BulkMode, success int, items []*domain.Item, errs []error) error {
+	var doc bulkDocument
+	doc.Meta.Results = make([]bulkResult, len(items))
+	var failures []*apierror.Error
+	for i, item := range items {
+		result := &doc.Meta.Results[i]
+		if errs[i] != nil {
+			result.Errors = bulkErrors(errs[i], i)
+			result.Status = strconv.Itoa(apierror.Status(result.Errors...))
+			if !errors.Is(errs[i], domain.ErrBulkAborted) {
+				failures = append(failures, result.Errors...)
+			}
+			continue
+		}
+
+		result.Status = strconv.Itoa(success)
+		if success == http.StatusNoContent {
+			continue
+		}
+		node, err := versionedNode(item, item.Version)
+		if err != nil {
+			return err
+		}
+		result.Data = node
+	}
+
+	status := http.StatusOK
+	switch {
+	case len(failures) == 0:
+	case mode == domain.BulkBestEffort:
+		status = http.StatusMultiStatus
+	default:
+		status = apierror.Status(failures...)
+	}
+	c.Header("Content-Type", jsonapi.MediaType)
+	c.Status(status)
+	return json.NewEncoder(c.Writer).Encode(doc)
+}
+
+// versionedNode returns the resource object of model, with its version in meta.version.
+func versionedNode(model interface{}, version int64) (*jsonapi.Node, error) {
+	payload, err := jsonapi.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	one, ok := payload.(*jsonapi.OnePayload)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payload type %T", payload)
+	}
+	one.Data.Meta = &jsonapi.Meta{"version": version}
+	return one.Data, nil
+}
+
+// bulkErrors maps the error of the resource at the given index of a bulk request to
+// error objects located in that resource (see locateErrors). A version mismatch, which
+// If-Match would otherwise report, points to the version of the resource.
+func bulkErrors(err error, index int) []*apierror.Error {
+	resource := fmt.Sprintf("/data/%d", index)
+	return locateErrors(err, resource, resource+"/meta/version", resource)
+}
+
+// locateErrors maps err to error objects, like apierror.FromError, located in a resource
+// of the request document: pointers into the primary data point into data instead,
+// version mismatches point to version, and errors located elsewhere to the resource.
+func locateErrors(err error, data string, version string, resource string) []*apierror.Error {
+	errs := apierror.FromError(err)
+	for i, e := range errs {
+		located := *e
+		pointer := ""
+		if e.Source != nil {
+			pointer = e.Source.Pointer
+		}
+		switch rest, ok := strings.CutPrefix(pointer, "/data"); {
+		case ok:
+			pointer = data + rest
+		case errors.Is(err, domain.ErrPreconditionFailed):
+			pointer = version
+		default:
+			pointer = resource
+		}
+		located.Source = &apierror.Source{Pointer: pointer}
+		errs[i] = &located
+	}
+	return errs
+}
@@ -0,0 +1,228 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// decodedBulkResult is a result of a bulk response, as clients read it
+type decodedBulkResult struct {
+	Status string `json:"status"`
+	Data   *struct {
+		Type       string         `json:"type"`
+		ID         string         `json:"id"`
+		Attributes map[string]any `json:"attributes"`
+		Meta       map[string]any `json:"meta"`
+	} `json:"data"`
+	Errors []apierror.Error `json:"errors"`
+}
+
+// serveBulk sends body to a bulk endpoint of handler, as user-1, with the given query
+func serveBulk(handler gin.HandlerFunc, method string, query string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(method, "/items/bulk"+query, strings.NewReader(body))
+	c.Request = withPrincipal(c.Request, "user-1")
+	handler(c)
+	return w
+}
+
+// decodeBulkResults returns the results of a bulk response
+func decodeBulkResults(t *testing.T, w *httptest.ResponseRecorder) []decodedBulkResult {
+	var doc struct {
+		Meta struct {
+			Results []decodedBulkResult `json:"results"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	return doc.Meta.Results
+}
+
+func TestItemHandler_BulkCreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	var created []*domain.Item
+	svc.On("CreateItems", mock.Anything, mock.Anything, domain.BulkAllOrNothing).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]*domain.Item)
+		for _, item := range created {
+			item.Version = 1
+		}
+	}).Return([]error{nil, nil}, nil)
+
+	w := serveBulk(handler.BulkCreate, http.MethodPost, "", `{"data": [
+		{"type": "items", "id": "mine", "attributes": {"title": "Lamp", "owner_id": "user-2"}},
+		{"type": "items", "attributes": {"title": "Desk"}}
+	]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, created, 2)
+	for _, item := range created {
+		assert.True(t, isValidUUID(item.ID))
+		assert.Equal(t, "user-1", item.OwnerID)
+		assert.NotNil(t, item.CreatedAt)
+	}
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 2)
+	assert.Equal(t, "201", results[0].Status)
+	require.NotNil(t, results[0].Data)
+	assert.Equal(t, created[0].ID, results[0].Data.ID)
+	assert.Equal(t, "Lamp", results[0].Data.Attributes["title"])
+	assert.Equal(t, float64(1), results[0].Data.Meta["version"])
+	assert.Equal(t, "Desk", results[1].Data.Attributes["title"])
+}
+
+func TestItemHandler_BulkCreate_AllOrNothing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := serveBulk(handler.BulkCreate, http.MethodPost, "", `{"data": [
+		{"type": "items", "attributes": {"title": "Lamp"}},
+		{"type": "lamps", "attributes": {"title": "Desk"}}
+	]}`)
+
+	// Nothing is written, with the status of the resource that failed
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "CreateItems", mock.Anything, mock.Anything, mock.Anything)
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 2)
+	assert.Equal(t, "424", results[0].Status)
+	assert.Equal(t, "bulk_aborted", results[0].Errors[0].Code)
+	assert.Equal(t, "400", results[1].Status)
+	assert.Equal(t, "/data/1", results[1].Errors[0].Source.Pointer)
+}
+
+func TestItemHandler_BulkCreate_BestEffort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	invalid := domain.ValidationErrors{{Field: "Title", Message: "Title is required", Pointer: "/data/attributes/title"}}
+	svc.On("CreateItems", mock.Anything, mock.MatchedBy(func(items []*domain.Item) bool { return len(items) == 2 }), domain.BulkBestEffort).
+		Return([]error{invalid, nil}, nil)
+
+	w := serveBulk(handler.BulkCreate, http.MethodPost, "?mode=best_effort", `{"data": [
+		{"type": "items", "attributes": {"title": ""}},
+		{"type": "lamps", "attributes": {"title": "Desk"}},
+		{"type": "items", "attributes": {"title": "Chair"}}
+	]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	svc.AssertExpectations(t)
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 3)
+	// Errors point into the resource they belong to
+	assert.Equal(t, "400", results[0].Status)
+	assert.Equal(t, "/data/0/attributes/title", results[0].Errors[0].Source.Pointer)
+	assert.Equal(t, "400", results[1].Status)
+	assert.Equal(t, "201", results[2].Status)
+	assert.Equal(t, "Chair", results[2].Data.Attributes["title"])
+}
+
+func TestItemHandler_BulkUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	var updated []*domain.Item
+	svc.On("UpdateItems", mock.Anything, mock.Anything, domain.BulkBestEffort).Run(func(args mock.Arguments) {
+		updated = args.Get(1).([]*domain.Item)
+	}).Return([]error{domain.ErrPreconditionFailed}, nil)
+
+	w := serveBulk(handler.BulkUpdate, http.MethodPut, "?mode=best_effort", `{"data": [
+		{"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "attributes": {"title": "Lamp"}, "meta": {"version": 3}},
+		{"type": "items", "id": "not-a-uuid", "attributes": {"title": "Desk"}},
+		{"type": "items", "id": "550e8400-e29b-41d4-a716-446655440001", "attributes": {"title": "Desk"}, "meta": {"version": "3"}}
+	]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440000", updated[0].ID)
+	assert.Equal(t, int64(3), updated[0].Version)
+
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 3)
+	// A version mismatch points to the version of the resource, not to If-Match
+	assert.Equal(t, "412", results[0].Status)
+	assert.Equal(t, "/data/0/meta/version", results[0].Errors[0].Source.Pointer)
+	assert.Empty(t, results[0].Errors[0].Source.Header)
+	assert.Equal(t, "/data/1/id", results[1].Errors[0].Source.Pointer)
+	assert.Equal(t, "/data/2/meta/version", results[2].Errors[0].Source.Pointer)
+}
+
+func TestItemHandler_BulkUpdate_RequireIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := newTestConfig()
+	cfg.RequireIfMatch = true
+	handler := NewItemHandler(new(MockItemService), newTestValidator(), newTestLogger(), cfg)
+
+	w := serveBulk(handler.BulkUpdate, http.MethodPut, "", `{"data": [
+		{"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "attributes": {"title": "Lamp"}}
+	]}`)
+
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 1)
+	assert.Equal(t, "/data/0/meta/version", results[0].Errors[0].Source.Pointer)
+}
+
+func TestItemHandler_BulkDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	expected := []*domain.Item{{ID: "550e8400-e29b-41d4-a716-446655440000", Version: 2}}
+	svc.On("DeleteItems", mock.Anything, expected, domain.BulkAllOrNothing).Return([]error{nil}, nil)
+
+	w := serveBulk(handler.BulkDelete, http.MethodDelete, "", `{"data": [
+		{"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "attributes": {"title": "Ignored"}, "meta": {"version": 2}}
+	]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+	results := decodeBulkResults(t, w)
+	require.Len(t, results, 1)
+	assert.Equal(t, "204", results[0].Status)
+	assert.Nil(t, results[0].Data)
+}
+
+func TestItemHandler_Bulk_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemHandler(new(MockItemService), newTestValidator(), newTestLogger(), newTestConfig())
+	handler.BulkMaxItems = 2
+
+	tests := []struct {
+		name   string
+		query  string
+		body   string
+		status int
+		source apierror.Source
+	}{
+		{"unknown mode", "?mode=sometimes", `{"data": [{"type": "items"}]}`, http.StatusBadRequest, apierror.Source{Parameter: "mode"}},
+		{"single resource", "", `{"data": {"type": "items"}}`, http.StatusBadRequest, apierror.Source{Pointer: "/data"}},
+		{"no resources", "", `{"data": []}`, http.StatusBadRequest, apierror.Source{Pointer: "/data"}},
+		{"too many resources", "", `{"data": [{"type": "items"}, {"type": "items"}, {"type": "items"}]}`, http.StatusRequestEntityTooLarge, apierror.Source{Pointer: "/data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveBulk(handler.BulkCreate, http.MethodPost, tt.query, tt.body)
+
+			assert.Equal(t, tt.status, w.Code)
+			errs := decodeErrors(t, w)
+			require.NotNil(t, errs[0].Source)
+			assert.Equal(t, tt.source, *errs[0].Source)
+		})
+	}
+}
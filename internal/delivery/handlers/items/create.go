@@ -0,0 +1,99 @@
+package items
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// resourceIdentifier identifies a resource in a JSON:API document.
+type resourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// decodeNewItem reads the JSON:API document of a new item into an item, along with the
+// properties to create with it: those its item_properties relationship links to, whose
+// attributes are those of the resources of the same type and ID in included. The IDs
+// only link the relationship to the included resources; the properties are stored
+// under IDs of their own. It returns the index in included of each property.
+func decodeNewItem(c *gin.Context) (*domain.Item, []int, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, nil, apierror.InvalidDocument(err)
+	}
+
+	var doc struct {
+		Data *struct {
+			Relationships map[string]struct {
+				Data json.RawMessage `json:"data"`
+			} `json:"relationships"`
+		} `json:"data"`
+		Included []resourceIdentifier `json:"included"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, nil, apierror.InvalidDocument(err)
+	}
+	if doc.Data == nil {
+		return nil, nil, apierror.InvalidDocument(errors.New("the document has no primary data"))
+	}
+
+	// Every linked property must be included, and every included resource linked
+	var linked []resourceIdentifier
+	if relationship, ok := doc.Data.Relationships[domain.ResourceItemProperties]; ok {
+		if err := json.Unmarshal(relationship.Data, &linked); err != nil {
+			return nil, nil, invalidLinkage("/data/relationships/item_properties/data", "item_properties must link to an array of item_properties")
+		}
+	}
+	includedAt := make(map[resourceIdentifier]int, len(doc.Included))
+	for i, resource := range doc.Included {
+		if _, ok := includedAt[resource]; ok || resource.Type != domain.ResourceItemProperties || resource.ID == "" {
+			return nil, nil, invalidLinkage(fmt.Sprintf("/included/%d", i), "included resources must be item_properties with distinct IDs")
+		}
+		includedAt[resource] = i
+	}
+	indexes := make([]int, len(linked))
+	for i, identifier := range linked {
+		index, ok := includedAt[identifier]
+		if !ok {
+			return nil, nil, invalidLinkage(fmt.Sprintf("/data/relationships/item_properties/data/%d", i), "linked item properties must be included")
+		}
+		indexes[i] = index
+		delete(includedAt, identifier)
+	}
+	for i, resource := range doc.Included {
+		if _, ok := includedAt[resource]; ok {
+			return nil, nil, invalidLinkage(fmt.Sprintf("/included/%d", i), "included resources must be linked from the primary data")
+		}
+	}
+
+	item := new(domain.Item)
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), item); err != nil {
+		return nil, nil, apierror.InvalidDocument(err)
+	}
+	return item, indexes, nil
+}
+
+// invalidLinkage reports a relationship that does not match the included resources.
+func invalidLinkage(pointer string, detail string) *apierror.Error {
+	return apierror.InvalidDocument(errors.New(detail)).WithPointer(pointer)
+}
+
+// includedValidationErrors locates the validation errors of the resource at the given
+// index in included, rather than in the primary data.
+func includedValidationErrors(errs domain.ValidationErrors, index int) domain.ValidationErrors {
+	for i := range errs {
+		if rest, ok := strings.CutPrefix(errs[i].Pointer, "/data/"); ok {
+			errs[i].Pointer = fmt.Sprintf("/included/%d/%s", index, rest)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,138 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemHandler_Create_WithProperties(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	var created *domain.Item
+	svc.On("CreateItem", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*domain.Item)
+	}).Return(nil)
+
+	body := `{"data": {"type": "items", "attributes": {"title": "Lamp"},
+		"relationships": {"item_properties": {"data": [{"type": "item_properties", "id": "b"}, {"type": "item_properties", "id": "a"}]}}},
+		"included": [
+			{"type": "item_properties", "id": "a", "attributes": {"name": "size", "value": "small"}},
+			{"type": "item_properties", "id": "b", "attributes": {"name": "color", "value": "red"}}
+		]}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	c.Request = withPrincipal(c.Request, "user-1")
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.NotNil(t, created)
+	require.Len(t, created.ItemProperties, 2)
+	// In the order of the relationship, with the attributes of the included resources
+	assert.Equal(t, "color", created.ItemProperties[0].Name)
+	assert.Equal(t, "red", created.ItemProperties[0].Value)
+	assert.Equal(t, "size", created.ItemProperties[1].Name)
+	// The IDs of the document only link the two
+	for _, property := range created.ItemProperties {
+		assert.True(t, isValidUUID(property.ID))
+	}
+
+	var doc struct {
+		Included []struct {
+			Type string `json:"type"`
+		} `json:"included"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Len(t, doc.Included, 2)
+}
+
+func TestItemHandler_Create_InvalidLinkage(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		pointer string
+	}{
+		{
+			name: "linked property not included",
+			body: `{"data": {"type": "items", "attributes": {"title": "Lamp"},
+				"relationships": {"item_properties": {"data": [{"type": "item_properties", "id": "a"}]}}}}`,
+			pointer: "/data/relationships/item_properties/data/0",
+		},
+		{
+			name: "included property not linked",
+			body: `{"data": {"type": "items", "attributes": {"title": "Lamp"}},
+				"included": [{"type": "item_properties", "id": "a", "attributes": {"name": "size", "value": "small"}}]}`,
+			pointer: "/included/0",
+		},
+		{
+			name: "included resource of another type",
+			body: `{"data": {"type": "items", "attributes": {"title": "Lamp"}},
+				"included": [{"type": "items", "id": "a", "attributes": {"title": "Desk"}}]}`,
+			pointer: "/included/0",
+		},
+		{
+			name: "relationship to one resource",
+			body: `{"data": {"type": "items", "attributes": {"title": "Lamp"},
+				"relationships": {"item_properties": {"data": {"type": "item_properties", "id": "a"}}}}}`,
+			pointer: "/data/relationships/item_properties/data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+			c.Request = withPrincipal(c.Request, "user-1")
+
+			handler.Create(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			errs := decodeErrors(t, w)
+			if assert.NotNil(t, errs[0].Source) {
+				assert.Equal(t, tt.pointer, errs[0].Source.Pointer)
+			}
+			svc.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestItemHandler_Create_InvalidProperty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	body := `{"data": {"type": "items", "attributes": {"title": "Lamp"},
+		"relationships": {"item_properties": {"data": [{"type": "item_properties", "id": "a"}]}}},
+		"included": [{"type": "item_properties", "id": "a", "attributes": {"name": "size"}}]}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	c.Request = withPrincipal(c.Request, "user-1")
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	// The error points at the included resource
+	errs := decodeErrors(t, w)
+	if assert.NotNil(t, errs[0].Source) {
+		assert.Equal(t, "/included/0/attributes/value", errs[0].Source.Pointer)
+	}
+	svc.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
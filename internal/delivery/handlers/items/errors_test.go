@@ -0,0 +1,125 @@
+package items
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeErrors asserts w holds a JSON:API error document and returns its errors
+func decodeErrors(t *testing.T, w *httptest.ResponseRecorder) []apierror.Error {
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	var doc struct {
+		Errors []apierror.Error `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	require.NotEmpty(t, doc.Errors)
+	return doc.Errors
+}
+
+func TestItemHandler_Create_ValidationErrorDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemHandler(new(MockItemService), newTestValidator(), newTestLogger(), newTestConfig())
+
+	var buf bytes.Buffer
+	require.NoError(t, jsonapi.MarshalPayload(&buf, &domain.Item{ID: "550e8400-e29b-41d4-a716-446655440000"}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errs := decodeErrors(t, w)
+	assert.Equal(t, "400", errs[0].Status)
+	assert.Equal(t, "validation_failed", errs[0].Code)
+	if assert.NotNil(t, errs[0].Source) {
+		assert.Equal(t, "/data/attributes/title", errs[0].Source.Pointer)
+	}
+}
+
+func TestItemHandler_ErrorDocuments(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+		code   string
+	}{
+		{"invalid UUID", "not-a-uuid", nil, http.StatusBadRequest, "bad_request"},
+		{"not found", testUUID, domain.ErrNotFound, http.StatusNotFound, "not_found"},
+		{"conflict", testUUID, domain.ErrConflict, http.StatusConflict, "conflict"},
+		{"unavailable", testUUID, fmt.Errorf("%w: dial tcp 10.0.0.1:3306: i/o timeout", domain.ErrUnavailable), http.StatusServiceUnavailable, "unavailable"},
+		{"internal error", testUUID, errors.New("dial tcp 10.0.0.1:3306: connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			svc.On("DeleteItem", mock.Anything, tt.id, int64(0)).Return(tt.err).Maybe()
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/items/"+tt.id, nil)
+
+			handler.Delete(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			errs := decodeErrors(t, w)
+			assert.Equal(t, tt.code, errs[0].Code)
+			// Internal failures are not disclosed to clients
+			assert.NotContains(t, w.Body.String(), "10.0.0.1")
+		})
+	}
+}
+
+func TestItemHandler_GetByID_ServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc := new(MockItemService)
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{}).Return(nil, domain.Validators{}, errors.New("connection reset"))
+
+	w, _, _ := getItem(t, svc, testUUID, "/api/v1/items/"+testUUID)
+
+	// Only missing items are reported as not found
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "internal_error", decodeErrors(t, w)[0].Code)
+}
+
+func TestItemPropertyHandler_Create_InvalidDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemPropertyHandler(new(MockItemPropertyService), newTestValidator(), newTestConfig())
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: itemID}}
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items/"+itemID+"/properties", bytes.NewBufferString(`{"data":`))
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errs := decodeErrors(t, w)
+	assert.Equal(t, "invalid_document", errs[0].Code)
+	if assert.NotNil(t, errs[0].Source) {
+		assert.Equal(t, "/data", errs[0].Source.Pointer)
+	}
+}
@@ -0,0 +1,108 @@
+package items
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatchHeader is the header conditional changes send the ETag they expect in
+const ifMatchHeader = "If-Match"
+
+// etag returns the entity tag of a resource at version, a strong validator.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setETag sends the entity tag of a resource at version.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", etag(version))
+}
+
+// representationETag returns the entity tag of the representation v describes: the
+// version of the resource followed by the digest of the representation, so that
+// If-Match can be checked against the version, or the digest alone for lists.
+func representationETag(v domain.Validators) string {
+	if v.Version == 0 {
+		return `"` + v.Digest + `"`
+	}
+	return `"` + strconv.FormatInt(v.Version, 10) + "-" + v.Digest + `"`
+}
+
+// notModified sends the validators of the representation v describes and, when the
+// conditions of the request show that the client holds it already, answers
+// 304 Not Modified. It reports whether it answered. If-None-Match takes precedence
+// over If-Modified-Since, which is only checked when the last change is known.
+func notModified(c *gin.Context, v domain.Validators) bool {
+	tag := representationETag(v)
+	c.Header("ETag", tag)
+	if !v.LastModified.IsZero() {
+		c.Header("Last-Modified", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		if !noneMatch(header, tag) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || v.LastModified.IsZero() {
+		return false
+	}
+	// HTTP dates have a precision of a second
+	if v.LastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// noneMatch reports whether the If-None-Match header, "*" or a list of entity
+// tags, matches none of tag. Tags are compared weakly, ignoring the W/ prefix.
+func noneMatch(header string, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return false
+		}
+	}
+	return true
+}
+
+// ifMatch returns the version the If-Match header of the request expects the
+// resource to be at, 0 when any version may be changed ("*", or no header when
+// the header is not required). The version is read from entity tags of the
+// resource (etag) and of its representations (representationETag). Entity tags
+// are compared strongly: weak tags and tags the API never sent match no version.
+// Lists of tags are not supported.
+func ifMatch(c *gin.Context, required bool) (int64, error) {
+	value := strings.TrimSpace(c.GetHeader(ifMatchHeader))
+	switch {
+	case value == "" && required:
+		return 0, apierror.PreconditionRequired(ifMatchHeader)
+	case value == "", value == "*":
+		return 0, nil
+	case strings.Contains(value, ","):
+		return 0, apierror.BadRequest(ifMatchHeader + " must be a single entity tag or *").WithHeader(ifMatchHeader)
+	case strings.HasPrefix(value, "W/"):
+		return 0, fmt.Errorf("%w: weak entity tag %s", domain.ErrPreconditionFailed, value)
+	}
+
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, apierror.BadRequest(ifMatchHeader + " must be a quoted entity tag or *").WithHeader(ifMatchHeader)
+	}
+	tag, _, _ := strings.Cut(value[1:len(value)-1], "-")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: unknown entity tag %s", domain.ErrPreconditionFailed, value)
+	}
+	return version, nil
+}
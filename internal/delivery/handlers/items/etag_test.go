@@ -0,0 +1,255 @@
+package items
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		required bool
+		version  int64
+		status   int
+	}{
+		{"absent", "", false, 0, 0},
+		{"absent when required", "", true, 0, http.StatusPreconditionRequired},
+		{"any version", "*", true, 0, 0},
+		{"entity tag", `"3"`, true, 3, 0},
+		{"representation entity tag", `"3-9f86d081884c7d65"`, true, 3, 0},
+		{"weak entity tag", `W/"3"`, false, 0, http.StatusPreconditionFailed},
+		{"unknown entity tag", `"abc"`, false, 0, http.StatusPreconditionFailed},
+		{"list of entity tags", `"3", "4"`, false, 0, http.StatusBadRequest},
+		{"unquoted", "3", false, 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodPut, "/items/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := ifMatch(c, tt.required)
+
+			assert.Equal(t, tt.version, version)
+			if tt.status == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Equal(t, tt.status, apierror.FromError(err)[0].StatusCode())
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetByID_ETag(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	modified := time.Date(2026, 3, 1, 12, 30, 15, 500, time.UTC)
+	svc := new(MockItemService)
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{}).
+		Return(&domain.Item{ID: testUUID, Title: "Lamp", Version: 7}, domain.Validators{Digest: "abc", Version: 7, LastModified: modified}, nil)
+
+	w, _, _ := getItem(t, svc, testUUID, "/api/v1/items/"+testUUID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"7-abc"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Sun, 01 Mar 2026 12:30:15 GMT", w.Header().Get("Last-Modified"))
+}
+
+func TestItemHandler_GetByID_Conditional(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	validators := domain.Validators{Digest: "abc", Version: 7, LastModified: time.Date(2026, 3, 1, 12, 30, 15, 500, time.UTC)}
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"matching entity tag", "If-None-Match", `"7-abc"`, http.StatusNotModified},
+		{"weak matching entity tag", "If-None-Match", `"1-def", W/"7-abc"`, http.StatusNotModified},
+		{"any entity tag", "If-None-Match", "*", http.StatusNotModified},
+		{"changed entity tag", "If-None-Match", `"6-def"`, http.StatusOK},
+		{"not modified since", "If-Modified-Since", "Sun, 01 Mar 2026 12:30:15 GMT", http.StatusNotModified},
+		{"modified since", "If-Modified-Since", "Sun, 01 Mar 2026 12:30:14 GMT", http.StatusOK},
+		{"invalid date", "If-Modified-Since", "yesterday", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{}).
+				Return(&domain.Item{ID: testUUID, Title: "Lamp", Version: 7}, validators, nil)
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: testUUID}}
+			c.Request, _ = http.NewRequest(http.MethodGet, "/items/"+testUUID, nil)
+			c.Request.Header.Set(tt.header, tt.value)
+
+			handler.GetByID(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, `"7-abc"`, w.Header().Get("ETag"))
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestItemHandler_GetAll_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, mock.Anything).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1"}}, Total: 1}, domain.Validators{Digest: "abc"}, nil)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/items", nil)
+	c.Request.Header.Set("If-None-Match", `"abc"`)
+
+	handler.GetAll(c)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+	// Lists have no known last change without a generation
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestItemHandler_Update_IfMatch(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	body := `{"data":{"type":"items","attributes":{"title":"Desk lamp"}}}`
+	tests := []struct {
+		name    string
+		header  string
+		strict  bool
+		err     error
+		status  int
+		etag    string
+		version int64
+	}{
+		{"matching version", `"3"`, true, nil, http.StatusOK, `"4"`, 3},
+		{"changed since", `"2"`, false, domain.ErrPreconditionFailed, http.StatusPreconditionFailed, "", 2},
+		{"unconditional", "", false, nil, http.StatusOK, `"4"`, 0},
+		{"required", "", true, nil, http.StatusPreconditionRequired, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			svc.On("UpdateItem", mock.Anything, mock.MatchedBy(func(i *domain.Item) bool {
+				return i.Version == tt.version
+			})).Run(func(args mock.Arguments) {
+				// The repository moves the item to its next version
+				if tt.err == nil {
+					args.Get(1).(*domain.Item).Version = 4
+				}
+			}).Return(tt.err).Maybe()
+			cfg := newTestConfig()
+			cfg.RequireIfMatch = tt.strict
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), cfg)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: testUUID}}
+			c.Request, _ = http.NewRequest(http.MethodPut, "/items/"+testUUID, bytes.NewBufferString(body))
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			handler.Update(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+			if tt.status == http.StatusPreconditionRequired {
+				svc.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+			}
+			if w.Code >= 400 {
+				errs := decodeErrors(t, w)
+				if assert.NotNil(t, errs[0].Source) {
+					assert.Equal(t, "If-Match", errs[0].Source.Header)
+				}
+			}
+		})
+	}
+}
+
+func TestItemHandler_Patch_IfMatch(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc := new(MockItemService)
+	svc.On("PatchItem", mock.Anything, testUUID, mock.MatchedBy(func(patch *domain.Item) bool {
+		return patch.Version == 5
+	}), domain.FieldMask{"title"}).Return(&domain.Item{ID: testUUID, Title: "Desk lamp", Version: 6}, nil)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodPatch, "/items/"+testUUID, bytes.NewBufferString(`{"data":{"type":"items","attributes":{"title":"Desk lamp"}}}`))
+	c.Request.Header.Set("If-Match", `"5"`)
+
+	handler.Patch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"6"`, w.Header().Get("ETag"))
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_Delete_IfMatch(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc := new(MockItemService)
+	svc.On("DeleteItem", mock.Anything, testUUID, int64(2)).Return(domain.ErrPreconditionFailed)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/items/"+testUUID, nil)
+	c.Request.Header.Set("If-Match", `"2"`)
+
+	handler.Delete(c)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, "precondition_failed", decodeErrors(t, w)[0].Code)
+	svc.AssertExpectations(t)
+}
+
+func TestItemPropertyHandler_Delete_RequiresIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+	propertyID := "550e8400-e29b-41d4-a716-446655440001"
+	svc := new(MockItemPropertyService)
+	cfg := newTestConfig()
+	cfg.RequireIfMatch = true
+	handler := NewItemPropertyHandler(svc, newTestValidator(), cfg)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: itemID}, {Key: "property_id", Value: propertyID}}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/items/"+itemID+"/properties/"+propertyID, nil)
+
+	handler.Delete(c)
+
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	assert.Equal(t, "precondition_required", decodeErrors(t, w)[0].Code)
+	svc.AssertNotCalled(t, "DeleteItemProperty", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,13 +1,15 @@
 package items
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
 )
@@ -19,199 +21,429 @@ func isValidUUID(u string) bool {
 }
 
 type ItemHandler struct {
-	Service   domain.ItemService
-	Validator domain.Validator
-	Logger    logging.Logger
+	Service         domain.ItemService
+	Validator       domain.Validator
+	Logger          logging.Logger
+	DefaultPageSize int
+	MaxPageSize     int
+	// RequireIfMatch refuses changes sent without If-Match
+	RequireIfMatch bool
+	// BulkMaxItems is the largest number of resources in a bulk request
+	BulkMaxItems int
 }
 
-func NewItemHandler(service domain.ItemService, validator domain.Validator, logger logging.Logger) *ItemHandler {
-	return &ItemHandler{Service: service, Validator: validator, Logger: logger}
+func NewItemHandler(service domain.ItemService, validator domain.Validator, logger logging.Logger, cfg *config.Config) *ItemHandler {
+	return &ItemHandler{
+		Service:         service,
+		Validator:       validator,
+		Logger:          logger,
+		DefaultPageSize: cfg.PageDefaultSize,
+		MaxPageSize:     cfg.PageMaxSize,
+		RequireIfMatch:  cfg.RequireIfMatch,
+		BulkMaxItems:    cfg.BulkMaxItems,
+	}
 }
 
-// GetAll gets all items
+// GetAll gets a page of items
 // @Summary      List items
-// @Description  get items
+// @Description  get a page of items, filtered and sorted, by number (page[number]) or after a cursor from a previous page (page[cursor]); answers If-None-Match and If-Modified-Since with 304
 // @Tags         items
 // @Accept       json
 // @Produce      json
-// @Param        include  query     string  false  "Include related resources (e.g. item_properties)"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        include       query     string  false  "Comma-separated related resources to include (item_properties)"
+// @Param        fields[items]            query  string  false  "Comma-separated fields of items to return (title, description, owner_id, created_at, updated_at, item_properties)"
+// @Param        fields[item_properties]  query  string  false  "Comma-separated fields of included item properties to return (item_id, name, value)"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Param        filter[title]            query  string  false  "Items whose title starts with the value"
+// @Param        filter[created_at][gte]  query  string  false  "Items created at or after the time (RFC 3339 or YYYY-MM-DD); gt, lte and lt are supported too"
+// @Param        filter[property.name]    query  string  false  "Items having a property with the name after 'property.' and the value"
+// @Param        sort                     query  string  false  "Comma-separated sort fields (title, created_at, updated_at), '-' for descending, e.g. -created_at,title"
+// @Param        If-None-Match      header  string  false  "ETag of the page held by the client"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified of the page held by the client"
 // @Success      200  {object}  JSONAPIItemListResponse "Items"
-// @Failure      500  {object}  map[string]string
+// @Header       200  {string}  ETag "Digest of the page"
+// @Header       200  {string}  Last-Modified "When the list last changed"
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
 // @Router       /v1/items [get]
 func (h *ItemHandler) GetAll(c *gin.Context) {
-	ctx := c.Request.Context()
-	if c.Query("include") == "item_properties" {
-		ctx = context.WithValue(ctx, "include_properties", true)
+	query, err := parseItemQuery(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
 	}
 
-	items, err := h.Service.GetAllItems(ctx)
+	items, validators, err := h.Service.GetAllItems(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
+		return
+	}
+	if notModified(c, validators) {
 		return
 	}
 
-	c.Header("Content-Type", jsonapi.MediaType)
-	if err := jsonapi.MarshalPayload(c.Writer, items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := writePage(c, query.Page, items, query.Options); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Search searches items
+// @Summary      Search items
+// @Description  full-text search of item titles, descriptions and property values, best matches first; each result carries its relevance in meta.score
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        q             query     string  true   "Words to search for; items matching any of them are returned"
+// @Param        include       query     string  false  "Comma-separated related resources to include (item_properties)"
+// @Param        fields[items]            query  string  false  "Comma-separated fields of items to return (title, description, owner_id, created_at, updated_at, item_properties)"
+// @Param        fields[item_properties]  query  string  false  "Comma-separated fields of included item properties to return (item_id, name, value)"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Success      200  {object}  JSONAPIItemSearchResponse "Items"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/items/search [get]
+func (h *ItemHandler) Search(c *gin.Context) {
+	search, err := parseItemSearch(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	results, err := h.Service.SearchItems(c.Request.Context(), search)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	items := &domain.Page[*domain.Item]{Items: make([]*domain.Item, len(results.Items)), Total: results.Total, NextCursor: results.NextCursor}
+	for i, result := range results.Items {
+		items.Items[i] = result.Item
+	}
+	many, err := marshalPage(c, search.Page, items, search.Options)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	for i, node := range many.Data {
+		node.Meta = &jsonapi.Meta{"score": results.Items[i].Score}
+	}
+	if err := writePayload(c, many); err != nil {
+		apierror.Respond(c, err)
 	}
 }
 
 // GetByID gets an item by ID
 // @Summary      Show an item
-// @Description  get item by ID
+// @Description  get item by ID; the ETag header holds its version, for If-Match on later changes, and answers If-None-Match and If-Modified-Since with 304
 // @Tags         items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string  true  "Item ID"
-// @Param        include  query     string  false  "Include related resources (e.g. item_properties)"
+// @Param        include  query     string  false  "Comma-separated related resources to include (item_properties)"
+// @Param        fields[items]            query  string  false  "Comma-separated fields of the item to return (title, description, owner_id, created_at, updated_at, item_properties)"
+// @Param        fields[item_properties]  query  string  false  "Comma-separated fields of included item properties to return (item_id, name, value)"
+// @Param        as_of    query     string  false  "Read the item as it was at this time (RFC 3339 or YYYY-MM-DD), from its revisions; such reads carry no validators"
+// @Param        If-None-Match      header  string  false  "ETag of the representation held by the client"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified of the representation held by the client"
 // @Success      200  {object}  JSONAPIItemResponse "Item"
-// @Failure      404  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Header       200  {string}  ETag "Version of the item and digest of the representation"
+// @Header       200  {string}  Last-Modified "When the representation last changed"
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
 // @Router       /v1/items/{id} [get]
 func (h *ItemHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
 	// Validate UUID format
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
 		return
 	}
 
-	ctx := c.Request.Context()
-	if c.Query("include") == "item_properties" {
-		ctx = context.WithValue(ctx, "include_properties", true)
+	opts, err := parseItemOptions(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if value, ok := c.GetQuery("as_of"); ok {
+		h.getAsOf(c, id, value, opts)
+		return
 	}
 
-	item, err := h.Service.GetItemByID(ctx, id)
+	item, validators, err := h.Service.GetItemByID(c.Request.Context(), id, opts)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	if notModified(c, validators) {
 		return
 	}
 
-	c.Header("Content-Type", jsonapi.MediaType)
-	if err := jsonapi.MarshalPayload(c.Writer, item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := writeResource(c, item, opts); err != nil {
+		apierror.Respond(c, err)
 	}
 }
 
 // Create creates a new item
 // @Summary      Create an item
-// @Description  Create a new item (ID is auto-generated)
+// @Description  Create a new item (ID is auto-generated, the owner is the authenticated caller), optionally with properties: those linked in relationships.item_properties, with their attributes in included, are created with it atomically. Their IDs only link the two and are replaced by generated ones
 // @Tags         items
 // @Accept       json
 // @Produce      json
-// @Param        item  body      JSONAPIItem  true  "Item data"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        item  body      JSONAPINewItem  true  "Item data, with the properties to create in included"
 // @Success      201   {object}  JSONAPIItemResponse "Created Item"
-// @Failure      400   {object}  map[string]string
-// @Failure      500   {object}  map[string]string
+// @Header       201   {string}  ETag "Version of the item"
+// @Failure      400   {object}  apierror.Document
+// @Failure      401   {object}  apierror.Document
+// @Failure      403   {object}  apierror.Document
+// @Failure      500   {object}  apierror.Document
 // @Router       /v1/items [post]
 func (h *ItemHandler) Create(c *gin.Context) {
 	h.Logger.LogRequest(c)
 
-	item := new(domain.Item)
-	if err := jsonapi.UnmarshalPayload(c.Request.Body, item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	item, included, err := decodeNewItem(c)
+	if err != nil {
+		apierror.Respond(c, err)
 		return
 	}
 
 	// Auto-generate UUID, ignoring any ID provided in the request
 	item.ID = uuid.New().String()
 
+	// The authenticated caller owns the item, ignoring any owner provided in the request
+	principal, ok := domain.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		apierror.Write(c, apierror.Unauthorized())
+		return
+	}
+	item.OwnerID = principal.Subject
+
 	// Set CreatedAt to current timestamp
 	now := time.Now()
 	item.CreatedAt = &now
 
-	// Validate the item using the injected validator
-	if validationErrors := h.Validator.Validate(item); len(validationErrors) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors})
+	// Validate the item and its properties using the injected validator
+	validationErrors := h.Validator.Validate(item)
+	for i, property := range item.ItemProperties {
+		// The properties get IDs of their own and belong to the new item
+		property.ID = uuid.New().String()
+		property.ItemID = ""
+		validationErrors = append(validationErrors, includedValidationErrors(h.Validator.Validate(property), included[i])...)
+	}
+	if len(validationErrors) > 0 {
+		apierror.Respond(c, validationErrors)
 		return
 	}
 
 	if err := h.Service.CreateItem(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
+	setETag(c, item.Version)
 	c.Status(http.StatusCreated)
 	c.Header("Content-Type", jsonapi.MediaType)
 	if err := jsonapi.MarshalPayload(c.Writer, item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 	}
 }
 
 // Update updates an item
 // @Summary      Update an item
-// @Description  Update an item by ID (ID in request body is ignored, path parameter is used)
+// @Description  Update an item by ID (ID in request body is ignored, path parameter is used); with If-Match, only if the item is still at that version
 // @Tags         items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id    path      string       true  "Item ID (UUID format)"
+// @Param        If-Match  header  string     false  "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set"
 // @Param        item  body      JSONAPIItem true  "Item data"
 // @Success      200   {object}  JSONAPIItemResponse "Updated Item"
-// @Failure      400   {object}  map[string]string
-// @Failure      500   {object}  map[string]string
+// @Header       200   {string}  ETag "New version of the item"
+// @Failure      400   {object}  apierror.Document
+// @Failure      401   {object}  apierror.Document
+// @Failure      403   {object}  apierror.Document
+// @Failure      404   {object}  apierror.Document
+// @Failure      412   {object}  apierror.Document
+// @Failure      428   {object}  apierror.Document
+// @Failure      500   {object}  apierror.Document
 // @Router       /v1/items/{id} [put]
 func (h *ItemHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
 	// Validate UUID format
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
 		return
 	}
 
 	h.Logger.LogRequest(c)
 
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
 	item := new(domain.Item)
 	if err := jsonapi.UnmarshalPayload(c.Request.Body, item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Write(c, apierror.InvalidDocument(err))
 		return
 	}
 	// Use ID from path parameter, ignoring any ID in request body
 	item.ID = id
+	item.Version = version
 
 	// Validate the item using the injected validator
 	if validationErrors := h.Validator.Validate(item); len(validationErrors) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors})
+		apierror.Respond(c, validationErrors)
 		return
 	}
 
 	if err := h.Service.UpdateItem(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
 		return
 	}
 
+	setETag(c, item.Version)
 	c.Header("Content-Type", jsonapi.MediaType)
 	if err := jsonapi.MarshalPayload(c.Writer, item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 	}
 }
 
+// Patch partially updates an item
+// @Summary      Partially update an item
+// @Description  Change the attributes of an item present in the document (title, description), keeping the others; the merged item is validated. With If-Match, only if the item is still at that version
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id    path      string       true  "Item ID (UUID format)"
+// @Param        If-Match  header  string     false  "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set"
+// @Param        item  body      JSONAPIItem true  "Attributes to change"
+// @Success      200   {object}  JSONAPIItemResponse "Updated Item"
+// @Header       200   {string}  ETag "New version of the item"
+// @Failure      400   {object}  apierror.Document
+// @Failure      401   {object}  apierror.Document
+// @Failure      403   {object}  apierror.Document
+// @Failure      404   {object}  apierror.Document
+// @Failure      412   {object}  apierror.Document
+// @Failure      428   {object}  apierror.Document
+// @Failure      500   {object}  apierror.Document
+// @Router       /v1/items/{id} [patch]
 func (h *ItemHandler) Patch(c *gin.Context) {
-	// For simplicity in this boilerplate, PATCH is handled similarly to Update
-	h.Update(c)
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	h.Logger.LogRequest(c)
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	patch := new(domain.Item)
+	mask, err := decodePatch(c, patch, domain.ItemPatchFields)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	patch.Version = version
+
+	item, err := h.Service.PatchItem(c.Request.Context(), id, patch, mask)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	setETag(c, item.Version)
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := jsonapi.MarshalPayload(c.Writer, item); err != nil {
+		apierror.Respond(c, err)
+	}
 }
 
 // Delete deletes an item
 // @Summary      Delete an item
-// @Description  Delete an item by ID
+// @Description  Delete an item by ID; with If-Match, only if the item is still at that version
 // @Tags         items
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      string  true  "Item ID (UUID format)"
+// @Param        If-Match  header  string  false  "ETag of the version to delete, or *; required when REQUIRE_IF_MATCH is set"
 // @Success      204  {object}  nil
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      412  {object}  apierror.Document
+// @Failure      428  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
 // @Router       /v1/items/{id} [delete]
 func (h *ItemHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	// Validate UUID format
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
 		return
 	}
 
-	if err := h.Service.DeleteItem(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Service.DeleteItem(c.Request.Context(), id, version); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
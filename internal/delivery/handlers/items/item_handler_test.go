@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gadz82/go-api-boilerplate/internal/config"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
 	"github.com/gadz82/go-api-boilerplate/internal/validation"
@@ -21,17 +24,28 @@ type MockItemService struct {
 	mock.Mock
 }
 
-func (m *MockItemService) GetAllItems(ctx context.Context) ([]*domain.Item, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*domain.Item), args.Error(1)
+func (m *MockItemService) GetAllItems(ctx context.Context, query domain.ItemQuery) (*domain.Page[*domain.Item], domain.Validators, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(domain.Validators), args.Error(2)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Get(1).(domain.Validators), args.Error(2)
 }
 
-func (m *MockItemService) GetItemByID(ctx context.Context, id string) (*domain.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) SearchItems(ctx context.Context, search domain.ItemSearch) (*domain.Page[*domain.ItemSearchResult], error) {
+	args := m.Called(ctx, search)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.Item), args.Error(1)
+	return args.Get(0).(*domain.Page[*domain.ItemSearchResult]), args.Error(1)
+}
+
+func (m *MockItemService) GetItemByID(ctx context.Context, id string, opts domain.QueryOptions) (*domain.Item, domain.Validators, error) {
+	args := m.Called(ctx, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(domain.Validators), args.Error(2)
+	}
+	return args.Get(0).(*domain.Item), args.Get(1).(domain.Validators), args.Error(2)
 }
 
 func (m *MockItemService) CreateItem(ctx context.Context, item *domain.Item) error {
@@ -44,11 +58,88 @@ func (m *MockItemService) UpdateItem(ctx context.Context, item *domain.Item) err
 	return args.Error(0)
 }
 
-func (m *MockItemService) DeleteItem(ctx context.Context, id string) error {
+func (m *MockItemService) PatchItem(ctx context.Context, id string, patch *domain.Item, mask domain.FieldMask) (*domain.Item, error) {
+	args := m.Called(ctx, id, patch, mask)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) DeleteItem(ctx context.Context, id string, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemService) ListDeletedItems(ctx context.Context, page domain.PageRequest) (*domain.Page[*domain.Item], error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Error(1)
+}
+
+func (m *MockItemService) RestoreItem(ctx context.Context, id string) (*domain.Item, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) PurgeItem(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockItemService) ListItemRevisions(ctx context.Context, id string, page domain.PageRequest) (*domain.Page[*domain.ItemRevision], error) {
+	args := m.Called(ctx, id, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.ItemRevision]), args.Error(1)
+}
+
+func (m *MockItemService) GetItemAsOf(ctx context.Context, id string, at time.Time, opts domain.QueryOptions) (*domain.Item, error) {
+	args := m.Called(ctx, id, at, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) RevertItem(ctx context.Context, id string, revision int64, version int64) (*domain.Item, error) {
+	args := m.Called(ctx, id, revision, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemService) UpdateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemService) DeleteItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
 // MockValidator implements domain.Validator for testing
 type MockValidator struct {
 	mock.Mock
@@ -89,15 +180,25 @@ func newTestLogger() logging.Logger {
 	return &MockLogger{}
 }
 
+// newTestConfig returns the configuration the handlers under test are built with
+func newTestConfig() *config.Config {
+	return &config.Config{PageDefaultSize: 20, PageMaxSize: 100, BulkMaxItems: 1000}
+}
+
+// withPrincipal returns a copy of req authenticated as subject
+func withPrincipal(req *http.Request, subject string) *http.Request {
+	return req.WithContext(domain.ContextWithPrincipal(req.Context(), &domain.Principal{Subject: subject}))
+}
+
 func TestItemHandler_GetAll(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
-	expectedItems := []*domain.Item{{ID: "1", Title: "Test"}}
-	svc.On("GetAllItems", mock.Anything).Return(expectedItems, nil)
+	expectedItems := &domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", Title: "Test"}}, Total: 1}
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{Page: domain.PageRequest{Size: 20}}).Return(expectedItems, domain.Validators{}, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -114,11 +215,11 @@ func TestItemHandler_GetByID(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 	item := &domain.Item{ID: testUUID, Title: "Test"}
-	svc.On("GetItemByID", mock.Anything, testUUID).Return(item, nil)
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{}).Return(item, domain.Validators{}, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -136,7 +237,7 @@ func TestItemHandler_GetByID_InvalidUUID(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -153,10 +254,10 @@ func TestItemHandler_GetByID_NotFound(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440001"
-	svc.On("GetItemByID", mock.Anything, testUUID).Return(nil, errors.New("not found"))
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{}).Return(nil, domain.Validators{}, domain.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -173,12 +274,12 @@ func TestItemHandler_Create(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	item := &domain.Item{Title: "New Item"}
 	svc.On("CreateItem", mock.Anything, mock.MatchedBy(func(i *domain.Item) bool {
-		// Verify that UUID is auto-generated and title is preserved
-		return i.Title == item.Title && i.ID != "" && isValidUUID(i.ID)
+		// Verify that UUID is auto-generated, title is preserved and the caller owns the item
+		return i.Title == item.Title && i.ID != "" && isValidUUID(i.ID) && i.OwnerID == "user-1"
 	})).Return(nil)
 
 	var buf bytes.Buffer
@@ -188,6 +289,7 @@ func TestItemHandler_Create(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
 
 	handler.Create(c)
 
@@ -200,7 +302,7 @@ func TestItemHandler_Create_IgnoresProvidedID(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	// Item with a provided ID that should be ignored
 	providedID := "550e8400-e29b-41d4-a716-446655440000"
@@ -217,6 +319,7 @@ func TestItemHandler_Create_IgnoresProvidedID(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
 
 	handler.Create(c)
 
@@ -224,12 +327,62 @@ func TestItemHandler_Create_IgnoresProvidedID(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestItemHandler_Create_IgnoresProvidedOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	validator := newTestValidator()
+	logger := newTestLogger()
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
+
+	// Item claiming to belong to someone else
+	item := &domain.Item{Title: "New Item", OwnerID: "someone-else"}
+	svc.On("CreateItem", mock.Anything, mock.MatchedBy(func(i *domain.Item) bool {
+		return i.OwnerID == "user-1"
+	})).Return(nil)
+
+	var buf bytes.Buffer
+	err := jsonapi.MarshalPayload(&buf, item)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"owner_id":"user-1"`)
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_Create_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	validator := newTestValidator()
+	logger := newTestLogger()
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
+
+	var buf bytes.Buffer
+	err := jsonapi.MarshalPayload(&buf, &domain.Item{Title: "New Item"})
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+
+	handler.Create(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	svc.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
+
 func TestItemHandler_Create_MissingTitle(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	// Item without required title
 	item := &domain.Item{ID: "550e8400-e29b-41d4-a716-446655440000"}
@@ -241,6 +394,7 @@ func TestItemHandler_Create_MissingTitle(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
 
 	handler.Create(c)
 
@@ -252,10 +406,10 @@ func TestItemHandler_Delete(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
-	svc.On("DeleteItem", mock.Anything, testUUID).Return(nil)
+	svc.On("DeleteItem", mock.Anything, testUUID, int64(0)).Return(nil)
 
 	w := httptest.NewRecorder()
 	c, r := gin.CreateTestContext(w)
@@ -274,7 +428,7 @@ func TestItemHandler_Delete_InvalidUUID(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -291,7 +445,7 @@ func TestItemHandler_Update(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 	item := &domain.Item{ID: testUUID, Title: "Updated Item"}
@@ -319,7 +473,7 @@ func TestItemHandler_Update_InvalidUUID(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -336,7 +490,7 @@ func TestItemHandler_Update_InvalidBody(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 
@@ -355,7 +509,7 @@ func TestItemHandler_Update_ValidationError(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 	// Item without required title
@@ -380,7 +534,7 @@ func TestItemHandler_Update_ServiceError(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 	item := &domain.Item{ID: testUUID, Title: "Updated Item"}
@@ -401,18 +555,16 @@ func TestItemHandler_Update_ServiceError(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
-func TestItemHandler_Patch(t *testing.T) {
+func TestItemHandler_Update_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
-	item := &domain.Item{ID: testUUID, Title: "Patched Item"}
-	svc.On("UpdateItem", mock.Anything, mock.MatchedBy(func(i *domain.Item) bool {
-		return i.ID == testUUID && i.Title == "Patched Item"
-	})).Return(nil)
+	item := &domain.Item{ID: testUUID, Title: "Updated Item"}
+	svc.On("UpdateItem", mock.Anything, mock.Anything).Return(fmt.Errorf("item: %w", domain.ErrNotFound))
 
 	var buf bytes.Buffer
 	err := jsonapi.MarshalPayload(&buf, item)
@@ -421,23 +573,91 @@ func TestItemHandler_Patch(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Params = gin.Params{{Key: "id", Value: testUUID}}
-	c.Request, _ = http.NewRequest(http.MethodPatch, "/items/"+testUUID, &buf)
+	c.Request, _ = http.NewRequest(http.MethodPut, "/items/"+testUUID, &buf)
+
+	handler.Update(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_Patch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	validator := newTestValidator()
+	logger := newTestLogger()
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
+
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	patched := &domain.Item{ID: testUUID, Title: "Patched Item", Description: "Kept", OwnerID: "user-1", CreatedAt: &createdAt}
+	// Only the attributes in the document are patched
+	svc.On("PatchItem", mock.Anything, testUUID, mock.MatchedBy(func(i *domain.Item) bool {
+		return i.Title == "Patched Item"
+	}), domain.FieldMask{"title"}).Return(patched, nil)
+
+	body := `{"data":{"type":"items","id":"` + testUUID + `","attributes":{"title":"Patched Item","owner_id":"mallory"}}}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodPatch, "/items/"+testUUID, bytes.NewBufferString(body))
 
 	handler.Patch(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	// The full updated item is returned
+	assert.Contains(t, w.Body.String(), `"description":"Kept"`)
+	assert.Contains(t, w.Body.String(), `"created_at":"2024-01-02T03:04:05Z"`)
 	svc.AssertExpectations(t)
 }
 
+func TestItemHandler_Patch_Errors(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"invalid document", `{"data":`, nil, http.StatusBadRequest},
+		{"no primary data", `{}`, nil, http.StatusBadRequest},
+		{"invalid result", `{"data":{"type":"items","attributes":{"title":""}}}`, domain.ValidationErrors{{Field: "title", Message: "title is required", Pointer: "/data/attributes/title"}}, http.StatusBadRequest},
+		{"not found", `{"data":{"type":"items","attributes":{"title":"Lamp"}}}`, domain.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			svc.On("PatchItem", mock.Anything, testUUID, mock.Anything, mock.Anything).Return(nil, tt.err).Maybe()
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: testUUID}}
+			c.Request, _ = http.NewRequest(http.MethodPatch, "/items/"+testUUID, bytes.NewBufferString(tt.body))
+
+			handler.Patch(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+			if tt.err == nil {
+				svc.AssertNotCalled(t, "PatchItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestItemHandler_Delete_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
-	svc.On("DeleteItem", mock.Anything, testUUID).Return(errors.New("service error"))
+	svc.On("DeleteItem", mock.Anything, testUUID, int64(0)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -450,14 +670,35 @@ func TestItemHandler_Delete_ServiceError(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestItemHandler_Delete_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	validator := newTestValidator()
+	logger := newTestLogger()
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
+
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc.On("DeleteItem", mock.Anything, testUUID, int64(0)).Return(fmt.Errorf("item: %w", domain.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/items/"+testUUID, nil)
+
+	handler.Delete(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+}
+
 func TestItemHandler_GetAll_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
-	svc.On("GetAllItems", mock.Anything).Return([]*domain.Item{}, errors.New("service error"))
+	svc.On("GetAllItems", mock.Anything, mock.Anything).Return(nil, domain.Validators{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -474,10 +715,13 @@ func TestItemHandler_GetAll_WithIncludeProperties(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
-	expectedItems := []*domain.Item{{ID: "1", Title: "Test"}}
-	svc.On("GetAllItems", mock.Anything).Return(expectedItems, nil)
+	expectedItems := &domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", Title: "Test"}}, Total: 1}
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{
+		Page:    domain.PageRequest{Size: 20},
+		Options: domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}},
+	}).Return(expectedItems, domain.Validators{}, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -494,11 +738,11 @@ func TestItemHandler_GetByID_WithIncludeProperties(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	testUUID := "550e8400-e29b-41d4-a716-446655440000"
 	item := &domain.Item{ID: testUUID, Title: "Test"}
-	svc.On("GetItemByID", mock.Anything, testUUID).Return(item, nil)
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}}).Return(item, domain.Validators{}, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -516,7 +760,7 @@ func TestItemHandler_Create_ServiceError(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	item := &domain.Item{Title: "New Item"}
 	svc.On("CreateItem", mock.Anything, mock.Anything).Return(errors.New("service error"))
@@ -528,6 +772,7 @@ func TestItemHandler_Create_ServiceError(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodPost, "/items", &buf)
+	c.Request = withPrincipal(c.Request, "user-1")
 
 	handler.Create(c)
 
@@ -540,7 +785,7 @@ func TestItemHandler_Create_InvalidBody(t *testing.T) {
 	svc := new(MockItemService)
 	validator := newTestValidator()
 	logger := newTestLogger()
-	handler := NewItemHandler(svc, validator, logger)
+	handler := NewItemHandler(svc, validator, logger, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
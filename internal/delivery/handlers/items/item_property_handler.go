@@ -2,6 +2,7 @@ package items
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -9,47 +10,82 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 )
 
 type ItemPropertyHandler struct {
-	Service   domain.ItemPropertyService
-	Validator domain.Validator
+	Service         domain.ItemPropertyService
+	Validator       domain.Validator
+	DefaultPageSize int
+	MaxPageSize     int
+	// RequireIfMatch refuses changes sent without If-Match
+	RequireIfMatch bool
 }
 
-func NewItemPropertyHandler(service domain.ItemPropertyService, validator domain.Validator) *ItemPropertyHandler {
-	return &ItemPropertyHandler{Service: service, Validator: validator}
+func NewItemPropertyHandler(service domain.ItemPropertyService, validator domain.Validator, cfg *config.Config) *ItemPropertyHandler {
+	return &ItemPropertyHandler{
+		Service:         service,
+		Validator:       validator,
+		DefaultPageSize: cfg.PageDefaultSize,
+		MaxPageSize:     cfg.PageMaxSize,
+		RequireIfMatch:  cfg.RequireIfMatch,
+	}
 }
 
-// GetAll gets all item properties
+// GetAll gets a page of item properties
 // @Summary      List item properties
-// @Description  get item properties for a specific item
+// @Description  get a page of item properties for a specific item, by number (page[number]) or after a cursor from a previous page (page[cursor])
 // @Tags         item_properties
 // @Accept       json
 // @Produce      json
-// @Param        id   path      string  true  "Item ID (UUID format)"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id            path      string  true   "Item ID (UUID format)"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Param        fields[item_properties]  query  string  false  "Comma-separated fields of item properties to return (item_id, name, value)"
 // @Success      200  {object}  JSONAPIItemPropertyListResponse "Item Properties"
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
 // @Router       /v1/items/{id}/properties [get]
 func (h *ItemPropertyHandler) GetAll(c *gin.Context) {
 	itemID := c.Param("id")
 
 	// Validate UUID format for item ID
 	if !isValidUUID(itemID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for item ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
 		return
 	}
 
-	properties, err := h.Service.GetItemPropertiesByItemID(c.Request.Context(), itemID)
+	page, err := parsePageRequest(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	opts, err := parseItemPropertyOptions(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.Header("Content-Type", jsonapi.MediaType)
-	if err := jsonapi.MarshalPayload(c.Writer, properties); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	properties, err := h.Service.GetItemPropertiesByItemID(c.Request.Context(), itemID, page, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := writePage(c, page, properties, opts); err != nil {
+		apierror.Respond(c, err)
 	}
 }
 
@@ -59,12 +95,18 @@ func (h *ItemPropertyHandler) GetAll(c *gin.Context) {
 // @Tags         item_properties
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id           path      string  true  "Item ID (UUID format)"
 // @Param        property_id  path      string  true  "Property ID (UUID format)"
+// @Param        fields[item_properties]  query  string  false  "Comma-separated fields of the item property to return (item_id, name, value)"
 // @Success      200          {object}  JSONAPIItemPropertyResponse "Item Property"
-// @Failure      400          {object}  map[string]string
-// @Failure      404          {object}  map[string]string
-// @Failure      500          {object}  map[string]string
+// @Header       200          {string}  ETag "Version of the item property"
+// @Failure      400          {object}  apierror.Document
+// @Failure      401          {object}  apierror.Document
+// @Failure      403          {object}  apierror.Document
+// @Failure      404          {object}  apierror.Document
+// @Failure      500          {object}  apierror.Document
 // @Router       /v1/items/{id}/properties/{property_id} [get]
 func (h *ItemPropertyHandler) GetByID(c *gin.Context) {
 	itemID := c.Param("id")
@@ -72,25 +114,35 @@ func (h *ItemPropertyHandler) GetByID(c *gin.Context) {
 
 	// Validate UUID format for item ID
 	if !isValidUUID(itemID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for item ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
 		return
 	}
 
 	// Validate UUID format for property ID
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for property ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for property ID"))
 		return
 	}
 
-	property, err := h.Service.GetItemPropertyByID(c.Request.Context(), itemID, id)
+	opts, err := parseItemPropertyOptions(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item property not found"})
+		apierror.Respond(c, err)
 		return
 	}
 
-	c.Header("Content-Type", jsonapi.MediaType)
-	if err := jsonapi.MarshalPayload(c.Writer, property); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	property, err := h.Service.GetItemPropertyByID(c.Request.Context(), itemID, id, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item property not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	setETag(c, property.Version)
+	if err := writeResource(c, property, opts); err != nil {
+		apierror.Respond(c, err)
 	}
 }
 
@@ -100,18 +152,24 @@ func (h *ItemPropertyHandler) GetByID(c *gin.Context) {
 // @Tags         item_properties
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id        path      string               true  "Item ID (UUID format)"
 // @Param        property  body      JSONAPIItemProperty true  "Property data"
 // @Success      201       {object}  JSONAPIItemPropertyResponse "Created Item Property"
-// @Failure      400       {object}  map[string]string
-// @Failure      500       {object}  map[string]string
+// @Header       201       {string}  ETag "Version of the item property"
+// @Failure      400       {object}  apierror.Document
+// @Failure      401       {object}  apierror.Document
+// @Failure      403       {object}  apierror.Document
+// @Failure      404       {object}  apierror.Document
+// @Failure      500       {object}  apierror.Document
 // @Router       /v1/items/{id}/properties [post]
 func (h *ItemPropertyHandler) Create(c *gin.Context) {
 	itemID := c.Param("id")
 
 	// Validate UUID format for item ID
 	if !isValidUUID(itemID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for item ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
 		return
 	}
 
@@ -121,7 +179,7 @@ func (h *ItemPropertyHandler) Create(c *gin.Context) {
 
 	property := new(domain.ItemProperty)
 	if err := jsonapi.UnmarshalPayload(c.Request.Body, property); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Write(c, apierror.InvalidDocument(err))
 		return
 	}
 
@@ -131,34 +189,48 @@ func (h *ItemPropertyHandler) Create(c *gin.Context) {
 
 	// Validate the property using the injected validator
 	if validationErrors := h.Validator.Validate(property); len(validationErrors) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors})
+		apierror.Respond(c, validationErrors)
 		return
 	}
 
 	if err := h.Service.CreateItemProperty(c.Request.Context(), property); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
 		return
 	}
 
+	setETag(c, property.Version)
 	c.Status(http.StatusCreated)
 	c.Header("Content-Type", jsonapi.MediaType)
 	if err := jsonapi.MarshalPayload(c.Writer, property); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 	}
 }
 
 // Update updates an item property
 // @Summary      Update an item property
-// @Description  Update an item property by ID for a specific item (ID in request body is ignored, path parameter is used)
+// @Description  Update an item property by ID for a specific item (ID in request body is ignored, path parameter is used); with If-Match, only if the property is still at that version
 // @Tags         item_properties
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id           path      string               true  "Item ID (UUID format)"
 // @Param        property_id  path      string               true  "Property ID (UUID format)"
+// @Param        If-Match     header    string               false  "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set"
 // @Param        property     body      JSONAPIItemProperty true  "Property data"
 // @Success      200          {object}  JSONAPIItemPropertyResponse "Updated Item Property"
-// @Failure      400          {object}  map[string]string
-// @Failure      500          {object}  map[string]string
+// @Header       200          {string}  ETag "New version of the item property"
+// @Failure      400          {object}  apierror.Document
+// @Failure      401          {object}  apierror.Document
+// @Failure      403          {object}  apierror.Document
+// @Failure      404          {object}  apierror.Document
+// @Failure      412          {object}  apierror.Document
+// @Failure      428          {object}  apierror.Document
+// @Failure      500          {object}  apierror.Document
 // @Router       /v1/items/{id}/properties/{property_id} [put]
 func (h *ItemPropertyHandler) Update(c *gin.Context) {
 	itemID := c.Param("id")
@@ -166,13 +238,19 @@ func (h *ItemPropertyHandler) Update(c *gin.Context) {
 
 	// Validate UUID format for item ID
 	if !isValidUUID(itemID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for item ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
 		return
 	}
 
 	// Validate UUID format for property ID
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for property ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for property ID"))
+		return
+	}
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
 		return
 	}
 
@@ -182,43 +260,122 @@ func (h *ItemPropertyHandler) Update(c *gin.Context) {
 
 	property := new(domain.ItemProperty)
 	if err := jsonapi.UnmarshalPayload(c.Request.Body, property); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Write(c, apierror.InvalidDocument(err))
 		return
 	}
 	// Use IDs from path parameters, ignoring any IDs in request body
 	property.ID = id
 	property.ItemID = itemID
+	property.Version = version
 
 	// Validate the property using the injected validator
 	if validationErrors := h.Validator.Validate(property); len(validationErrors) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErrors})
+		apierror.Respond(c, validationErrors)
 		return
 	}
 
 	if err := h.Service.UpdateItemProperty(c.Request.Context(), property); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item property not found"))
+			return
+		}
+		apierror.Respond(c, err)
 		return
 	}
 
+	setETag(c, property.Version)
 	c.Header("Content-Type", jsonapi.MediaType)
 	if err := jsonapi.MarshalPayload(c.Writer, property); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apierror.Respond(c, err)
 	}
 }
 
+// Patch partially updates an item property
+// @Summary      Partially update an item property
+// @Description  Change the attributes of an item property present in the document (name, value), keeping the others; the merged property is validated. With If-Match, only if the property is still at that version
+// @Tags         item_properties
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id           path      string               true  "Item ID (UUID format)"
+// @Param        property_id  path      string               true  "Property ID (UUID format)"
+// @Param        If-Match     header    string               false  "ETag of the version to update, or *; required when REQUIRE_IF_MATCH is set"
+// @Param        property     body      JSONAPIItemProperty true  "Attributes to change"
+// @Success      200          {object}  JSONAPIItemPropertyResponse "Updated Item Property"
+// @Header       200          {string}  ETag "New version of the item property"
+// @Failure      400          {object}  apierror.Document
+// @Failure      401          {object}  apierror.Document
+// @Failure      403          {object}  apierror.Document
+// @Failure      404          {object}  apierror.Document
+// @Failure      412          {object}  apierror.Document
+// @Failure      428          {object}  apierror.Document
+// @Failure      500          {object}  apierror.Document
+// @Router       /v1/items/{id}/properties/{property_id} [patch]
 func (h *ItemPropertyHandler) Patch(c *gin.Context) {
-	h.Update(c)
+	itemID := c.Param("id")
+	id := c.Param("property_id")
+
+	// Validate UUID format for item ID
+	if !isValidUUID(itemID) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
+		return
+	}
+
+	// Validate UUID format for property ID
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for property ID"))
+		return
+	}
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	patch := new(domain.ItemProperty)
+	mask, err := decodePatch(c, patch, domain.ItemPropertyPatchFields)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	patch.Version = version
+
+	property, err := h.Service.PatchItemProperty(c.Request.Context(), itemID, id, patch, mask)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item property not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	setETag(c, property.Version)
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := jsonapi.MarshalPayload(c.Writer, property); err != nil {
+		apierror.Respond(c, err)
+	}
 }
 
 // Delete deletes an item property
 // @Summary      Delete an item property
-// @Description  Delete an item property by ID for a specific item
+// @Description  Delete an item property by ID for a specific item; with If-Match, only if the property is still at that version
 // @Tags         item_properties
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id           path      string  true  "Item ID (UUID format)"
 // @Param        property_id  path      string  true  "Property ID (UUID format)"
+// @Param        If-Match     header    string  false  "ETag of the version to delete, or *; required when REQUIRE_IF_MATCH is set"
 // @Success      204          {object}  nil
-// @Failure      400          {object}  map[string]string
-// @Failure      500          {object}  map[string]string
+// @Failure      400          {object}  apierror.Document
+// @Failure      401          {object}  apierror.Document
+// @Failure      403          {object}  apierror.Document
+// @Failure      404          {object}  apierror.Document
+// @Failure      412          {object}  apierror.Document
+// @Failure      428          {object}  apierror.Document
+// @Failure      500          {object}  apierror.Document
 // @Router       /v1/items/{id}/properties/{property_id} [delete]
 func (h *ItemPropertyHandler) Delete(c *gin.Context) {
 	itemID := c.Param("id")
@@ -226,18 +383,28 @@ func (h *ItemPropertyHandler) Delete(c *gin.Context) {
 
 	// Validate UUID format for item ID
 	if !isValidUUID(itemID) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for item ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
 		return
 	}
 
 	// Validate UUID format for property ID
 	if !isValidUUID(id) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format for property ID"})
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for property ID"))
+		return
+	}
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
 		return
 	}
 
-	if err := h.Service.DeleteItemProperty(c.Request.Context(), itemID, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Service.DeleteItemProperty(c.Request.Context(), itemID, id, version); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item property not found"))
+			return
+		}
+		apierror.Respond(c, err)
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -20,16 +21,24 @@ type MockItemPropertyService struct {
 	mock.Mock
 }
 
-func (m *MockItemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string) ([]*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID)
+func (m *MockItemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string, page domain.PageRequest, opts domain.QueryOptions) (*domain.Page[*domain.ItemProperty], error) {
+	args := m.Called(ctx, itemID, page, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.ItemProperty), args.Error(1)
+	return args.Get(0).(*domain.Page[*domain.ItemProperty]), args.Error(1)
 }
 
-func (m *MockItemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string) (*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string, opts domain.QueryOptions) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, itemID, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemProperty), args.Error(1)
+}
+
+func (m *MockItemPropertyService) FindItemProperty(ctx context.Context, id string) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -46,8 +55,31 @@ func (m *MockItemPropertyService) UpdateItemProperty(ctx context.Context, itemPr
 	return args.Error(0)
 }
 
-func (m *MockItemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string) error {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyService) PatchItemProperty(ctx context.Context, itemID string, id string, patch *domain.ItemProperty, mask domain.FieldMask) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, itemID, id, patch, mask)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemProperty), args.Error(1)
+}
+
+func (m *MockItemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string, version int64) error {
+	args := m.Called(ctx, itemID, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyService) AttachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyService) ReplaceItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyService) DetachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
 	return args.Error(0)
 }
 
@@ -56,13 +88,16 @@ func TestItemPropertyHandler_GetAll(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
-	expectedProperties := []*domain.ItemProperty{
-		{ID: "550e8400-e29b-41d4-a716-446655440001", ItemID: itemID, Name: "color", Value: "red"},
+	expectedProperties := &domain.Page[*domain.ItemProperty]{
+		Items: []*domain.ItemProperty{
+			{ID: "550e8400-e29b-41d4-a716-446655440001", ItemID: itemID, Name: "color", Value: "red"},
+		},
+		Total: 1,
 	}
-	svc.On("GetItemPropertiesByItemID", mock.Anything, itemID).Return(expectedProperties, nil)
+	svc.On("GetItemPropertiesByItemID", mock.Anything, itemID, domain.PageRequest{Size: 20}, domain.QueryOptions{}).Return(expectedProperties, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -79,7 +114,7 @@ func TestItemPropertyHandler_GetAll_InvalidItemUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -95,10 +130,10 @@ func TestItemPropertyHandler_GetAll_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
-	svc.On("GetItemPropertiesByItemID", mock.Anything, itemID).Return(nil, errors.New("database error"))
+	svc.On("GetItemPropertiesByItemID", mock.Anything, itemID, mock.Anything, domain.QueryOptions{}).Return(nil, errors.New("database error"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -116,12 +151,12 @@ func TestItemPropertyHandler_GetByID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
 	property := &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}
-	svc.On("GetItemPropertyByID", mock.Anything, itemID, propertyID).Return(property, nil)
+	svc.On("GetItemPropertyByID", mock.Anything, itemID, propertyID, domain.QueryOptions{}).Return(property, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -141,7 +176,7 @@ func TestItemPropertyHandler_GetByID_InvalidItemUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -160,7 +195,7 @@ func TestItemPropertyHandler_GetByID_InvalidPropertyUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -179,11 +214,11 @@ func TestItemPropertyHandler_GetByID_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
-	svc.On("GetItemPropertyByID", mock.Anything, itemID, propertyID).Return(nil, errors.New("not found"))
+	svc.On("GetItemPropertyByID", mock.Anything, itemID, propertyID, domain.QueryOptions{}).Return(nil, domain.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -204,7 +239,7 @@ func TestItemPropertyHandler_Create(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	property := &domain.ItemProperty{Name: "color", Value: "red"}
@@ -231,7 +266,7 @@ func TestItemPropertyHandler_Create_InvalidItemUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	property := &domain.ItemProperty{Name: "color", Value: "red"}
 
@@ -253,7 +288,7 @@ func TestItemPropertyHandler_Create_MissingName(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	property := &domain.ItemProperty{Value: "red"} // Missing Name
@@ -276,7 +311,7 @@ func TestItemPropertyHandler_Create_MissingValue(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	property := &domain.ItemProperty{Name: "color"} // Missing Value
@@ -299,7 +334,7 @@ func TestItemPropertyHandler_Create_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	property := &domain.ItemProperty{Name: "color", Value: "red"}
@@ -325,7 +360,7 @@ func TestItemPropertyHandler_Update(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
@@ -352,11 +387,38 @@ func TestItemPropertyHandler_Update(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestItemPropertyHandler_Patch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+	propertyID := "550e8400-e29b-41d4-a716-446655440001"
+	svc.On("PatchItemProperty", mock.Anything, itemID, propertyID, mock.MatchedBy(func(p *domain.ItemProperty) bool {
+		return p.Value == "blue"
+	}), domain.FieldMask{"value"}).Return(&domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "blue"}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{
+		{Key: "id", Value: itemID},
+		{Key: "property_id", Value: propertyID},
+	}
+	c.Request, _ = http.NewRequest(http.MethodPatch, "/items/"+itemID+"/item_properties/"+propertyID,
+		bytes.NewBufferString(`{"data":{"type":"item_properties","attributes":{"value":"blue"}}}`))
+
+	handler.Patch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"color"`)
+	svc.AssertExpectations(t)
+}
+
 func TestItemPropertyHandler_Update_InvalidItemUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	property := &domain.ItemProperty{Name: "color", Value: "blue"}
 
@@ -381,7 +443,7 @@ func TestItemPropertyHandler_Update_InvalidPropertyUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	property := &domain.ItemProperty{Name: "color", Value: "blue"}
 
@@ -406,7 +468,7 @@ func TestItemPropertyHandler_Update_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
@@ -433,7 +495,7 @@ func TestItemPropertyHandler_Update_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
@@ -463,11 +525,11 @@ func TestItemPropertyHandler_Delete(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
-	svc.On("DeleteItemProperty", mock.Anything, itemID, propertyID).Return(nil)
+	svc.On("DeleteItemProperty", mock.Anything, itemID, propertyID, int64(0)).Return(nil)
 
 	w := httptest.NewRecorder()
 	c, r := gin.CreateTestContext(w)
@@ -488,7 +550,7 @@ func TestItemPropertyHandler_Delete_InvalidItemUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -507,7 +569,7 @@ func TestItemPropertyHandler_Delete_InvalidPropertyUUID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -526,11 +588,11 @@ func TestItemPropertyHandler_Delete_ServiceError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc := new(MockItemPropertyService)
 	validator := newTestValidator()
-	handler := NewItemPropertyHandler(svc, validator)
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
 
 	itemID := "550e8400-e29b-41d4-a716-446655440000"
 	propertyID := "550e8400-e29b-41d4-a716-446655440001"
-	svc.On("DeleteItemProperty", mock.Anything, itemID, propertyID).Return(errors.New("database error"))
+	svc.On("DeleteItemProperty", mock.Anything, itemID, propertyID, int64(0)).Return(errors.New("database error"))
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -545,3 +607,27 @@ func TestItemPropertyHandler_Delete_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	svc.AssertExpectations(t)
 }
+
+func TestItemPropertyHandler_Delete_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	validator := newTestValidator()
+	handler := NewItemPropertyHandler(svc, validator, newTestConfig())
+
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+	propertyID := "550e8400-e29b-41d4-a716-446655440001"
+	svc.On("DeleteItemProperty", mock.Anything, itemID, propertyID, int64(0)).Return(fmt.Errorf("item property: %w", domain.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{
+		{Key: "id", Value: itemID},
+		{Key: "property_id", Value: propertyID},
+	}
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/items/"+itemID+"/properties/"+propertyID, nil)
+
+	handler.Delete(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+}
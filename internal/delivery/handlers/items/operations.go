@@ -0,0 +1,465 @@
+package items
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+)
+
+// atomicExtension is the URI of the JSON:API Atomic Operations extension.
+const atomicExtension = "https://jsonapi.org/ext/atomic"
+
+// atomicMediaType is the media type of the documents of the Atomic Operations extension.
+var atomicMediaType = fmt.Sprintf("%s; ext=%q", jsonapi.MediaType, atomicExtension)
+
+// OperationsHandler serves atomic requests, which add, update and remove items and item
+// properties together, as described by the JSON:API Atomic Operations extension.
+type OperationsHandler struct {
+	Service domain.OperationService
+	Logger  logging.Logger
+	// RequireIfMatch refuses updates and removals without meta.version
+	RequireIfMatch bool
+	// MaxOperations is the largest number of operations in a request
+	MaxOperations int
+}
+
+func NewOperationsHandler(service domain.OperationService, logger logging.Logger, cfg *config.Config) *OperationsHandler {
+	return &OperationsHandler{
+		Service:        service,
+		Logger:         logger,
+		RequireIfMatch: cfg.RequireIfMatch,
+		MaxOperations:  cfg.BulkMaxItems,
+	}
+}
+
+// atomicRef identifies a resource of an atomic request: a stored one by its id, or one
+// added by an earlier operation of the request by the lid the request gave it.
+type atomicRef struct {
+	Type         string                     `json:"type"`
+	ID           string                     `json:"id"`
+	LID          string                     `json:"lid"`
+	Relationship string                     `json:"relationship"`
+	Meta         map[string]json.RawMessage `json:"meta"`
+}
+
+// atomicResource is the resource object of an operation.
+type atomicResource struct {
+	atomicRef
+	Attributes    map[string]json.RawMessage `json:"attributes"`
+	Relationships map[string]struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"relationships"`
+}
+
+// atomicOperation is an operation of an atomic request.
+type atomicOperation struct {
+	Op   domain.OperationCode `json:"op"`
+	Ref  *atomicRef           `json:"ref"`
+	Href string               `json:"href"`
+	Data json.RawMessage      `json:"data"`
+}
+
+// atomicResult is the result of an operation: the resource it added or updated, if any.
+type atomicResult struct {
+	Data *jsonapi.Node `json:"data,omitempty"`
+}
+
+// atomicDocument is the response document of an atomic request, with the result of each
+// operation in the order of the request.
+type atomicDocument struct {
+	Results []atomicResult `json:"atomic:results"`
+}
+
+// Operations applies the operations of an atomic request
+// @Summary      Apply atomic operations
+// @Description  add, update and remove items and item properties in one transaction, as described by the JSON:API Atomic Operations extension (https://jsonapi.org/ext/atomic). The request must be sent with the ext="https://jsonapi.org/ext/atomic" media type parameter. An added resource may be given a lid, by which later operations refer to it (for instance in the item relationship of a property); item properties are updated and removed by id alone. Updates are partial, and updates and removals apply only at the version in meta.version when it is given (required when REQUIRE_IF_MATCH is set). Either every operation is applied, with a result for each in atomic:results, or none is, and the errors of the operation that failed point into it
+// @Tags         operations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        operations  body      JSONAPIOperations true  "Operations"
+// @Success      200         {object}  JSONAPIOperationsResponse "Results"
+// @Success      204         {object}  nil "Applied, with no resource to return"
+// @Failure      400         {object}  apierror.Document
+// @Failure      401         {object}  apierror.Document
+// @Failure      403         {object}  apierror.Document
+// @Failure      404         {object}  apierror.Document
+// @Failure      409         {object}  apierror.Document
+// @Failure      412         {object}  apierror.Document
+// @Failure      413         {object}  apierror.Document
+// @Failure      415         {object}  apierror.Document
+// @Failure      428         {object}  apierror.Document
+// @Failure      500         {object}  apierror.Document
+// @Router       /v1/operations [post]
+func (h *OperationsHandler) Operations(c *gin.Context) {
+	if !isAtomicMediaType(c.GetHeader("Content-Type")) {
+		apierror.Write(c, apierror.New(http.StatusUnsupportedMediaType, "unsupported_media_type",
+			fmt.Sprintf("Atomic operations must be sent as %s", atomicMediaType)))
+		return
+	}
+	principal, ok := domain.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		apierror.Write(c, apierror.Unauthorized())
+		return
+	}
+
+	h.Logger.LogRequest(c)
+
+	decoder := &operationDecoder{
+		ids:            map[resourceIdentifier]string{},
+		propertyItems:  map[string]string{},
+		owner:          principal.Subject,
+		now:            time.Now(),
+		requireVersion: h.RequireIfMatch,
+	}
+	operations, err := decoder.decode(c, h.MaxOperations)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := h.Service.ApplyOperations(c.Request.Context(), operations); err != nil {
+		var operationErr *domain.OperationError
+		if errors.As(err, &operationErr) {
+			apierror.Write(c, operationErrors(operationErr, operations[operationErr.Index].Code)...)
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := writeOperationResults(c, operations); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// isAtomicMediaType reports whether contentType is the JSON:API media type with the
+// Atomic Operations extension among its ext parameter.
+func isAtomicMediaType(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != jsonapi.MediaType {
+		return false
+	}
+	return slices.Contains(strings.Fields(params["ext"]), atomicExtension)
+}
+
+// operationDecoder reads the operations of an atomic request. Resources are added under
+// IDs generated as they are decoded, so the lids of the request resolve before any
+// operation is applied.
+type operationDecoder struct {
+	// ids maps the type and lid of the resources added by the request to their IDs
+	ids map[resourceIdentifier]string
+	// propertyItems maps the IDs of the properties added by the request to their items
+	propertyItems map[string]string
+	// owner owns the items added, created at now
+	owner string
+	now   time.Time
+	// requireVersion refuses updates and removals without meta.version
+	requireVersion bool
+}
+
+// decode reads the atomic:operations of the request document, at most maxOperations of them.
+func (d *operationDecoder) decode(c *gin.Context, maxOperations int) ([]*domain.Operation, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+	var doc struct {
+		Operations json.RawMessage `json:"atomic:operations"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(doc.Operations, &raw); err != nil || len(raw) == 0 {
+		return nil, apierror.InvalidDocument(errors.New("atomic:operations must be a non-empty array of operations")).
+			WithPointer("/atomic:operations")
+	}
+	if len(raw) > maxOperations {
+		return nil, apierror.New(http.StatusRequestEntityTooLarge, "too_many_operations",
+			fmt.Sprintf("An atomic request may not have more than %d operations", maxOperations)).WithPointer("/atomic:operations")
+	}
+
+	operations := make([]*domain.Operation, len(raw))
+	for i, r := range raw {
+		base := fmt.Sprintf("/atomic:operations/%d", i)
+		var op atomicOperation
+		if err := json.Unmarshal(r, &op); err != nil {
+			return nil, apierror.InvalidDocument(err).WithPointer(base)
+		}
+		if op.Href != "" {
+			return nil, apierror.InvalidDocument(errors.New("operations target resources by ref, not href")).WithPointer(base + "/href")
+		}
+		if op.Ref != nil && op.Ref.Relationship != "" {
+			return nil, apierror.InvalidDocument(errors.New("operations on relationships are not supported")).WithPointer(base + "/ref/relationship")
+		}
+
+		switch op.Op {
+		case domain.OperationAdd:
+			operations[i], err = d.add(op, base)
+		case domain.OperationUpdate:
+			operations[i], err = d.update(op, base)
+		case domain.OperationRemove:
+			operations[i], err = d.remove(op, base)
+		default:
+			err = apierror.InvalidDocument(errors.New("op must be add, update or remove")).WithPointer(base + "/op")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return operations, nil
+}
+
+// add reads an operation adding a resource. Like the creation endpoints, it ignores the ID
+// of the resource in favour of a new one, and adds items owned by the caller without properties.
+func (d *operationDecoder) add(op atomicOperation, base string) (*domain.Operation, error) {
+	if op.Ref != nil {
+		return nil, apierror.InvalidDocument(errors.New("an add operation has no ref")).WithPointer(base + "/ref")
+	}
+	resource, err := decodeAtomicResource(op.Data, base+"/data")
+	if err != nil {
+		return nil, err
+	}
+	id := uuid.New().String()
+	if resource.LID != "" {
+		lid := resourceIdentifier{Type: resource.Type, ID: resource.LID}
+		if _, ok := d.ids[lid]; ok {
+			return nil, apierror.InvalidDocument(errors.New("lid identifies another resource of the request")).WithPointer(base + "/data/lid")
+		}
+		d.ids[lid] = id
+	}
+
+	operation := &domain.Operation{Code: domain.OperationAdd}
+	switch resource.Type {
+	case domain.ResourceItems:
+		if _, ok := resource.Relationships[domain.ResourceItemProperties]; ok {
+			return nil, apierror.InvalidDocument(errors.New("item properties are added by operations of their own")).
+				WithPointer(base + "/data/relationships/item_properties")
+		}
+		item := new(domain.Item)
+		if err := resource.unmarshal(item, base+"/data"); err != nil {
+			return nil, err
+		}
+		item.ID = id
+		item.OwnerID = d.owner
+		item.CreatedAt = &d.now
+		operation.Item = item
+	case domain.ResourceItemProperties:
+		property := new(domain.ItemProperty)
+		if err := resource.unmarshal(property, base+"/data"); err != nil {
+			return nil, err
+		}
+		property.ID = id
+		if property.ItemID, err = d.propertyItem(resource, property.ItemID, base+"/data"); err != nil {
+			return nil, err
+		}
+		d.propertyItems[id] = property.ItemID
+		operation.Property = property
+	default:
+		return nil, invalidResourceType(base + "/data/type")
+	}
+	return operation, nil
+}
+
+// update reads an operation updating the attributes of a resource present in its data.
+func (d *operationDecoder) update(op atomicOperation, base string) (*domain.Operation, error) {
+	resource, err := decodeAtomicResource(op.Data, base+"/data")
+	if err != nil {
+		return nil, err
+	}
+	if op.Ref != nil && (op.Ref.Type != resource.Type || op.Ref.ID != resource.ID || op.Ref.LID != resource.LID) {
+		return nil, apierror.InvalidDocument(errors.New("ref must identify the resource of data")).WithPointer(base + "/ref")
+	}
+	id, version, err := d.target(resource.atomicRef, base+"/data")
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &domain.Operation{Code: domain.OperationUpdate}
+	switch resource.Type {
+	case domain.ResourceItems:
+		patch := new(domain.Item)
+		if err := resource.unmarshal(patch, base+"/data"); err != nil {
+			return nil, err
+		}
+		*patch = domain.Item{ID: id, Title: patch.Title, Description: patch.Description, Version: version}
+		operation.Item = patch
+		operation.Mask = patchMask(resource.Attributes, domain.ItemPatchFields)
+	case domain.ResourceItemProperties:
+		patch := new(domain.ItemProperty)
+		if err := resource.unmarshal(patch, base+"/data"); err != nil {
+			return nil, err
+		}
+		*patch = domain.ItemProperty{ID: id, ItemID: d.propertyItems[id], Name: patch.Name, Value: patch.Value, Version: version}
+		operation.Property = patch
+		operation.Mask = patchMask(resource.Attributes, domain.ItemPropertyPatchFields)
+	default:
+		return nil, invalidResourceType(base + "/data/type")
+	}
+	return operation, nil
+}
+
+// remove reads an operation removing the resource of its ref.
+func (d *operationDecoder) remove(op atomicOperation, base string) (*domain.Operation, error) {
+	if op.Ref == nil {
+		return nil, apierror.InvalidDocument(errors.New("a remove operation must have a ref")).WithPointer(base)
+	}
+	id, version, err := d.target(*op.Ref, base+"/ref")
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &domain.Operation{Code: domain.OperationRemove}
+	switch op.Ref.Type {
+	case domain.ResourceItems:
+		operation.Item = &domain.Item{ID: id, Version: version}
+	case domain.ResourceItemProperties:
+		operation.Property = &domain.ItemProperty{ID: id, ItemID: d.propertyItems[id], Version: version}
+	default:
+		return nil, invalidResourceType(base + "/ref/type")
+	}
+	return operation, nil
+}
+
+// target resolves the resource an update or removal changes, at pointer, and the version
+// in its meta.version.
+func (d *operationDecoder) target(ref atomicRef, pointer string) (string, int64, error) {
+	id, err := d.resolve(ref, pointer)
+	if err != nil {
+		return "", 0, err
+	}
+	version, err := metaVersion(ref.Meta, pointer+"/meta/version")
+	if err != nil {
+		return "", 0, err
+	}
+	if version == 0 && d.requireVersion {
+		return "", 0, versionRequired(pointer + "/meta/version")
+	}
+	return id, version, nil
+}
+
+// resolve returns the ID of the resource ref identifies, at pointer.
+func (d *operationDecoder) resolve(ref atomicRef, pointer string) (string, error) {
+	switch {
+	case ref.ID != "" && ref.LID != "":
+		return "", apierror.InvalidDocument(errors.New("a resource is identified by id or lid, not both")).WithPointer(pointer)
+	case ref.LID != "":
+		id, ok := d.ids[resourceIdentifier{Type: ref.Type, ID: ref.LID}]
+		if !ok {
+			return "", apierror.InvalidDocument(errors.New("lid must identify a resource added by an earlier operation")).WithPointer(pointer + "/lid")
+		}
+		return id, nil
+	case !isValidUUID(ref.ID):
+		return "", apierror.BadRequest("Invalid UUID format").WithPointer(pointer + "/id")
+	default:
+		return ref.ID, nil
+	}
+}
+
+// propertyItem returns the item a new property belongs to: the one its item relationship
+// links to, or otherwise the one its item_id attribute names.
+func (d *operationDecoder) propertyItem(resource *atomicResource, itemID string, pointer string) (string, error) {
+	relationship, ok := resource.Relationships["item"]
+	if !ok {
+		if !isValidUUID(itemID) {
+			return "", invalidLinkage(pointer+"/relationships/item", "item properties must link to their item")
+		}
+		return itemID, nil
+	}
+	var linkage *atomicRef
+	if err := json.Unmarshal(relationship.Data, &linkage); err != nil || linkage == nil || linkage.Type != domain.ResourceItems {
+		return "", invalidLinkage(pointer+"/relationships/item/data", "item must link to an item")
+	}
+	return d.resolve(*linkage, pointer+"/relationships/item/data")
+}
+
+// decodeAtomicResource reads the resource object of an operation, at pointer.
+func decodeAtomicResource(data json.RawMessage, pointer string) (*atomicResource, error) {
+	var resource *atomicResource
+	if err := json.Unmarshal(data, &resource); err != nil || resource == nil {
+		return nil, apierror.InvalidDocument(errors.New("data must be a resource object")).WithPointer(pointer)
+	}
+	return resource, nil
+}
+
+// unmarshal reads the attributes of the resource into model, whose type it must have.
+func (r *atomicResource) unmarshal(model interface{}, pointer string) error {
+	payload, err := json.Marshal(map[string]any{
+		"data": map[string]any{"type": r.Type, "attributes": r.Attributes},
+	})
+	if err != nil {
+		return apierror.InvalidDocument(err).WithPointer(pointer)
+	}
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(payload), model); err != nil {
+		return apierror.InvalidDocument(err).WithPointer(pointer + "/attributes")
+	}
+	return nil
+}
+
+// invalidResourceType reports an operation on a resource other than items and item properties.
+func invalidResourceType(pointer string) *apierror.Error {
+	return apierror.InvalidDocument(fmt.Errorf("type must be %s or %s", domain.ResourceItems, domain.ResourceItemProperties)).
+		WithPointer(pointer)
+}
+
+// operationErrors maps the error of the operation that failed an atomic request to error
+// objects located in that operation (see locateErrors): the errors of its resource point
+// into its data, and a version mismatch to the meta.version it was given in.
+func operationErrors(err *domain.OperationError, code domain.OperationCode) []*apierror.Error {
+	base := fmt.Sprintf("/atomic:operations/%d", err.Index)
+	version := base + "/data/meta/version"
+	if code == domain.OperationRemove {
+		version = base + "/ref/meta/version"
+	}
+	return locateErrors(err.Err, base+"/data", version, base)
+}
+
+// writeOperationResults writes the result of each operation: the resource it added or
+// updated, with its version in meta.version, and an empty result for removals. Requests
+// that only remove resources have no results to return, and get 204.
+func writeOperationResults(c *gin.Context, operations []*domain.Operation) error {
+	doc := atomicDocument{Results: make([]atomicResult, len(operations))}
+	empty := true
+	for i, operation := range operations {
+		if operation.Code == domain.OperationRemove {
+			continue
+		}
+		var node *jsonapi.Node
+		var err error
+		if operation.Item != nil {
+			node, err = versionedNode(operation.Item, operation.Item.Version)
+		} else {
+			node, err = versionedNode(operation.Property, operation.Property.Version)
+		}
+		if err != nil {
+			return err
+		}
+		doc.Results[i].Data = node
+		empty = false
+	}
+
+	if empty {
+		c.Status(http.StatusNoContent)
+		return nil
+	}
+	c.Header("Content-Type", atomicMediaType)
+	c.Status(http.StatusOK)
+	return json.NewEncoder(c.Writer).Encode(doc)
+}
@@ -0,0 +1,231 @@
+package items
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOperationService implements domain.OperationService for testing
+type MockOperationService struct {
+	mock.Mock
+}
+
+func (m *MockOperationService) ApplyOperations(ctx context.Context, operations []*domain.Operation) error {
+	args := m.Called(ctx, operations)
+	return args.Error(0)
+}
+
+// serveOperations sends body to the operations endpoint of handler, as user-1, with the atomic media type
+func serveOperations(handler *OperationsHandler, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/operations", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`)
+	c.Request = withPrincipal(c.Request, "user-1")
+	handler.Operations(c)
+	return w
+}
+
+// atomicRequest returns the document of an atomic request with the given operations
+func atomicRequest(operations ...string) string {
+	return `{"atomic:operations": [` + strings.Join(operations, ",") + `]}`
+}
+
+func TestOperationsHandler_Operations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockOperationService)
+	handler := NewOperationsHandler(svc, newTestLogger(), newTestConfig())
+
+	var applied []*domain.Operation
+	svc.On("ApplyOperations", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		applied = args.Get(1).([]*domain.Operation)
+		applied[0].Item.Version = 1
+		applied[1].Property.Version = 1
+		applied[2].Property.Version = 4
+	}).Return(nil)
+
+	w := serveOperations(handler, atomicRequest(
+		`{"op": "add", "data": {"type": "items", "lid": "lamp", "attributes": {"title": "Lamp", "owner_id": "user-2"}}}`,
+		`{"op": "add", "data": {"type": "item_properties", "lid": "color", "attributes": {"name": "color", "value": "red"},
+			"relationships": {"item": {"data": {"type": "items", "lid": "lamp"}}}}}`,
+		`{"op": "update", "data": {"type": "item_properties", "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"attributes": {"value": "blue"}, "meta": {"version": 3}}}`,
+		`{"op": "remove", "ref": {"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "meta": {"version": 2}}}`,
+	))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`, w.Header().Get("Content-Type"))
+	require.Len(t, applied, 4)
+
+	// New resources get IDs of their own, which their lids resolve to
+	item := applied[0].Item
+	assert.Equal(t, domain.OperationAdd, applied[0].Code)
+	assert.True(t, isValidUUID(item.ID))
+	assert.Equal(t, "user-1", item.OwnerID)
+	assert.NotNil(t, item.CreatedAt)
+	property := applied[1].Property
+	assert.True(t, isValidUUID(property.ID))
+	assert.Equal(t, item.ID, property.ItemID)
+	assert.Equal(t, "red", property.Value)
+
+	// Updates are partial, and properties are found by ID
+	assert.Equal(t, domain.OperationUpdate, applied[2].Code)
+	assert.Equal(t, &domain.ItemProperty{ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Value: "blue", Version: 4}, applied[2].Property)
+	assert.Equal(t, domain.FieldMask{"value"}, applied[2].Mask)
+
+	assert.Equal(t, domain.OperationRemove, applied[3].Code)
+	assert.Equal(t, &domain.Item{ID: "550e8400-e29b-41d4-a716-446655440000", Version: 2}, applied[3].Item)
+
+	var doc struct {
+		Results []struct {
+			Data *struct {
+				Type       string         `json:"type"`
+				ID         string         `json:"id"`
+				Attributes map[string]any `json:"attributes"`
+				Meta       map[string]any `json:"meta"`
+			} `json:"data"`
+		} `json:"atomic:results"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	require.Len(t, doc.Results, 4)
+	require.NotNil(t, doc.Results[0].Data)
+	assert.Equal(t, "items", doc.Results[0].Data.Type)
+	assert.Equal(t, item.ID, doc.Results[0].Data.ID)
+	assert.Equal(t, float64(1), doc.Results[0].Data.Meta["version"])
+	require.NotNil(t, doc.Results[2].Data)
+	assert.Equal(t, "blue", doc.Results[2].Data.Attributes["value"])
+	assert.Equal(t, float64(4), doc.Results[2].Data.Meta["version"])
+	assert.Nil(t, doc.Results[3].Data)
+}
+
+func TestOperationsHandler_Operations_OnlyRemovals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockOperationService)
+	handler := NewOperationsHandler(svc, newTestLogger(), newTestConfig())
+	svc.On("ApplyOperations", mock.Anything, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.POST("/operations", handler.Operations)
+	req, _ := http.NewRequest(http.MethodPost, "/operations", strings.NewReader(atomicRequest(
+		`{"op": "remove", "ref": {"type": "item_properties", "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}`,
+	)))
+	req.Header.Set("Content-Type", `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`)
+	r.ServeHTTP(w, withPrincipal(req, "user-1"))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestOperationsHandler_Operations_UnsupportedMediaType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockOperationService)
+	handler := NewOperationsHandler(svc, newTestLogger(), newTestConfig())
+
+	for _, contentType := range []string{"application/vnd.api+json", "application/json", `application/vnd.api+json; ext="https://example.com/ext"`} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/operations", strings.NewReader(atomicRequest()))
+		c.Request.Header.Set("Content-Type", contentType)
+		c.Request = withPrincipal(c.Request, "user-1")
+		handler.Operations(c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code, contentType)
+	}
+	svc.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+}
+
+func TestOperationsHandler_Operations_RolledBack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		pointer string
+	}{
+		{
+			name:    "invalid resource",
+			err:     domain.ValidationErrors{{Field: "Name", Pointer: "/data/attributes/name", Message: "Name is required"}},
+			status:  http.StatusBadRequest,
+			pointer: "/atomic:operations/1/data/attributes/name",
+		},
+		{name: "missing resource", err: domain.ErrNotFound, status: http.StatusNotFound, pointer: "/atomic:operations/1"},
+		{name: "changed resource", err: domain.ErrPreconditionFailed, status: http.StatusPreconditionFailed, pointer: "/atomic:operations/1/ref/meta/version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockOperationService)
+			handler := NewOperationsHandler(svc, newTestLogger(), newTestConfig())
+			svc.On("ApplyOperations", mock.Anything, mock.Anything).Return(&domain.OperationError{Index: 1, Err: tt.err})
+
+			w := serveOperations(handler, atomicRequest(
+				`{"op": "add", "data": {"type": "items", "attributes": {"title": "Lamp"}}}`,
+				`{"op": "remove", "ref": {"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "meta": {"version": 2}}}`,
+			))
+
+			assert.Equal(t, tt.status, w.Code)
+			errs := decodeErrors(t, w)
+			require.Len(t, errs, 1)
+			require.NotNil(t, errs[0].Source)
+			assert.Equal(t, tt.pointer, errs[0].Source.Pointer)
+		})
+	}
+}
+
+func TestOperationsHandler_Operations_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockOperationService)
+	handler := NewOperationsHandler(svc, newTestLogger(), newTestConfig())
+	handler.MaxOperations = 2
+
+	addItem := `{"op": "add", "data": {"type": "items", "lid": "lamp", "attributes": {"title": "Lamp"}}}`
+	tests := []struct {
+		name           string
+		body           string
+		requireIfMatch bool
+		status         int
+		pointer        string
+	}{
+		{"no operations", atomicRequest(), false, http.StatusBadRequest, "/atomic:operations"},
+		{"too many operations", atomicRequest(addItem, addItem, addItem), false, http.StatusRequestEntityTooLarge, "/atomic:operations"},
+		{"unknown op", atomicRequest(`{"op": "replace", "data": {"type": "items"}}`), false, http.StatusBadRequest, "/atomic:operations/0/op"},
+		{"unknown type", atomicRequest(`{"op": "add", "data": {"type": "widgets"}}`), false, http.StatusBadRequest, "/atomic:operations/0/data/type"},
+		{"href", atomicRequest(`{"op": "remove", "href": "/api/v1/items/1"}`), false, http.StatusBadRequest, "/atomic:operations/0/href"},
+		{"relationship", atomicRequest(`{"op": "update", "ref": {"type": "items", "id": "1", "relationship": "item_properties"}, "data": []}`), false, http.StatusBadRequest, "/atomic:operations/0/ref/relationship"},
+		{"duplicate lid", atomicRequest(addItem, addItem), false, http.StatusBadRequest, "/atomic:operations/1/data/lid"},
+		{"unknown lid", atomicRequest(`{"op": "remove", "ref": {"type": "items", "lid": "lamp"}}`), false, http.StatusBadRequest, "/atomic:operations/0/ref/lid"},
+		{"invalid id", atomicRequest(`{"op": "remove", "ref": {"type": "items", "id": "1"}}`), false, http.StatusBadRequest, "/atomic:operations/0/ref/id"},
+		{"remove without ref", atomicRequest(`{"op": "remove"}`), false, http.StatusBadRequest, "/atomic:operations/0"},
+		{"property without item", atomicRequest(`{"op": "add", "data": {"type": "item_properties", "attributes": {"name": "color", "value": "red"}}}`), false, http.StatusBadRequest, "/atomic:operations/0/data/relationships/item"},
+		{"property of a lid of another type", atomicRequest(addItem, `{"op": "add", "data": {"type": "item_properties", "attributes": {"name": "color", "value": "red"},
+			"relationships": {"item": {"data": {"type": "item_properties", "lid": "lamp"}}}}}`), false, http.StatusBadRequest, "/atomic:operations/1/data/relationships/item/data"},
+		{"ref of another resource", atomicRequest(`{"op": "update", "ref": {"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000"},
+			"data": {"type": "items", "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "attributes": {"title": "Lamp"}}}`), false, http.StatusBadRequest, "/atomic:operations/0/ref"},
+		{"invalid version", atomicRequest(`{"op": "remove", "ref": {"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "meta": {"version": 0}}}`), false, http.StatusBadRequest, "/atomic:operations/0/ref/meta/version"},
+		{"missing version", atomicRequest(`{"op": "update", "data": {"type": "items", "id": "550e8400-e29b-41d4-a716-446655440000", "attributes": {"title": "Lamp"}}}`), true, http.StatusPreconditionRequired, "/atomic:operations/0/data/meta/version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler.RequireIfMatch = tt.requireIfMatch
+			w := serveOperations(handler, tt.body)
+
+			assert.Equal(t, tt.status, w.Code)
+			errs := decodeErrors(t, w)
+			require.NotNil(t, errs[0].Source)
+			assert.Equal(t, apierror.Source{Pointer: tt.pointer}, *errs[0].Source)
+		})
+	}
+	svc.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,111 @@
+package items
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// parseQueryOptions reads the include and fields[TYPE] query parameters.
+// includes lists the relationship paths that can be included and fields the
+// fields of each resource type the document can contain; anything else is rejected.
+func parseQueryOptions(c *gin.Context, includes []string, fields map[string][]string) (domain.QueryOptions, error) {
+	var opts domain.QueryOptions
+
+	if value := c.Query("include"); value != "" {
+		for _, path := range strings.Split(value, ",") {
+			path = strings.TrimSpace(path)
+			if !slices.Contains(includes, path) {
+				if len(includes) == 0 {
+					return opts, invalidParameter("include", "No related resources can be included here")
+				}
+				return opts, invalidParameter("include", fmt.Sprintf("Unknown relationship path %q, include must list paths among %s", path, strings.Join(includes, ", ")))
+			}
+			if !slices.Contains(opts.Include, path) {
+				opts.Include = append(opts.Include, path)
+			}
+		}
+	}
+
+	params := c.Request.URL.Query()
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name == "fields" || strings.HasPrefix(name, "fields[") {
+			names = append(names, name)
+		}
+	}
+	// Report the first invalid fieldset by name rather than at random
+	sort.Strings(names)
+
+	for _, name := range names {
+		resourceType, ok := strings.CutSuffix(strings.TrimPrefix(name, "fields["), "]")
+		known, found := fields[resourceType]
+		if !ok || !found {
+			return opts, invalidParameter(name, fmt.Sprintf("Unknown fieldset %s", name))
+		}
+
+		// An empty fieldset returns no fields at all
+		fieldset := []string{}
+		if value := params.Get(name); value != "" {
+			for _, field := range strings.Split(value, ",") {
+				field = strings.TrimSpace(field)
+				if !slices.Contains(known, field) {
+					return opts, invalidParameter(name, fmt.Sprintf("Unknown field %q of %s, %s must list fields among %s", field, resourceType, name, strings.Join(known, ", ")))
+				}
+				if !slices.Contains(fieldset, field) {
+					fieldset = append(fieldset, field)
+				}
+			}
+		}
+		if opts.Fields == nil {
+			opts.Fields = map[string][]string{}
+		}
+		opts.Fields[resourceType] = fieldset
+	}
+
+	return opts, nil
+}
+
+// applyFieldsets drops the attributes and relationships of the nodes that are
+// not in the sparse fieldset of their type.
+func applyFieldsets(opts domain.QueryOptions, nodes ...*jsonapi.Node) {
+	for _, node := range nodes {
+		fields, ok := opts.Fieldset(node.Type)
+		if !ok {
+			continue
+		}
+		for name := range node.Attributes {
+			if !slices.Contains(fields, name) {
+				delete(node.Attributes, name)
+			}
+		}
+		for name := range node.Relationships {
+			if !slices.Contains(fields, name) {
+				delete(node.Relationships, name)
+			}
+		}
+	}
+}
+
+// writeResource writes a single resource as a JSON:API document holding the fields opts asks for.
+func writeResource(c *gin.Context, model interface{}, opts domain.QueryOptions) error {
+	payload, err := jsonapi.Marshal(model)
+	if err != nil {
+		return err
+	}
+	one, ok := payload.(*jsonapi.OnePayload)
+	if !ok {
+		return fmt.Errorf("unexpected payload type %T", payload)
+	}
+	applyFieldsets(opts, one.Data)
+	applyFieldsets(opts, one.Included...)
+
+	c.Header("Content-Type", jsonapi.MediaType)
+	return json.NewEncoder(c.Writer).Encode(one)
+}
@@ -0,0 +1,164 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// resourceNode is the part of a resource object that sparse fieldsets shape
+type resourceNode struct {
+	Type          string                     `json:"type"`
+	ID            string                     `json:"id"`
+	Attributes    map[string]interface{}     `json:"attributes"`
+	Relationships map[string]json.RawMessage `json:"relationships"`
+}
+
+// getItem requests the item at target and decodes the response
+func getItem(t *testing.T, svc *MockItemService, id string, target string) (*httptest.ResponseRecorder, resourceNode, []resourceNode) {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	c.Request, _ = http.NewRequest(http.MethodGet, target, nil)
+
+	handler.GetByID(c)
+
+	var doc struct {
+		Data     resourceNode   `json:"data"`
+		Included []resourceNode `json:"included"`
+	}
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	}
+	return w, doc.Data, doc.Included
+}
+
+func TestItemHandler_GetByID_SparseFieldsets(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	item := &domain.Item{
+		ID:          testUUID,
+		Title:       "Lamp",
+		Description: "Teak lamp",
+		ItemProperties: []*domain.ItemProperty{
+			{ID: "550e8400-e29b-41d4-a716-446655440001", ItemID: testUUID, Name: "color", Value: "red"},
+		},
+	}
+	svc := new(MockItemService)
+	svc.On("GetItemByID", mock.Anything, testUUID, domain.QueryOptions{
+		Include: []string{domain.ItemIncludeProperties},
+		Fields: map[string][]string{
+			domain.ResourceItems:          {"title", "item_properties"},
+			domain.ResourceItemProperties: {"name", "value"},
+		},
+	}).Return(item, domain.Validators{}, nil)
+
+	w, data, included := getItem(t, svc, testUUID, "/api/v1/items/"+testUUID+
+		"?include=item_properties&fields[items]=title,item_properties&fields[item_properties]=name,value")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]interface{}{"title": "Lamp"}, data.Attributes)
+	assert.Contains(t, data.Relationships, "item_properties")
+	if assert.Len(t, included, 1) {
+		assert.Equal(t, map[string]interface{}{"name": "color", "value": "red"}, included[0].Attributes)
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetAll_SparseFieldsets(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{
+		Page:    domain.PageRequest{Size: 20},
+		Options: domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"description"}}},
+	}).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", Title: "Lamp", Description: "Teak lamp"}}, Total: 1}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?fields[items]=description")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, doc.Data, 1) {
+		var node resourceNode
+		assert.NoError(t, json.Unmarshal(doc.Data[0], &node))
+		assert.Equal(t, map[string]interface{}{"description": "Teak lamp"}, node.Attributes)
+		assert.Empty(t, node.Relationships)
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_InvalidQueryOptions(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name      string
+		query     string
+		parameter string
+	}{
+		{"unknown include", "include=owner", "include"},
+		{"unknown nested include", "include=item_properties,item_properties.item", "include"},
+		{"unknown fieldset type", "fields[owners]=name", "fields[owners]"},
+		{"unknown field", "fields[items]=title,price", "fields[items]"},
+		{"bare fields", "fields=title", "fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockItemService)
+
+			w, _, _ := getItem(t, svc, testUUID, "/api/v1/items/"+testUUID+"?"+tt.query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"parameter":"`+tt.parameter+`"`)
+			svc.AssertNotCalled(t, "GetItemByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestItemPropertyHandler_GetByID_QueryOptions(t *testing.T) {
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+	propertyID := "550e8400-e29b-41d4-a716-446655440001"
+	property := &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}
+
+	tests := []struct {
+		name       string
+		query      string
+		status     int
+		attributes map[string]interface{}
+	}{
+		{"sparse fieldset", "fields[item_properties]=value", http.StatusOK, map[string]interface{}{"value": "red"}},
+		{"include", "include=item", http.StatusBadRequest, nil},
+		{"items fieldset", "fields[items]=title", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemPropertyService)
+			svc.On("GetItemPropertyByID", mock.Anything, itemID, propertyID, domain.QueryOptions{
+				Fields: map[string][]string{domain.ResourceItemProperties: {"value"}},
+			}).Return(property, nil).Maybe()
+			handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: itemID}, {Key: "property_id", Value: propertyID}}
+			c.Request, _ = http.NewRequest(http.MethodGet, "/items/"+itemID+"/properties/"+propertyID+"?"+tt.query, nil)
+
+			handler.GetByID(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.attributes != nil {
+				var doc struct {
+					Data resourceNode `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+				assert.Equal(t, tt.attributes, doc.Data.Attributes)
+			}
+		})
+	}
+}
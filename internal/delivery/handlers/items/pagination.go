@@ -0,0 +1,120 @@
+package items
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// parsePageRequest reads the page[number], page[size] and page[cursor] query parameters.
+// Without page[number] pages are addressed by cursor, starting with the first
+// page of defaultSize results.
+func parsePageRequest(c *gin.Context, defaultSize int, maxSize int) (domain.PageRequest, error) {
+	page := domain.PageRequest{Size: defaultSize, Cursor: c.Query("page[cursor]")}
+
+	if size, ok := c.GetQuery("page[size]"); ok {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 1 || n > maxSize {
+			return page, invalidParameter("page[size]", fmt.Sprintf("page[size] must be between 1 and %d", maxSize))
+		}
+		page.Size = n
+	}
+
+	if number, ok := c.GetQuery("page[number]"); ok {
+		if page.Cursor != "" {
+			return page, invalidParameter("page[number]", "page[number] and page[cursor] cannot be combined")
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 {
+			return page, invalidParameter("page[number]", "page[number] must be a positive integer")
+		}
+		page.Number = n
+	}
+
+	return page, nil
+}
+
+// pageLinks returns the pagination links of a page of the list at u.
+// Links to pages that do not exist, or cannot be addressed in cursor
+// pagination (prev and last), are null.
+func pageLinks[T any](u *url.URL, page domain.PageRequest, result *domain.Page[T]) *jsonapi.Links {
+	link := func(number int, cursor string) string {
+		query := u.Query()
+		query.Del("page[number]")
+		query.Del("page[cursor]")
+		query.Set("page[size]", strconv.Itoa(page.Size))
+		if number > 0 {
+			query.Set("page[number]", strconv.Itoa(number))
+		}
+		if cursor != "" {
+			query.Set("page[cursor]", cursor)
+		}
+		return u.Path + "?" + query.Encode()
+	}
+
+	links := jsonapi.Links{
+		domain.KeySelfLink:      link(page.Number, page.Cursor),
+		jsonapi.KeyFirstPage:    link(min(page.Number, 1), ""),
+		jsonapi.KeyPreviousPage: nil,
+		jsonapi.KeyNextPage:     nil,
+		jsonapi.KeyLastPage:     nil,
+	}
+
+	if page.Number == 0 {
+		if result.NextCursor != "" {
+			links[jsonapi.KeyNextPage] = link(0, result.NextCursor)
+		}
+		return &links
+	}
+
+	last := int((result.Total + int64(page.Size) - 1) / int64(page.Size))
+	if last < 1 {
+		last = 1
+	}
+	links[jsonapi.KeyLastPage] = link(last, "")
+	if page.Number > 1 {
+		links[jsonapi.KeyPreviousPage] = link(page.Number-1, "")
+	}
+	if page.Number < last {
+		links[jsonapi.KeyNextPage] = link(page.Number+1, "")
+	}
+	return &links
+}
+
+// writePage writes a page of a list as a JSON:API document with pagination
+// links and the total number of results in meta.total, holding the fields opts asks for.
+func writePage[T any](c *gin.Context, page domain.PageRequest, result *domain.Page[T], opts domain.QueryOptions) error {
+	many, err := marshalPage(c, page, result, opts)
+	if err != nil {
+		return err
+	}
+	return writePayload(c, many)
+}
+
+// marshalPage marshals a page of a list as writePage writes it, for callers
+// that add to the document before writing it with writePayload.
+func marshalPage[T any](c *gin.Context, page domain.PageRequest, result *domain.Page[T], opts domain.QueryOptions) (*jsonapi.ManyPayload, error) {
+	payload, err := jsonapi.Marshal(result.Items)
+	if err != nil {
+		return nil, err
+	}
+	many, ok := payload.(*jsonapi.ManyPayload)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payload type %T", payload)
+	}
+	applyFieldsets(opts, many.Data...)
+	applyFieldsets(opts, many.Included...)
+	many.Links = pageLinks(c.Request.URL, page, result)
+	many.Meta = &jsonapi.Meta{"total": result.Total}
+	return many, nil
+}
+
+func writePayload(c *gin.Context, many *jsonapi.ManyPayload) error {
+	c.Header("Content-Type", jsonapi.MediaType)
+	return json.NewEncoder(c.Writer).Encode(many)
+}
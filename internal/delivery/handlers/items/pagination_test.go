@@ -0,0 +1,161 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pageDocument is the part of a list response that carries pagination
+type pageDocument struct {
+	Data  []json.RawMessage  `json:"data"`
+	Links map[string]*string `json:"links"`
+	Meta  map[string]int64   `json:"meta"`
+}
+
+// getItemsPage requests the items list at target and decodes the response
+func getItemsPage(t *testing.T, svc *MockItemService, target string) (*httptest.ResponseRecorder, pageDocument) {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, target, nil)
+
+	handler.GetAll(c)
+
+	var doc pageDocument
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	}
+	return w, doc
+}
+
+// pageLink decodes the page parameters of a pagination link
+func pageLink(t *testing.T, link *string) url.Values {
+	if !assert.NotNil(t, link) {
+		return nil
+	}
+	u, err := url.Parse(*link)
+	assert.NoError(t, err)
+	return u.Query()
+}
+
+func TestItemHandler_GetAll_NumberPagination(t *testing.T) {
+	svc := new(MockItemService)
+	items := []*domain.Item{{ID: "3", Title: "Third"}, {ID: "4", Title: "Fourth"}}
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{
+		Page:    domain.PageRequest{Number: 2, Size: 2},
+		Options: domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}},
+	}).
+		Return(&domain.Page[*domain.Item]{Items: items, Total: 5}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?page[number]=2&page[size]=2&include=item_properties")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, doc.Data, 2)
+	assert.Equal(t, int64(5), doc.Meta["total"])
+
+	assert.Equal(t, "2", pageLink(t, doc.Links["self"]).Get("page[number]"))
+	assert.Equal(t, "1", pageLink(t, doc.Links["first"]).Get("page[number]"))
+	assert.Equal(t, "1", pageLink(t, doc.Links["prev"]).Get("page[number]"))
+	assert.Equal(t, "3", pageLink(t, doc.Links["next"]).Get("page[number]"))
+	assert.Equal(t, "3", pageLink(t, doc.Links["last"]).Get("page[number]"))
+
+	// Links keep the other query parameters and the page size
+	next := pageLink(t, doc.Links["next"])
+	assert.Equal(t, "2", next.Get("page[size]"))
+	assert.Equal(t, "item_properties", next.Get("include"))
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetAll_LastPage(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{Page: domain.PageRequest{Number: 1, Size: 20}}).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}, Total: 0}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?page[number]=1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(0), doc.Meta["total"])
+	assert.Contains(t, doc.Links, "prev")
+	assert.Nil(t, doc.Links["prev"])
+	assert.Contains(t, doc.Links, "next")
+	assert.Nil(t, doc.Links["next"])
+	assert.Equal(t, "1", pageLink(t, doc.Links["last"]).Get("page[number]"))
+}
+
+func TestItemHandler_GetAll_FirstCursorPage(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{Page: domain.PageRequest{Size: 2}}).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1"}, {ID: "2"}}, Total: 5, NextCursor: "abc"}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?page[size]=2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	first := pageLink(t, doc.Links["first"])
+	assert.Empty(t, first.Get("page[number]"))
+	assert.Empty(t, first.Get("page[cursor]"))
+	assert.Equal(t, "2", first.Get("page[size]"))
+	assert.Equal(t, "abc", pageLink(t, doc.Links["next"]).Get("page[cursor]"))
+	assert.Nil(t, doc.Links["prev"])
+	assert.Nil(t, doc.Links["last"])
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetAll_CursorPagination(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, domain.ItemQuery{Page: domain.PageRequest{Size: 2, Cursor: "abc"}}).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "3"}, {ID: "4"}}, Total: 5, NextCursor: "def"}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?page[cursor]=abc&page[size]=2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(5), doc.Meta["total"])
+	assert.Equal(t, "abc", pageLink(t, doc.Links["self"]).Get("page[cursor]"))
+	assert.Equal(t, "def", pageLink(t, doc.Links["next"]).Get("page[cursor]"))
+	assert.Empty(t, pageLink(t, doc.Links["next"]).Get("page[number]"))
+	assert.Nil(t, doc.Links["prev"])
+	assert.Nil(t, doc.Links["last"])
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetAll_InvalidPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"size above maximum", "page[size]=101"},
+		{"zero size", "page[size]=0"},
+		{"non-numeric size", "page[size]=ten"},
+		{"zero number", "page[number]=0"},
+		{"number with cursor", "page[number]=2&page[cursor]=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockItemService)
+
+			w, _ := getItemsPage(t, svc, "/api/v1/items?"+tt.query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			svc.AssertNotCalled(t, "GetAllItems", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestItemHandler_GetAll_InvalidCursor(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, mock.Anything).Return(nil, domain.Validators{}, domain.ErrInvalidCursor)
+
+	w, _ := getItemsPage(t, svc, "/api/v1/items?page[cursor]=garbage")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,51 @@
+package items
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// decodePatch reads the JSON:API document of a partial update into model and returns
+// the mask of the attributes the document sets, among the patchable ones. Attributes
+// the document leaves out, or that the server manages, keep their stored values.
+func decodePatch(c *gin.Context, model interface{}, patchable []string) (domain.FieldMask, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+
+	var doc struct {
+		Data *struct {
+			Attributes map[string]json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+	if doc.Data == nil {
+		return nil, apierror.InvalidDocument(errors.New("the document has no primary data"))
+	}
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), model); err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+
+	return patchMask(doc.Data.Attributes, patchable), nil
+}
+
+// patchMask returns the mask of the patchable attributes present in attributes.
+func patchMask(attributes map[string]json.RawMessage, patchable []string) domain.FieldMask {
+	mask := domain.FieldMask{}
+	for _, field := range patchable {
+		if _, ok := attributes[field]; ok {
+			mask = append(mask, field)
+		}
+	}
+	return mask
+}
@@ -0,0 +1,138 @@
+package items
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// invalidParameter reports a query parameter the request cannot be served with.
+func invalidParameter(parameter string, detail string) error {
+	return apierror.InvalidParameter(parameter, detail)
+}
+
+// filterTimeLayouts are the layouts accepted for time filters, a date meaning midnight UTC.
+var filterTimeLayouts = []string{time.RFC3339Nano, "2006-01-02"}
+
+func parseFilterTime(parameter string, value string) (*time.Time, error) {
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, invalidParameter(parameter, fmt.Sprintf("%s must be an RFC 3339 time or a date (YYYY-MM-DD)", parameter))
+}
+
+// itemFieldsets lists the fields of the resource types in items documents.
+var itemFieldsets = map[string][]string{
+	domain.ResourceItems:          domain.ItemFields,
+	domain.ResourceItemProperties: domain.ItemPropertyFields,
+}
+
+// parseItemOptions reads the include and fields[TYPE] query parameters of items documents.
+func parseItemOptions(c *gin.Context) (domain.QueryOptions, error) {
+	return parseQueryOptions(c, domain.ItemIncludes, itemFieldsets)
+}
+
+// parseItemPropertyOptions reads the fields[item_properties] query parameter
+// of item properties documents, which include no related resources.
+func parseItemPropertyOptions(c *gin.Context) (domain.QueryOptions, error) {
+	return parseQueryOptions(c, nil, map[string][]string{domain.ResourceItemProperties: domain.ItemPropertyFields})
+}
+
+// parseItemQuery reads the filter, sort, page, include and fields query parameters of the items list.
+// Filters and sort fields other than the ones items support are rejected.
+func parseItemQuery(c *gin.Context, defaultSize int, maxSize int) (domain.ItemQuery, error) {
+	page, err := parsePageRequest(c, defaultSize, maxSize)
+	if err != nil {
+		return domain.ItemQuery{}, err
+	}
+	opts, err := parseItemOptions(c)
+	if err != nil {
+		return domain.ItemQuery{}, err
+	}
+	query := domain.ItemQuery{Page: page, Options: opts}
+
+	params := c.Request.URL.Query()
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name == "filter" || strings.HasPrefix(name, "filter[") {
+			names = append(names, name)
+		}
+	}
+	// Report the first invalid filter by name rather than at random
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := params.Get(name)
+		switch {
+		case name == "filter[title]":
+			query.TitlePrefix = value
+		case name == "filter[created_at][gte]":
+			if query.CreatedAt.GTE, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case name == "filter[created_at][gt]":
+			if query.CreatedAt.GT, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case name == "filter[created_at][lte]":
+			if query.CreatedAt.LTE, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case name == "filter[created_at][lt]":
+			if query.CreatedAt.LT, err = parseFilterTime(name, value); err != nil {
+				return query, err
+			}
+		case strings.HasPrefix(name, "filter[property.") && strings.HasSuffix(name, "]") && len(name) > len("filter[property.]"):
+			if query.Properties == nil {
+				query.Properties = map[string]string{}
+			}
+			query.Properties[strings.TrimSuffix(strings.TrimPrefix(name, "filter[property."), "]")] = value
+		default:
+			return query, invalidParameter(name, fmt.Sprintf("Unknown filter %s", name))
+		}
+	}
+
+	if value, ok := c.GetQuery("sort"); ok {
+		for _, field := range strings.Split(value, ",") {
+			sortField := domain.SortField{Field: strings.TrimSpace(field)}
+			if rest, found := strings.CutPrefix(sortField.Field, "-"); found {
+				sortField = domain.SortField{Field: rest, Desc: true}
+			}
+			if !slices.Contains(domain.ItemSortFields, sortField.Field) {
+				return query, invalidParameter("sort", fmt.Sprintf("Items cannot be sorted by %q, sort must list fields among %s", sortField.Field, strings.Join(domain.ItemSortFields, ", ")))
+			}
+			if slices.ContainsFunc(query.Sort, func(s domain.SortField) bool { return s.Field == sortField.Field }) {
+				return query, invalidParameter("sort", fmt.Sprintf("Items are sorted by %q more than once", sortField.Field))
+			}
+			query.Sort = append(query.Sort, sortField)
+		}
+	}
+
+	return query, nil
+}
+
+// parseItemSearch reads the q, page, include and fields query parameters of the items search.
+func parseItemSearch(c *gin.Context, defaultSize int, maxSize int) (domain.ItemSearch, error) {
+	page, err := parsePageRequest(c, defaultSize, maxSize)
+	if err != nil {
+		return domain.ItemSearch{}, err
+	}
+	opts, err := parseItemOptions(c)
+	if err != nil {
+		return domain.ItemSearch{}, err
+	}
+
+	search := domain.ItemSearch{Text: strings.TrimSpace(c.Query("q")), Page: page, Options: opts}
+	if search.Text == "" {
+		return search, invalidParameter("q", "q must contain the words to search for")
+	}
+	return search, nil
+}
@@ -0,0 +1,147 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestItemHandler_GetAll_FilterAndSort(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.March, 31, 12, 30, 0, 0, time.UTC)
+	expected := domain.ItemQuery{
+		TitlePrefix: "App",
+		CreatedAt:   domain.TimeRange{GTE: &from, LT: &until},
+		Properties:  map[string]string{"color": "red", "size": "large"},
+		Sort: []domain.SortField{
+			{Field: domain.ItemSortCreatedAt, Desc: true},
+			{Field: domain.ItemSortTitle},
+		},
+		Page: domain.PageRequest{Size: 20},
+	}
+
+	svc := new(MockItemService)
+	svc.On("GetAllItems", mock.Anything, expected).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}, Total: 0}, domain.Validators{}, nil)
+
+	w, doc := getItemsPage(t, svc, "/api/v1/items?filter[title]=App"+
+		"&filter[created_at][gte]=2024-03-01&filter[created_at][lt]=2024-03-31T12:30:00Z"+
+		"&filter[property.color]=red&filter[property.size]=large&sort=-created_at,title")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Pagination links keep the filters and the sort
+	first := pageLink(t, doc.Links["first"])
+	assert.Equal(t, "App", first.Get("filter[title]"))
+	assert.Equal(t, "-created_at,title", first.Get("sort"))
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetAll_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		parameter string
+	}{
+		{"unknown filter", "filter[owner_id]=alice", "filter[owner_id]"},
+		{"unknown created_at operator", "filter[created_at][eq]=2024-03-01", "filter[created_at][eq]"},
+		{"bare filter", "filter=title", "filter"},
+		{"property without name", "filter[property.]=red", "filter[property.]"},
+		{"invalid time", "filter[created_at][gte]=yesterday", "filter[created_at][gte]"},
+		{"unknown sort field", "sort=owner_id", "sort"},
+		{"empty sort", "sort=", "sort"},
+		{"repeated sort field", "sort=title,-title", "sort"},
+		{"invalid page size", "page[size]=0", "page[size]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockItemService)
+
+			w, _ := getItemsPage(t, svc, "/api/v1/items?"+tt.query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+			var body struct {
+				Errors []*jsonapi.ErrorObject `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if assert.Len(t, body.Errors, 1) {
+				assert.Equal(t, "400", body.Errors[0].Status)
+				assert.Equal(t, "invalid_query_parameter", body.Errors[0].Code)
+				if assert.NotNil(t, body.Errors[0].Meta) {
+					assert.Equal(t, tt.parameter, (*body.Errors[0].Meta)["parameter"])
+				}
+			}
+			svc.AssertNotCalled(t, "GetAllItems", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// searchItems requests the items search at target
+func searchItems(svc *MockItemService, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, target, nil)
+
+	handler.Search(c)
+	return w
+}
+
+func TestItemHandler_Search(t *testing.T) {
+	svc := new(MockItemService)
+	svc.On("SearchItems", mock.Anything, domain.ItemSearch{Text: "teak lamp", Page: domain.PageRequest{Size: 2}}).
+		Return(&domain.Page[*domain.ItemSearchResult]{
+			Items: []*domain.ItemSearchResult{
+				{Item: &domain.Item{ID: "1", Title: "Teak lamp"}, Score: 2.5},
+				{Item: &domain.Item{ID: "2", Title: "Teak table"}, Score: 1.25},
+			},
+			Total:      3,
+			NextCursor: "abc",
+		}, nil)
+
+	w := searchItems(svc, "/api/v1/items/search?q=+teak+lamp+&page[size]=2")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Data []struct {
+			ID   string             `json:"id"`
+			Meta map[string]float64 `json:"meta"`
+		} `json:"data"`
+		Links map[string]*string `json:"links"`
+		Meta  map[string]int64   `json:"meta"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	if assert.Len(t, doc.Data, 2) {
+		assert.Equal(t, "1", doc.Data[0].ID)
+		assert.Equal(t, 2.5, doc.Data[0].Meta["score"])
+		assert.Equal(t, 1.25, doc.Data[1].Meta["score"])
+	}
+	assert.Equal(t, int64(3), doc.Meta["total"])
+	next := pageLink(t, doc.Links["next"])
+	assert.Equal(t, "abc", next.Get("page[cursor]"))
+	assert.Equal(t, " teak lamp ", next.Get("q"))
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_Search_MissingText(t *testing.T) {
+	for _, target := range []string{"/api/v1/items/search", "/api/v1/items/search?q=%20"} {
+		svc := new(MockItemService)
+
+		w := searchItems(svc, target)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"parameter":"q"`)
+		svc.AssertNotCalled(t, "SearchItems", mock.Anything, mock.Anything)
+	}
+}
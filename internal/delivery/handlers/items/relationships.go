@@ -0,0 +1,214 @@
+package items
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// relationshipDocument is a JSON:API document whose primary data is the linkage of a
+// to-many relationship.
+type relationshipDocument struct {
+	Links *jsonapi.Links       `json:"links,omitempty"`
+	Data  []resourceIdentifier `json:"data"`
+	Meta  *jsonapi.Meta        `json:"meta,omitempty"`
+}
+
+// GetRelationship gets a page of the item_properties relationship of an item
+// @Summary      List the item_properties relationship of an item
+// @Description  get a page of the identifiers of the properties of an item, paginated like the properties themselves
+// @Tags         item_properties
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id            path      string  true   "Item ID (UUID format)"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Success      200  {object}  JSONAPIItemPropertiesRelationshipResponse "Item Properties Relationship"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/items/{id}/relationships/item_properties [get]
+func (h *ItemPropertyHandler) GetRelationship(c *gin.Context) {
+	itemID := c.Param("id")
+
+	// Validate UUID format for item ID
+	if !isValidUUID(itemID) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
+		return
+	}
+
+	page, err := parsePageRequest(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	// The linkage only needs the IDs of the properties
+	opts := domain.QueryOptions{Fields: map[string][]string{domain.ResourceItemProperties: {}}}
+	properties, err := h.Service.GetItemPropertiesByItemID(c.Request.Context(), itemID, page, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	doc := relationshipDocument{
+		Links: pageLinks(c.Request.URL, page, properties),
+		Data:  make([]resourceIdentifier, len(properties.Items)),
+		Meta:  &jsonapi.Meta{"total": properties.Total},
+	}
+	(*doc.Links)["related"] = domain.ItemsPath + "/" + itemID + "/" + domain.ResourceItemProperties
+	for i, property := range properties.Items {
+		doc.Data[i] = resourceIdentifier{Type: domain.ResourceItemProperties, ID: property.ID}
+	}
+	c.Header("Content-Type", jsonapi.MediaType)
+	if err := json.NewEncoder(c.Writer).Encode(doc); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// AddRelationship attaches properties to an item
+// @Summary      Attach item properties to an item
+// @Description  Attach the given properties to an item, moving them from the items they belong to, in one transaction; properties already attached are left alone
+// @Tags         item_properties
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path      string                        true  "Item ID (UUID format)"
+// @Param        linkage  body      JSONAPIItemPropertiesLinkage true  "Properties to attach"
+// @Success      204      {object}  nil
+// @Failure      400      {object}  apierror.Document
+// @Failure      401      {object}  apierror.Document
+// @Failure      403      {object}  apierror.Document
+// @Failure      404      {object}  apierror.Document
+// @Failure      500      {object}  apierror.Document
+// @Router       /v1/items/{id}/relationships/item_properties [post]
+func (h *ItemPropertyHandler) AddRelationship(c *gin.Context) {
+	h.changeRelationship(c, h.Service.AttachItemProperties, "Item or item property not found")
+}
+
+// ReplaceRelationship replaces the properties of an item
+// @Summary      Replace the item properties of an item
+// @Description  Make the given properties those of an item, in one transaction: they are moved from the items they belong to, and the other properties of the item are deleted
+// @Tags         item_properties
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path      string                        true  "Item ID (UUID format)"
+// @Param        linkage  body      JSONAPIItemPropertiesLinkage true  "Properties the item has"
+// @Success      204      {object}  nil
+// @Failure      400      {object}  apierror.Document
+// @Failure      401      {object}  apierror.Document
+// @Failure      403      {object}  apierror.Document
+// @Failure      404      {object}  apierror.Document
+// @Failure      500      {object}  apierror.Document
+// @Router       /v1/items/{id}/relationships/item_properties [patch]
+func (h *ItemPropertyHandler) ReplaceRelationship(c *gin.Context) {
+	h.changeRelationship(c, h.Service.ReplaceItemProperties, "Item or item property not found")
+}
+
+// RemoveRelationship detaches properties from an item
+// @Summary      Detach item properties from an item
+// @Description  Detach the given properties from an item, which deletes them since a property cannot exist without its item; properties of other items are left alone
+// @Tags         item_properties
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path      string                        true  "Item ID (UUID format)"
+// @Param        linkage  body      JSONAPIItemPropertiesLinkage true  "Properties to detach"
+// @Success      204      {object}  nil
+// @Failure      400      {object}  apierror.Document
+// @Failure      401      {object}  apierror.Document
+// @Failure      403      {object}  apierror.Document
+// @Failure      404      {object}  apierror.Document
+// @Failure      500      {object}  apierror.Document
+// @Router       /v1/items/{id}/relationships/item_properties [delete]
+func (h *ItemPropertyHandler) RemoveRelationship(c *gin.Context) {
+	h.changeRelationship(c, h.Service.DetachItemProperties, "Item not found")
+}
+
+// changeRelationship changes the item_properties relationship of the item in the path
+// with change, given the properties the request identifies. notFound details the 404
+// the change fails with when something it refers to does not exist.
+func (h *ItemPropertyHandler) changeRelationship(c *gin.Context, change func(ctx context.Context, itemID string, ids []string) error, notFound string) {
+	itemID := c.Param("id")
+
+	// Validate UUID format for item ID
+	if !isValidUUID(itemID) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format for item ID"))
+		return
+	}
+
+	ids, err := decodeItemPropertiesLinkage(c)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := change(c.Request.Context(), itemID, ids); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound(notFound))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// decodeItemPropertiesLinkage reads the IDs of the item properties a relationship document
+// identifies, each once. Its primary data must be an array of item_properties identifiers.
+func decodeItemPropertiesLinkage(c *gin.Context) ([]string, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+
+	var doc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, apierror.InvalidDocument(err)
+	}
+	if len(doc.Data) == 0 || string(doc.Data) == "null" {
+		return nil, apierror.InvalidDocument(errors.New("the document has no primary data"))
+	}
+	var linkage []resourceIdentifier
+	if err := json.Unmarshal(doc.Data, &linkage); err != nil {
+		return nil, invalidLinkage("/data", "item_properties must link to an array of item_properties")
+	}
+
+	ids := make([]string, 0, len(linkage))
+	for i, identifier := range linkage {
+		if identifier.Type != domain.ResourceItemProperties {
+			return nil, invalidLinkage(fmt.Sprintf("/data/%d/type", i), "type must be item_properties")
+		}
+		if !isValidUUID(identifier.ID) {
+			return nil, invalidLinkage(fmt.Sprintf("/data/%d/id", i), "Invalid UUID format for property ID")
+		}
+		if !slices.Contains(ids, identifier.ID) {
+			ids = append(ids, identifier.ID)
+		}
+	}
+	return ids, nil
+}
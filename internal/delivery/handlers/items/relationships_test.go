@@ -0,0 +1,179 @@
+package items
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	relationshipItemID     = "550e8400-e29b-41d4-a716-446655440000"
+	relationshipPropertyID = "550e8400-e29b-41d4-a716-446655440001"
+)
+
+// serveRelationship serves a request to the item_properties relationship of the test item
+func serveRelationship(handler *ItemPropertyHandler, method string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	path := "/items/:id/relationships/item_properties"
+	r.GET(path, handler.GetRelationship)
+	r.POST(path, handler.AddRelationship)
+	r.PATCH(path, handler.ReplaceRelationship)
+	r.DELETE(path, handler.RemoveRelationship)
+
+	req, _ := http.NewRequest(method, "/items/"+relationshipItemID+"/relationships/item_properties", bytes.NewBufferString(body))
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestItemPropertyHandler_GetRelationship(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+
+	page := &domain.Page[*domain.ItemProperty]{
+		Items: []*domain.ItemProperty{{ID: relationshipPropertyID, ItemID: relationshipItemID}},
+		Total: 1,
+	}
+	// Only the IDs of the properties are read
+	opts := domain.QueryOptions{Fields: map[string][]string{domain.ResourceItemProperties: {}}}
+	svc.On("GetItemPropertiesByItemID", mock.Anything, relationshipItemID, domain.PageRequest{Size: 20}, opts).Return(page, nil)
+
+	w := serveRelationship(handler, http.MethodGet, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Links map[string]*string   `json:"links"`
+		Data  []resourceIdentifier `json:"data"`
+		Meta  map[string]int64     `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, []resourceIdentifier{{Type: "item_properties", ID: relationshipPropertyID}}, doc.Data)
+	assert.Equal(t, "/api/v1/items/"+relationshipItemID+"/item_properties", *doc.Links["related"])
+	assert.Contains(t, *doc.Links["self"], "/items/"+relationshipItemID+"/relationships/item_properties?")
+	assert.Equal(t, int64(1), doc.Meta["total"])
+}
+
+func TestItemPropertyHandler_GetRelationship_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+	svc.On("GetItemPropertiesByItemID", mock.Anything, relationshipItemID, mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+
+	w := serveRelationship(handler, http.MethodGet, "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "Item not found", decodeErrors(t, w)[0].Detail)
+}
+
+func TestItemPropertyHandler_ChangeRelationship(t *testing.T) {
+	otherPropertyID := "550e8400-e29b-41d4-a716-446655440002"
+	body := `{"data":[{"type":"item_properties","id":"` + relationshipPropertyID + `"},{"type":"item_properties","id":"` + otherPropertyID + `"},{"type":"item_properties","id":"` + relationshipPropertyID + `"}]}`
+
+	for method, call := range map[string]string{
+		http.MethodPost:   "AttachItemProperties",
+		http.MethodPatch:  "ReplaceItemProperties",
+		http.MethodDelete: "DetachItemProperties",
+	} {
+		t.Run(method, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemPropertyService)
+			handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+			// Each property is identified once
+			svc.On(call, mock.Anything, relationshipItemID, []string{relationshipPropertyID, otherPropertyID}).Return(nil)
+
+			w := serveRelationship(handler, method, body)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestItemPropertyHandler_ReplaceRelationship_Empty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+	svc.On("ReplaceItemProperties", mock.Anything, relationshipItemID, []string{}).Return(nil)
+
+	w := serveRelationship(handler, http.MethodPatch, `{"data":[]}`)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestItemPropertyHandler_AddRelationship_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemPropertyService)
+	handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+	svc.On("AttachItemProperties", mock.Anything, relationshipItemID, []string{relationshipPropertyID}).Return(domain.ErrNotFound)
+
+	w := serveRelationship(handler, http.MethodPost, `{"data":[{"type":"item_properties","id":"`+relationshipPropertyID+`"}]}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestItemPropertyHandler_ChangeRelationship_InvalidLinkage(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		pointer string
+	}{
+		{name: "no primary data", body: `{}`, pointer: "/data"},
+		{name: "null linkage", body: `{"data":null}`, pointer: "/data"},
+		{name: "to-one linkage", body: `{"data":{"type":"item_properties","id":"` + relationshipPropertyID + `"}}`, pointer: "/data"},
+		{name: "wrong type", body: `{"data":[{"type":"items","id":"` + relationshipPropertyID + `"}]}`, pointer: "/data/0/type"},
+		{name: "invalid ID", body: `{"data":[{"type":"item_properties","id":"prop-1"}]}`, pointer: "/data/0/id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemPropertyService)
+			handler := NewItemPropertyHandler(svc, newTestValidator(), newTestConfig())
+
+			w := serveRelationship(handler, http.MethodPost, tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.pointer, decodeErrors(t, w)[0].Source.Pointer)
+			svc.AssertNotCalled(t, "AttachItemProperties", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestItemHandler_GetByID_RelationshipLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(MockItemService)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	item := &domain.Item{ID: relationshipItemID, Title: "Lamp"}
+	svc.On("GetItemByID", mock.Anything, relationshipItemID, domain.QueryOptions{}).Return(item, domain.Validators{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: relationshipItemID}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/items/"+relationshipItemID, nil)
+
+	handler.GetByID(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Data struct {
+			Relationships map[string]struct {
+				Links map[string]string `json:"links"`
+			} `json:"relationships"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, map[string]string{
+		"self":    "/api/v1/items/" + relationshipItemID + "/relationships/item_properties",
+		"related": "/api/v1/items/" + relationshipItemID + "/item_properties",
+	}, doc.Data.Relationships["item_properties"].Links)
+}
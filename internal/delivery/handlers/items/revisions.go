@@ -0,0 +1,140 @@
+package items
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// getAsOf writes the item as it was at the time in the as_of query parameter. Past states
+// are not conditional: they carry no validators, as the item may have changed since.
+func (h *ItemHandler) getAsOf(c *gin.Context, id string, value string, opts domain.QueryOptions) {
+	at, err := parseFilterTime("as_of", value)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	item, err := h.Service.GetItemAsOf(c.Request.Context(), id, *at, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found at the given time"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := writeResource(c, item, opts); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// GetRevisions gets a page of the revisions of an item
+// @Summary      List item revisions
+// @Description  get a page of the revisions of an item, most recent first; each is a snapshot of the item and its properties stored by a change to either, including the deletion of the item
+// @Tags         items
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id            path      string  true   "Item ID (UUID format)"
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Success      200  {object}  JSONAPIItemRevisionListResponse "Item revisions"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/items/{id}/revisions [get]
+func (h *ItemHandler) GetRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	page, err := parsePageRequest(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	revisions, err := h.Service.ListItemRevisions(c.Request.Context(), id, page)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	if err := writePage(c, page, revisions, domain.QueryOptions{}); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// RestoreRevision reverts an item to one of its revisions
+// @Summary      Revert an item to a revision
+// @Description  set the title and description of an item back to those of one of its revisions; the revert is saved as an update, with a new version and revision, and recorded in the audit log as a revert. Properties are not reverted. With If-Match, only if the item is still at that version
+// @Tags         items
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id        path    string  true   "Item ID (UUID format)"
+// @Param        rev       path    int     true   "Revision number"
+// @Param        If-Match  header  string  false  "ETag of the version to revert, or *; required when REQUIRE_IF_MATCH is set"
+// @Success      200  {object}  JSONAPIItemResponse "Reverted item"
+// @Header       200  {string}  ETag "New version of the item"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      412  {object}  apierror.Document
+// @Failure      428  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/items/{id}/revisions/{rev}/restore [post]
+func (h *ItemHandler) RestoreRevision(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+	revision, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil || revision < 1 {
+		apierror.Write(c, apierror.BadRequest("Revision must be a positive integer"))
+		return
+	}
+
+	h.Logger.LogRequest(c)
+
+	version, err := ifMatch(c, h.RequireIfMatch)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	item, err := h.Service.RevertItem(c.Request.Context(), id, revision, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Item or revision not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	setETag(c, item.Version)
+	if err := writeResource(c, item, domain.QueryOptions{}); err != nil {
+		apierror.Respond(c, err)
+	}
+}
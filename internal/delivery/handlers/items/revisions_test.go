@@ -0,0 +1,167 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemHandler_GetByID_AsOf(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		asOf   string
+		err    error
+		status int
+	}{
+		{"found", "2026-03-01T12:00:00Z", nil, http.StatusOK},
+		{"not found then", "2026-03-01T12:00:00Z", domain.ErrNotFound, http.StatusNotFound},
+		{"invalid time", "yesterday", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			if tt.err != nil {
+				svc.On("GetItemAsOf", mock.Anything, testUUID, at, domain.QueryOptions{}).Return(nil, tt.err).Maybe()
+			} else {
+				svc.On("GetItemAsOf", mock.Anything, testUUID, at, domain.QueryOptions{}).Return(&domain.Item{ID: testUUID, Title: "Lamp", Version: 2}, nil).Maybe()
+			}
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: testUUID}}
+			c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/items/"+testUUID+"?as_of="+tt.asOf, nil)
+
+			handler.GetByID(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			// Past states carry no validators
+			assert.Empty(t, w.Header().Get("ETag"))
+			svc.AssertNotCalled(t, "GetItemByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestItemHandler_GetRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc := new(MockItemService)
+	svc.On("ListItemRevisions", mock.Anything, testUUID, domain.PageRequest{Size: 20}).Return(&domain.Page[*domain.ItemRevision]{
+		Items: []*domain.ItemRevision{{
+			ID: "r2", ItemID: testUUID, Revision: 2,
+			Item: &domain.Item{ID: testUUID, Title: "Lamp", ItemProperties: []*domain.ItemProperty{{ID: "p1", Name: "color", Value: "red"}}},
+		}},
+		Total: 1,
+	}, nil)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/items/"+testUUID+"/revisions", nil)
+
+	handler.GetRevisions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Data []struct {
+			Type       string `json:"type"`
+			Attributes struct {
+				Revision int64 `json:"revision"`
+				Item     struct {
+					Title          string `json:"title"`
+					ItemProperties []struct {
+						Value string `json:"value"`
+					} `json:"item_properties"`
+				} `json:"item"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	if assert.Len(t, doc.Data, 1) {
+		assert.Equal(t, "item_revisions", doc.Data[0].Type)
+		assert.Equal(t, int64(2), doc.Data[0].Attributes.Revision)
+		assert.Equal(t, "Lamp", doc.Data[0].Attributes.Item.Title)
+		if assert.Len(t, doc.Data[0].Attributes.Item.ItemProperties, 1) {
+			assert.Equal(t, "red", doc.Data[0].Attributes.Item.ItemProperties[0].Value)
+		}
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_GetRevisions_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	svc := new(MockItemService)
+	svc.On("ListItemRevisions", mock.Anything, testUUID, mock.Anything).Return(nil, domain.ErrNotFound)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: testUUID}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/items/"+testUUID+"/revisions", nil)
+
+	handler.GetRevisions(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestItemHandler_RestoreRevision(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name    string
+		id      string
+		rev     string
+		ifMatch string
+		version int64
+		err     error
+		status  int
+	}{
+		{"reverted", testUUID, "1", "", 0, nil, http.StatusOK},
+		{"at version", testUUID, "1", `"3"`, 3, nil, http.StatusOK},
+		{"stale version", testUUID, "1", `"2"`, 2, domain.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{"unknown revision", testUUID, "9", "", 0, domain.ErrNotFound, http.StatusNotFound},
+		{"invalid revision", testUUID, "0", "", 0, nil, http.StatusBadRequest},
+		{"invalid UUID", "not-a-uuid", "1", "", 0, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			if tt.err != nil {
+				svc.On("RevertItem", mock.Anything, tt.id, mock.Anything, tt.version).Return(nil, tt.err).Maybe()
+			} else {
+				svc.On("RevertItem", mock.Anything, tt.id, int64(1), tt.version).Return(&domain.Item{ID: tt.id, Title: "Lamp", Version: 4}, nil).Maybe()
+			}
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}, {Key: "rev", Value: tt.rev}}
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/items/"+tt.id+"/revisions/"+tt.rev+"/restore", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			handler.RestoreRevision(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
@@ -1,9 +1,18 @@
 package items
 
+import "github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+
 type JSONAPIItem struct {
 	Data JSONAPIItemData `json:"data"`
 }
 
+// JSONAPINewItem is an item to create, with the properties its item_properties
+// relationship links to in included
+type JSONAPINewItem struct {
+	Data     JSONAPIItemData           `json:"data"`
+	Included []JSONAPIItemPropertyData `json:"included,omitempty"`
+}
+
 type JSONAPIItemData struct {
 	Type          string                    `json:"type" example:"items"`
 	ID            string                    `json:"id,omitempty" example:"item_1"`
@@ -14,6 +23,8 @@ type JSONAPIItemData struct {
 type JSONAPIItemAttributes struct {
 	Title       string `json:"title" example:"Item Title"`
 	Description string `json:"description" example:"Item Description"`
+	// OwnerID is read-only: it is set to the authenticated caller on creation
+	OwnerID string `json:"owner_id,omitempty" example:"user-123"`
 }
 
 type JSONAPIItemRelationships struct {
@@ -22,6 +33,32 @@ type JSONAPIItemRelationships struct {
 
 type JSONAPIItemPropertiesRel struct {
 	Data []JSONAPIItemPropertyDataIdentifier `json:"data"`
+	// Links are set in responses only
+	Links *JSONAPIRelationshipLinks `json:"links,omitempty"`
+}
+
+// JSONAPIRelationshipLinks links to a relationship of an item (self) and to its related resources (related)
+type JSONAPIRelationshipLinks struct {
+	Self    string `json:"self" example:"/api/v1/items/550e8400-e29b-41d4-a716-446655440000/relationships/item_properties"`
+	Related string `json:"related" example:"/api/v1/items/550e8400-e29b-41d4-a716-446655440000/item_properties"`
+}
+
+// JSONAPIItemPropertiesLinkage identifies the item properties to attach to, replace in or detach from an item
+type JSONAPIItemPropertiesLinkage struct {
+	Data []JSONAPIItemPropertyDataIdentifier `json:"data"`
+}
+
+// JSONAPIItemPropertiesRelationshipResponse is a page of the item_properties relationship of an item
+type JSONAPIItemPropertiesRelationshipResponse struct {
+	Data  []JSONAPIItemPropertyDataIdentifier `json:"data"`
+	Links JSONAPIRelationshipPageLinks        `json:"links"`
+	Meta  JSONAPIPaginationMeta               `json:"meta"`
+}
+
+// JSONAPIRelationshipPageLinks links to the pages of a relationship and to its related resources
+type JSONAPIRelationshipPageLinks struct {
+	JSONAPIPaginationLinks
+	Related string `json:"related" example:"/api/v1/items/550e8400-e29b-41d4-a716-446655440000/item_properties"`
 }
 
 type JSONAPIItemPropertyDataIdentifier struct {
@@ -29,13 +66,165 @@ type JSONAPIItemPropertyDataIdentifier struct {
 	ID   string `json:"id" example:"prop_1"`
 }
 
+// JSONAPIBulkItems is the document of a bulk request: the resources to write
+type JSONAPIBulkItems struct {
+	Data []JSONAPIBulkItemData `json:"data"`
+}
+
+// JSONAPIBulkItemData is an item of a bulk request or response, with its version in meta.version
+type JSONAPIBulkItemData struct {
+	JSONAPIItemData
+	Meta *JSONAPIBulkItemMeta `json:"meta,omitempty"`
+}
+
+type JSONAPIBulkItemMeta struct {
+	// Version is the version an update or deletion applies to, or the version of the stored item
+	Version int64 `json:"version" example:"3"`
+}
+
+// JSONAPIBulkResponse holds the result of each resource of a bulk request, in the order of the request
+type JSONAPIBulkResponse struct {
+	Meta JSONAPIBulkMeta `json:"meta"`
+}
+
+type JSONAPIBulkMeta struct {
+	Results []JSONAPIBulkResult `json:"results"`
+}
+
+// JSONAPIBulkResult is the outcome of one resource: the stored item, or the errors that kept it from being written
+type JSONAPIBulkResult struct {
+	Status string               `json:"status" example:"201"`
+	Data   *JSONAPIBulkItemData `json:"data,omitempty"`
+	Errors []apierror.Error     `json:"errors,omitempty"`
+}
+
+// JSONAPIOperations is the document of an atomic request: the operations to apply, in order
+type JSONAPIOperations struct {
+	Operations []JSONAPIOperation `json:"atomic:operations"`
+}
+
+// JSONAPIOperation adds the resource of data, updates the attributes data has, or removes the resource of ref
+type JSONAPIOperation struct {
+	Op   string                `json:"op" example:"add" enums:"add,update,remove"`
+	Ref  *JSONAPIOperationRef  `json:"ref,omitempty"`
+	Data *JSONAPIOperationData `json:"data,omitempty"`
+}
+
+// JSONAPIOperationRef identifies a stored resource by id, or one added by an earlier operation by lid
+type JSONAPIOperationRef struct {
+	Type string               `json:"type" example:"items" enums:"items,item_properties"`
+	ID   string               `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	LID  string               `json:"lid,omitempty" example:"lamp"`
+	Meta *JSONAPIBulkItemMeta `json:"meta,omitempty"`
+}
+
+// JSONAPIOperationData is an item or item property, whose item relationship links to its item
+type JSONAPIOperationData struct {
+	JSONAPIOperationRef
+	Attributes    map[string]string                  `json:"attributes,omitempty"`
+	Relationships *JSONAPIOperationDataRelationships `json:"relationships,omitempty"`
+}
+
+type JSONAPIOperationDataRelationships struct {
+	Item *JSONAPIOperationItemRel `json:"item,omitempty"`
+}
+
+type JSONAPIOperationItemRel struct {
+	Data JSONAPIOperationRef `json:"data"`
+}
+
+// JSONAPIOperationsResponse holds the result of each operation of an atomic request, in the order of the request
+type JSONAPIOperationsResponse struct {
+	Results []JSONAPIOperationResult `json:"atomic:results"`
+}
+
+// JSONAPIOperationResult is the resource an operation added or updated, with its version in meta.version, and empty for removals
+type JSONAPIOperationResult struct {
+	Data *JSONAPIOperationData `json:"data,omitempty"`
+}
+
 type JSONAPIItemResponse struct {
 	Data     JSONAPIItemData       `json:"data"`
 	Included []JSONAPIItemProperty `json:"included,omitempty"`
 }
 
 type JSONAPIItemListResponse struct {
-	Data []JSONAPIItemData `json:"data"`
+	Data  []JSONAPIItemData      `json:"data"`
+	Links JSONAPIPaginationLinks `json:"links"`
+	Meta  JSONAPIPaginationMeta  `json:"meta"`
+}
+
+// JSONAPIItemSearchData is an item found by a search, with its relevance in meta.score
+type JSONAPIItemSearchData struct {
+	JSONAPIItemData
+	Meta JSONAPIItemSearchMeta `json:"meta"`
+}
+
+type JSONAPIItemSearchMeta struct {
+	// Score is higher for better matches, and only comparable within one search
+	Score float64 `json:"score" example:"1.83"`
+}
+
+type JSONAPIItemSearchResponse struct {
+	Data     []JSONAPIItemSearchData `json:"data"`
+	Included []JSONAPIItemProperty   `json:"included,omitempty"`
+	Links    JSONAPIPaginationLinks  `json:"links"`
+	Meta     JSONAPIPaginationMeta   `json:"meta"`
+}
+
+// JSONAPIDeletedItemData is a soft-deleted item, with the time it was deleted in meta.deleted_at
+type JSONAPIDeletedItemData struct {
+	JSONAPIItemData
+	Meta JSONAPIDeletedItemMeta `json:"meta"`
+}
+
+type JSONAPIDeletedItemMeta struct {
+	DeletedAt string `json:"deleted_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type JSONAPIDeletedItemListResponse struct {
+	Data  []JSONAPIDeletedItemData `json:"data"`
+	Links JSONAPIPaginationLinks   `json:"links"`
+	Meta  JSONAPIPaginationMeta    `json:"meta"`
+}
+
+type JSONAPIItemRevisionData struct {
+	Type       string                        `json:"type" example:"item_revisions"`
+	ID         string                        `json:"id" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Attributes JSONAPIItemRevisionAttributes `json:"attributes"`
+}
+
+type JSONAPIItemRevisionAttributes struct {
+	ItemID   string `json:"item_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Revision int64  `json:"revision" example:"3"`
+	// Item is the snapshot of the item, with its properties; deleted_at is set in the revision of its deletion
+	Item      JSONAPIItemSnapshot `json:"item"`
+	CreatedAt string              `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type JSONAPIItemSnapshot struct {
+	ID             string                        `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title          string                        `json:"title" example:"Item Title"`
+	Description    string                        `json:"description" example:"Item Description"`
+	OwnerID        string                        `json:"owner_id" example:"user-123"`
+	Version        int64                         `json:"version" example:"3"`
+	CreatedAt      string                        `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt      string                        `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	DeletedAt      *string                       `json:"deleted_at"`
+	ItemProperties []JSONAPIItemPropertySnapshot `json:"item_properties,omitempty"`
+}
+
+type JSONAPIItemPropertySnapshot struct {
+	ID     string `json:"id" example:"prop_1"`
+	ItemID string `json:"item_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name   string `json:"name" example:"Property Name"`
+	Value  string `json:"value" example:"Property Value"`
+}
+
+type JSONAPIItemRevisionListResponse struct {
+	Data  []JSONAPIItemRevisionData `json:"data"`
+	Links JSONAPIPaginationLinks    `json:"links"`
+	Meta  JSONAPIPaginationMeta     `json:"meta"`
 }
 
 type JSONAPIItemProperty struct {
@@ -59,5 +248,45 @@ type JSONAPIItemPropertyResponse struct {
 }
 
 type JSONAPIItemPropertyListResponse struct {
-	Data []JSONAPIItemPropertyData `json:"data"`
+	Data  []JSONAPIItemPropertyData `json:"data"`
+	Links JSONAPIPaginationLinks    `json:"links"`
+	Meta  JSONAPIPaginationMeta     `json:"meta"`
+}
+
+// JSONAPIPaginationLinks links to the pages of a list. Links to pages that do
+// not exist are null; prev and last are always null in cursor pagination.
+type JSONAPIPaginationLinks struct {
+	Self  string  `json:"self" example:"/api/v1/items?page%5Bnumber%5D=2&page%5Bsize%5D=20"`
+	First string  `json:"first" example:"/api/v1/items?page%5Bnumber%5D=1&page%5Bsize%5D=20"`
+	Prev  *string `json:"prev" example:"/api/v1/items?page%5Bnumber%5D=1&page%5Bsize%5D=20"`
+	Next  *string `json:"next" example:"/api/v1/items?page%5Bnumber%5D=3&page%5Bsize%5D=20"`
+	Last  *string `json:"last" example:"/api/v1/items?page%5Bnumber%5D=5&page%5Bsize%5D=20"`
+}
+
+type JSONAPIPaginationMeta struct {
+	Total int64 `json:"total" example:"97"`
+}
+
+type JSONAPIAuditEventData struct {
+	Type       string                      `json:"type" example:"audit_events"`
+	ID         string                      `json:"id" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Attributes JSONAPIAuditEventAttributes `json:"attributes"`
+}
+
+type JSONAPIAuditEventAttributes struct {
+	Actor        string         `json:"actor" example:"user-123"`
+	Action       string         `json:"action" example:"patch"`
+	ResourceType string         `json:"resource_type" example:"items"`
+	ResourceID   string         `json:"resource_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Before       map[string]any `json:"before"`
+	After        map[string]any `json:"after"`
+	RequestID    string         `json:"request_id" example:"5d0c4b1e-5b0a-4a8e-9d8f-2f3c7a1b6e90"`
+	IP           string         `json:"ip" example:"203.0.113.7"`
+	CreatedAt    string         `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type JSONAPIAuditEventListResponse struct {
+	Data  []JSONAPIAuditEventData `json:"data"`
+	Links JSONAPIPaginationLinks  `json:"links"`
+	Meta  JSONAPIPaginationMeta   `json:"meta"`
 }
@@ -0,0 +1,129 @@
+package items
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+)
+
+// GetDeleted gets a page of the soft-deleted items
+// @Summary      List deleted items
+// @Description  get a page of the soft-deleted items (admin only), most recently deleted first; each carries the time it was deleted in meta.deleted_at
+// @Tags         trash
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        page[number]  query     int     false  "Page number, starting at 1"
+// @Param        page[size]    query     int     false  "Page size, up to the configured maximum"
+// @Param        page[cursor]  query     string  false  "Cursor from the next link of a previous page"
+// @Success      200  {object}  JSONAPIDeletedItemListResponse "Deleted items"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/admin/trash/items [get]
+func (h *ItemHandler) GetDeleted(c *gin.Context) {
+	page, err := parsePageRequest(c, h.DefaultPageSize, h.MaxPageSize)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	items, err := h.Service.ListDeletedItems(c.Request.Context(), page)
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+
+	many, err := marshalPage(c, page, items, domain.QueryOptions{})
+	if err != nil {
+		apierror.Respond(c, err)
+		return
+	}
+	for i, node := range many.Data {
+		node.Meta = &jsonapi.Meta{"deleted_at": items.Items[i].DeletedAt.Time.UTC().Format(time.RFC3339)}
+	}
+	if err := writePayload(c, many); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Restore restores a soft-deleted item
+// @Summary      Restore a deleted item
+// @Description  undelete a soft-deleted item along with the properties deleted with it (admin only); properties deleted before the item stay deleted
+// @Tags         trash
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Item ID (UUID format)"
+// @Success      200  {object}  JSONAPIItemResponse "Restored item"
+// @Header       200  {string}  ETag "Version of the item"
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/admin/trash/items/{id}/restore [post]
+func (h *ItemHandler) Restore(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	item, err := h.Service.RestoreItem(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Deleted item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+
+	setETag(c, item.Version)
+	if err := writeResource(c, item, domain.QueryOptions{}); err != nil {
+		apierror.Respond(c, err)
+	}
+}
+
+// Purge permanently deletes a soft-deleted item
+// @Summary      Purge a deleted item
+// @Description  permanently delete a soft-deleted item and all of its properties (admin only); items that are not deleted cannot be purged
+// @Tags         trash
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      string  true  "Item ID (UUID format)"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  apierror.Document
+// @Failure      401  {object}  apierror.Document
+// @Failure      403  {object}  apierror.Document
+// @Failure      404  {object}  apierror.Document
+// @Failure      500  {object}  apierror.Document
+// @Router       /v1/admin/trash/items/{id} [delete]
+func (h *ItemHandler) Purge(c *gin.Context) {
+	id := c.Param("id")
+
+	// Validate UUID format
+	if !isValidUUID(id) {
+		apierror.Write(c, apierror.BadRequest("Invalid UUID format"))
+		return
+	}
+
+	if err := h.Service.PurgeItem(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			apierror.Write(c, apierror.NotFound("Deleted item not found"))
+			return
+		}
+		apierror.Respond(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+package items
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestItemHandler_GetDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := new(MockItemService)
+	svc.On("ListDeletedItems", mock.Anything, domain.PageRequest{Size: 20}).Return(&domain.Page[*domain.Item]{
+		Items: []*domain.Item{{ID: "1", Title: "Lamp", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}},
+		Total: 1,
+	}, nil)
+	handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/admin/trash/items", nil)
+
+	handler.GetDeleted(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc struct {
+		Data []struct {
+			ID   string         `json:"id"`
+			Meta map[string]any `json:"meta"`
+		} `json:"data"`
+		Meta map[string]any `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	if assert.Len(t, doc.Data, 1) {
+		assert.Equal(t, "1", doc.Data[0].ID)
+		assert.Equal(t, "2026-03-01T12:00:00Z", doc.Data[0].Meta["deleted_at"])
+	}
+	assert.Equal(t, float64(1), doc.Meta["total"])
+	svc.AssertExpectations(t)
+}
+
+func TestItemHandler_Restore(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		status int
+	}{
+		{"restored", testUUID, nil, http.StatusOK},
+		{"not deleted", testUUID, domain.ErrNotFound, http.StatusNotFound},
+		{"invalid UUID", "not-a-uuid", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			if tt.err != nil {
+				svc.On("RestoreItem", mock.Anything, tt.id).Return(nil, tt.err).Maybe()
+			} else {
+				svc.On("RestoreItem", mock.Anything, tt.id).Return(&domain.Item{ID: tt.id, Title: "Lamp", Version: 3}, nil).Maybe()
+			}
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request, _ = http.NewRequest(http.MethodPost, "/api/v1/admin/trash/items/"+tt.id+"/restore", nil)
+
+			handler.Restore(c)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+				assert.Contains(t, w.Body.String(), `"title":"Lamp"`)
+			} else {
+				decodeErrors(t, w)
+			}
+		})
+	}
+}
+
+func TestItemHandler_Purge(t *testing.T) {
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"purged", nil, http.StatusNoContent},
+		{"not deleted", domain.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			svc := new(MockItemService)
+			svc.On("PurgeItem", mock.Anything, testUUID).Return(tt.err)
+			handler := NewItemHandler(svc, newTestValidator(), newTestLogger(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: testUUID}}
+			c.Request, _ = http.NewRequest(http.MethodDelete, "/api/v1/admin/trash/items/"+testUUID, nil)
+
+			handler.Purge(c)
+			c.Writer.WriteHeaderNow()
+
+			assert.Equal(t, tt.status, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
@@ -1,21 +1,60 @@
 package middleware
 
 import (
-	"net/http"
+	"strings"
 
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
-const StaticToken = "secret-token"
+// APIKeyHeader is the header carrying an API key as an alternative to the Authorization header.
+const APIKeyHeader = "X-API-Key"
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware authenticates requests carrying either a signed JWT or an API key.
+// JWTs are sent as "Authorization: Bearer <jwt>"; API keys as "X-API-Key: <key>"
+// or "Authorization: Bearer ak_...". On success the principal is stored in the
+// request context (see domain.PrincipalFromContext) for handlers and services.
+func AuthMiddleware(verifier domain.TokenVerifier, apiKeys domain.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("Authorization")
-		if token != "Bearer "+StaticToken {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+		ctx := c.Request.Context()
+
+		var (
+			principal *domain.Principal
+			err       error
+		)
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			principal, err = apiKeys.Authenticate(ctx, key)
+		} else if token, ok := bearerToken(c.GetHeader("Authorization")); !ok {
+			unauthorized(c)
 			return
+		} else if strings.HasPrefix(token, domain.APIKeyTokenPrefix) {
+			principal, err = apiKeys.Authenticate(ctx, token)
+		} else {
+			principal, err = verifier.Verify(ctx, token)
 		}
+
+		if err != nil {
+			unauthorized(c)
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithPrincipal(ctx, principal))
 		c.Next()
 	}
 }
+
+// bearerToken extracts the token from an Authorization header value.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	apierror.Write(c, apierror.Unauthorized())
+}
@@ -1,25 +1,65 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
+// stubVerifier accepts a single known token
+type stubVerifier struct {
+	validToken string
+}
+
+func (s *stubVerifier) Verify(ctx context.Context, token string) (*domain.Principal, error) {
+	if token != s.validToken {
+		return nil, errors.New("invalid token")
+	}
+	return &domain.Principal{Subject: "user-123", Scopes: []string{"items:read"}}, nil
+}
+
+// stubAPIKeyService authenticates a single known API key
+type stubAPIKeyService struct {
+	domain.APIKeyService
+	validKey string
+}
+
+func (s *stubAPIKeyService) Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	if plaintext != s.validKey {
+		return nil, domain.ErrUnauthenticated
+	}
+	return &domain.Principal{Subject: "billing-service", AuthMethod: domain.AuthMethodAPIKey}, nil
+}
+
+const testAPIKey = "ak_0123abcd_secret"
+
+func newTestAuthMiddleware() gin.HandlerFunc {
+	return AuthMiddleware(&stubVerifier{validToken: "valid-token"}, &stubAPIKeyService{validKey: testAPIKey})
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	tests := []struct {
 		name           string
 		authHeader     string
+		apiKeyHeader   string
 		expectedStatus int
 	}{
 		{
 			name:           "Valid Token",
-			authHeader:     "Bearer secret-token",
+			authHeader:     "Bearer valid-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Case Insensitive Scheme",
+			authHeader:     "bearer valid-token",
 			expectedStatus: http.StatusOK,
 		},
 		{
@@ -34,7 +74,38 @@ func TestAuthMiddleware(t *testing.T) {
 		},
 		{
 			name:           "Wrong Format",
-			authHeader:     "secret-token",
+			authHeader:     "valid-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Wrong Scheme",
+			authHeader:     "Basic valid-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Valid API Key Header",
+			apiKeyHeader:   testAPIKey,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid API Key Header",
+			apiKeyHeader:   "ak_0123abcd_wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Valid API Key As Bearer",
+			authHeader:     "Bearer " + testAPIKey,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid API Key As Bearer",
+			authHeader:     "Bearer ak_0123abcd_wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid API Key Header Ignores Valid Token",
+			authHeader:     "Bearer valid-token",
+			apiKeyHeader:   "ak_0123abcd_wrong",
 			expectedStatus: http.StatusUnauthorized,
 		},
 	}
@@ -44,7 +115,7 @@ func TestAuthMiddleware(t *testing.T) {
 			w := httptest.NewRecorder()
 			_, r := gin.CreateTestContext(w)
 
-			r.Use(AuthMiddleware())
+			r.Use(newTestAuthMiddleware())
 			r.GET("/test", func(c *gin.Context) {
 				c.Status(http.StatusOK)
 			})
@@ -53,9 +124,62 @@ func TestAuthMiddleware(t *testing.T) {
 			if tt.authHeader != "" {
 				req.Header.Set("Authorization", tt.authHeader)
 			}
+			if tt.apiKeyHeader != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKeyHeader)
+			}
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
 		})
 	}
 }
+
+func TestAuthMiddleware_StoresPrincipalInContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	var principal *domain.Principal
+	r.Use(newTestAuthMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		principal, _ = domain.PrincipalFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, principal) {
+		assert.Equal(t, "user-123", principal.Subject)
+	}
+}
+
+func TestAuthMiddleware_APIKeyPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	var principal *domain.Principal
+	r.Use(newTestAuthMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		principal, _ = domain.PrincipalFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(APIKeyHeader, testAPIKey)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, principal) {
+		assert.Equal(t, "billing-service", principal.Subject)
+		assert.Equal(t, domain.AuthMethodAPIKey, principal.AuthMethod)
+	}
+}
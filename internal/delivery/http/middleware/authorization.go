@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScopes rejects requests whose principal lacks any of the given scopes.
+// Principals with the admin role hold every scope. It must be registered after AuthMiddleware.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if missing := missingScopes(principal, scopes); len(missing) > 0 {
+			insufficientScope(c, missing)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireIncludeScopes checks the scopes needed to read the related resources named
+// in the "include" query parameter, e.g. {"item_properties": "item_properties:read"}.
+// Includes without an entry are left to the handler.
+func RequireIncludeScopes(scopesByInclude map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		include := c.Query("include")
+		if include == "" {
+			c.Next()
+			return
+		}
+
+		var required []string
+		for _, name := range strings.Split(include, ",") {
+			if scope, ok := scopesByInclude[strings.TrimSpace(name)]; ok {
+				required = append(required, scope)
+			}
+		}
+		if len(required) == 0 {
+			c.Next()
+			return
+		}
+
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if missing := missingScopes(principal, required); len(missing) > 0 {
+			insufficientScope(c, missing)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRelationshipScopes checks the scopes needed to write the related resources named
+// in the relationships of the request document, e.g. {"item_properties": "item_properties:write"}
+// for resources created along with the primary data. Relationships without an entry, and
+// documents that cannot be read, are left to the handler.
+func RequireRelationshipScopes(scopesByRelationship map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		// The handler reads the document again
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		var doc struct {
+			Data struct {
+				Relationships map[string]json.RawMessage `json:"relationships"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			c.Next()
+			return
+		}
+		var required []string
+		for name := range doc.Data.Relationships {
+			if scope, ok := scopesByRelationship[name]; ok {
+				required = append(required, scope)
+			}
+		}
+		if len(required) == 0 {
+			c.Next()
+			return
+		}
+		sort.Strings(required)
+
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if missing := missingScopes(principal, required); len(missing) > 0 {
+			insufficientScope(c, missing)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireOperationScopes checks the scopes needed to apply the operations of an atomic
+// request to the types of resources they target, in their data or ref, e.g.
+// {"items": "items:write"}. Types without an entry, and documents or operations that
+// cannot be read, are left to the handler.
+func RequireOperationScopes(scopesByType map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		// The handler reads the document again
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		var doc struct {
+			Operations []struct {
+				Ref  json.RawMessage `json:"ref"`
+				Data json.RawMessage `json:"data"`
+			} `json:"atomic:operations"`
+		}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			c.Next()
+			return
+		}
+		needed := map[string]bool{}
+		for _, operation := range doc.Operations {
+			for _, target := range []json.RawMessage{operation.Ref, operation.Data} {
+				var resource struct {
+					Type string `json:"type"`
+				}
+				if json.Unmarshal(target, &resource) != nil {
+					continue
+				}
+				if scope, ok := scopesByType[resource.Type]; ok {
+					needed[scope] = true
+				}
+			}
+		}
+		if len(needed) == 0 {
+			c.Next()
+			return
+		}
+		required := make([]string, 0, len(needed))
+		for scope := range needed {
+			required = append(required, scope)
+		}
+		sort.Strings(required)
+
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if missing := missingScopes(principal, required); len(missing) > 0 {
+			insufficientScope(c, missing)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated requests whose principal lacks the given role.
+// It must be registered after AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if !principal.HasRole(role) {
+			forbidden(c, "insufficient_role", fmt.Sprintf("The %q role is required", role))
+			return
+		}
+		c.Next()
+	}
+}
+
+func missingScopes(principal *domain.Principal, scopes []string) []string {
+	var missing []string
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// insufficientScope responds as described by RFC 6750 section 3.1, with a JSON:API error body.
+func insufficientScope(c *gin.Context, missing []string) {
+	scope := strings.Join(missing, " ")
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer realm="api", error="insufficient_scope", scope=%q`, scope))
+	forbidden(c, "insufficient_scope", fmt.Sprintf("Missing required scope: %s", scope))
+}
+
+func forbidden(c *gin.Context, code string, detail string) {
+	apierror.Write(c, apierror.New(http.StatusForbidden, code, detail))
+}
@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveWithPrincipal runs the middleware behind a stub that stores the given principal, if any
+func serveWithPrincipal(principal *domain.Principal, middleware gin.HandlerFunc, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	r.Use(func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	})
+	r.Use(middleware)
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{
+			name:           "Has All Scopes",
+			principal:      &domain.Principal{Subject: "user-123", Scopes: []string{"items:read", "items:write"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing One Scope",
+			principal:      &domain.Principal{Subject: "user-123", Scopes: []string{"items:read"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "No Scopes",
+			principal:      &domain.Principal{Subject: "user-123"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin Holds Every Scope",
+			principal:      &domain.Principal{Subject: "admin-1", Roles: []string{domain.RoleAdmin}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithPrincipal(tt.principal, RequireScopes("items:read", "items:write"), "/test")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireScopes_ForbiddenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	principal := &domain.Principal{Subject: "user-123", Scopes: []string{"items:read"}}
+	w := serveWithPrincipal(principal, RequireScopes("items:read", "items:write"), "/test")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `Bearer realm="api", error="insufficient_scope", scope="items:write"`, w.Header().Get("WWW-Authenticate"))
+
+	var body struct {
+		Errors []*jsonapi.ErrorObject `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	if assert.Len(t, body.Errors, 1) {
+		assert.Equal(t, "403", body.Errors[0].Status)
+		assert.Equal(t, "insufficient_scope", body.Errors[0].Code)
+		assert.Contains(t, body.Errors[0].Detail, "items:write")
+	}
+}
+
+func TestRequireIncludeScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reader := &domain.Principal{Subject: "user-123", Scopes: []string{"items:read"}}
+	fullReader := &domain.Principal{Subject: "user-123", Scopes: []string{"items:read", "item_properties:read"}}
+	middleware := RequireIncludeScopes(map[string]string{"item_properties": "item_properties:read"})
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		target         string
+		expectedStatus int
+	}{
+		{name: "No Include", principal: reader, target: "/test", expectedStatus: http.StatusOK},
+		{name: "Unguarded Include", principal: reader, target: "/test?include=owner", expectedStatus: http.StatusOK},
+		{name: "Guarded Include Without Scope", principal: reader, target: "/test?include=item_properties", expectedStatus: http.StatusForbidden},
+		{name: "Guarded Include In List", principal: reader, target: "/test?include=owner,item_properties", expectedStatus: http.StatusForbidden},
+		{name: "Guarded Include With Scope", principal: fullReader, target: "/test?include=item_properties", expectedStatus: http.StatusOK},
+		{name: "Unauthenticated", target: "/test?include=item_properties", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithPrincipal(tt.principal, middleware, tt.target)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireRelationshipScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	writer := &domain.Principal{Subject: "user-123", Scopes: []string{"items:write"}}
+	fullWriter := &domain.Principal{Subject: "user-123", Scopes: []string{"items:write", "item_properties:write"}}
+	withProperties := `{"data": {"type": "items", "attributes": {"title": "Lamp"},
+		"relationships": {"item_properties": {"data": [{"type": "item_properties", "id": "1"}]}}}}`
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		body           string
+		expectedStatus int
+	}{
+		{name: "No Relationships", principal: writer, body: `{"data": {"type": "items", "attributes": {"title": "Lamp"}}}`, expectedStatus: http.StatusOK},
+		{name: "Unguarded Relationship", principal: writer, body: `{"data": {"type": "items", "relationships": {"owner": {"data": null}}}}`, expectedStatus: http.StatusOK},
+		{name: "Guarded Relationship Without Scope", principal: writer, body: withProperties, expectedStatus: http.StatusForbidden},
+		{name: "Guarded Relationship With Scope", principal: fullWriter, body: withProperties, expectedStatus: http.StatusOK},
+		{name: "Unreadable Document", principal: writer, body: `{"data":`, expectedStatus: http.StatusOK},
+		{name: "Unauthenticated", body: withProperties, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.Use(func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), tt.principal))
+				}
+				c.Next()
+			})
+			r.Use(RequireRelationshipScopes(map[string]string{"item_properties": "item_properties:write"}))
+			var received string
+			r.POST("/test", func(c *gin.Context) {
+				body, _ := io.ReadAll(c.Request.Body)
+				received = string(body)
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code == http.StatusOK {
+				// The handler still reads the whole document
+				assert.Equal(t, tt.body, received)
+			}
+		})
+	}
+}
+
+func TestRequireOperationScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	writer := &domain.Principal{Subject: "user-123", Scopes: []string{"items:write"}}
+	fullWriter := &domain.Principal{Subject: "user-123", Scopes: []string{"items:write", "item_properties:write"}}
+	addItem := `{"op": "add", "data": {"type": "items", "lid": "lamp", "attributes": {"title": "Lamp"}}}`
+	removeProperty := `{"op": "remove", "ref": {"type": "item_properties", "id": "1"}}`
+	withProperties := `{"atomic:operations": [` + addItem + `, ` + removeProperty + `]}`
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		body           string
+		expectedStatus int
+	}{
+		{name: "Items Only", principal: writer, body: `{"atomic:operations": [` + addItem + `]}`, expectedStatus: http.StatusOK},
+		{name: "Items Only Without Scope", principal: &domain.Principal{Subject: "user-123"}, body: `{"atomic:operations": [` + addItem + `]}`, expectedStatus: http.StatusForbidden},
+		{name: "Properties Without Scope", principal: writer, body: withProperties, expectedStatus: http.StatusForbidden},
+		{name: "Properties With Scope", principal: fullWriter, body: withProperties, expectedStatus: http.StatusOK},
+		{name: "Unguarded Type", principal: writer, body: `{"atomic:operations": [{"op": "add", "data": {"type": "widgets"}}]}`, expectedStatus: http.StatusOK},
+		{name: "Unreadable Document", principal: writer, body: `{"atomic:operations":`, expectedStatus: http.StatusOK},
+		{name: "Unauthenticated", body: withProperties, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.Use(func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), tt.principal))
+				}
+				c.Next()
+			})
+			r.Use(RequireOperationScopes(map[string]string{"items": "items:write", "item_properties": "item_properties:write"}))
+			var received string
+			r.POST("/test", func(c *gin.Context) {
+				body, _ := io.ReadAll(c.Request.Body)
+				received = string(body)
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code == http.StatusOK {
+				// The handler still reads the whole document
+				assert.Equal(t, tt.body, received)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		expectedStatus int
+	}{
+		{
+			name:           "Has Role",
+			principal:      &domain.Principal{Subject: "user-123", Roles: []string{"admin"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing Role",
+			principal:      &domain.Principal{Subject: "user-123", Roles: []string{"viewer"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Unauthenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithPrincipal(tt.principal, RequireRole("admin"), "/test")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
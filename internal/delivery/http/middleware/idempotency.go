@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader carries the key by which a client identifies a POST request it may retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed for a retried request.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyConfig controls how IdempotencyMiddleware keeps the responses to requests.
+type IdempotencyConfig struct {
+	// TTL is how long the response to a request is kept, to be replayed for its retries.
+	TTL time.Duration
+	// LockTTL bounds how long a request holds its key while it is processed; a request
+	// running longer lets a retry through.
+	LockTTL time.Duration
+}
+
+// idempotentResponse is a response kept for an idempotency key, with the fingerprint of
+// the request it answered (see requestFingerprint).
+type idempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// IdempotencyMiddleware makes the POST requests sent with an Idempotency-Key safe to retry.
+// The first response to a key is kept in cache and replayed, status, headers and body, for
+// every retry: a request with the same key, method, URL and body from the same principal
+// of the same tenant. A request reusing the key for anything else gets 422, and one sent
+// while the request of its key is still processed gets 409, the key being locked in cache
+// meanwhile. Server errors are not kept, so that the request can be retried, nor are 401
+// and 403 responses, which would outlive the credentials and scopes granted since, nor
+// responses with Cache-Control: no-store, which carry secrets. Requests without the
+// header, and other methods, are served as usual. It must be registered after
+// AuthMiddleware and TenantMiddleware.
+func IdempotencyMiddleware(cache domain.CacheRepository, cfg IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if !validRequestID(key) {
+			apierror.Write(c, apierror.BadRequest(fmt.Sprintf("%s must be printable ASCII of up to %d characters", IdempotencyKeyHeader, maxRequestIDLength)).WithHeader(IdempotencyKeyHeader))
+			return
+		}
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		tenantID, _ := domain.TenantFromContext(c.Request.Context())
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Write(c, apierror.InvalidDocument(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request, body)
+
+		// The response is kept, and the key locked, even when the client gives up on the request
+		ctx := context.WithoutCancel(c.Request.Context())
+		cacheKey := idempotencyCacheKey(tenantID, principal.Subject, key)
+		lockKey := cacheKey + ":lock"
+		if replay(c, cache, cacheKey, fingerprint) {
+			return
+		}
+
+		token := uuid.NewString()
+		locked, err := cache.Lock(ctx, lockKey, token, cfg.LockTTL)
+		if err != nil {
+			apierror.Respond(c, fmt.Errorf("%w: failed to lock idempotency key: %v", domain.ErrUnavailable, err))
+			return
+		}
+		if !locked {
+			// The request holding the key may have completed since the response was looked up
+			if replay(c, cache, cacheKey, fingerprint) {
+				return
+			}
+			apierror.Write(c, apierror.New(http.StatusConflict, "idempotency_key_in_use", "A request with this Idempotency-Key is still being processed").WithHeader(IdempotencyKeyHeader))
+			return
+		}
+		defer func() {
+			if err := cache.Unlock(ctx, lockKey, token); err != nil {
+				log.Printf("Failed to unlock idempotency key: %v", err)
+			}
+		}()
+		// The request holding the key before may have stored its response and released the
+		// key since the response was looked up
+		if replay(c, cache, cacheKey, fingerprint) {
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if !keepResponse(recorder) {
+			return
+		}
+		header := recorder.Header().Clone()
+		// Replays carry the ID of their own request
+		header.Del(RequestIDHeader)
+		data, err := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      recorder.Status(),
+			Header:      header,
+			Body:        recorder.body.Bytes(),
+		})
+		if err == nil {
+			err = cache.Set(ctx, cacheKey, string(data), cfg.TTL)
+		}
+		if err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// replay answers the request with the response kept under cacheKey, or with 422 when that
+// response answered another request, and reports whether there was a response to replay.
+func replay(c *gin.Context, cache domain.CacheRepository, cacheKey string, fingerprint string) bool {
+	stored, err := cache.Get(c.Request.Context(), cacheKey)
+	if err != nil || stored == "" {
+		return false
+	}
+	var response idempotentResponse
+	if err := json.Unmarshal([]byte(stored), &response); err != nil {
+		log.Printf("Failed to read idempotent response: %v", err)
+		return false
+	}
+	if response.Fingerprint != fingerprint {
+		apierror.Write(c, apierror.New(http.StatusUnprocessableEntity, "idempotency_key_reused", "The Idempotency-Key was already used for a different request").WithHeader(IdempotencyKeyHeader))
+		return true
+	}
+
+	for name, values := range response.Header {
+		c.Writer.Header()[name] = values
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.AbortWithStatus(response.Status)
+	_, _ = c.Writer.Write(response.Body)
+	return true
+}
+
+// keepResponse reports whether the response may be kept to be replayed (see
+// IdempotencyMiddleware).
+func keepResponse(w gin.ResponseWriter) bool {
+	switch status := w.Status(); {
+	case status >= http.StatusInternalServerError, status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return false
+	}
+	for _, directive := range strings.Split(w.Header().Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint identifies a request by its method, URL and body, which its retries share.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyCacheKey returns the cache key of the response to an idempotency key of a
+// principal of a tenant. The parts are hashed together so that none can run into another.
+func idempotencyCacheKey(tenantID string, subject string, key string) string {
+	sum := sha256.Sum256([]byte(tenantID + "\x00" + subject + "\x00" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// responseRecorder copies the body of a response as it is written.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/repository/file"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIdempotencyConfig = IdempotencyConfig{TTL: time.Hour, LockTTL: time.Minute}
+
+// newIdempotencyTestRouter returns a router serving POST and GET /test through
+// IdempotencyMiddleware for the given principal of the acme tenant. The handler answers
+// with status, counting its calls in calls.
+func newIdempotencyTestRouter(cache domain.CacheRepository, principal *domain.Principal, status int, calls *int) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := domain.ContextWithPrincipal(c.Request.Context(), principal)
+		c.Request = c.Request.WithContext(domain.ContextWithTenant(ctx, "acme"))
+		c.Next()
+	})
+	r.Use(IdempotencyMiddleware(cache, testIdempotencyConfig))
+	handler := func(c *gin.Context) {
+		*calls++
+		c.Header("Location", "/test/1")
+		c.Header(RequestIDHeader, "request-1")
+		c.String(status, "call %d", *calls)
+	}
+	r.POST("/test", handler)
+	r.GET("/test", handler)
+	return r
+}
+
+func newIdempotencyTestCache(t *testing.T) domain.CacheRepository {
+	t.Helper()
+	cache, err := file.NewCacheRepository(t.TempDir())
+	require.NoError(t, err)
+	return cache
+}
+
+func serveIdempotentRequest(r *gin.Engine, method string, key string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/test", bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_Replay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls)
+
+	first := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+	retry := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "call 1", retry.Body.String())
+	assert.Equal(t, "/test/1", retry.Header().Get("Location"))
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	// The ID of the original request is not replayed
+	assert.Empty(t, retry.Header().Get(RequestIDHeader))
+
+	// Another key is another request
+	other := serveIdempotentRequest(r, http.MethodPost, "key-2", `{"title":"Lamp"}`)
+	assert.Equal(t, "call 2", other.Body.String())
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_KeyReused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls)
+
+	serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Desk"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_reused")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyMiddleware_KeyInUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newIdempotencyTestCache(t)
+	var calls int
+	r := newIdempotencyTestRouter(cache, &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls)
+
+	// Another request holds the key
+	lockKey := idempotencyCacheKey("acme", "user-123", "key-1") + ":lock"
+	locked, err := cache.Lock(t.Context(), lockKey, "other", time.Minute)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_in_use")
+	assert.Equal(t, 0, calls)
+}
+
+// lateCache misses the first lookup of a key, as if the response to the request holding
+// the key was stored right after it
+type lateCache struct {
+	domain.CacheRepository
+	looked map[string]bool
+}
+
+func (c *lateCache) Get(ctx context.Context, key string) (string, error) {
+	if !c.looked[key] {
+		c.looked[key] = true
+		return "", nil
+	}
+	return c.CacheRepository.Get(ctx, key)
+}
+
+func TestIdempotencyMiddleware_StoredWhileLocking(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newIdempotencyTestCache(t)
+	var calls int
+	serveIdempotentRequest(newIdempotencyTestRouter(cache, &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls), http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	// The retry misses the response, then gets the key released by the first request
+	r := newIdempotencyTestRouter(&lateCache{CacheRepository: cache, looked: map[string]bool{}}, &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls)
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "call 1", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_ServerErrorNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusInternalServerError, &calls)
+
+	serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_ForbiddenNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusForbidden, &calls)
+
+	serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{"title":"Lamp"}`)
+
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_NoStoreNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cacheDir := t.TempDir()
+	cache, err := file.NewCacheRepository(cacheDir)
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), &domain.Principal{Subject: "admin-1"}))
+		c.Next()
+	})
+	r.Use(IdempotencyMiddleware(cache, testIdempotencyConfig))
+	var calls int
+	r.POST("/test", func(c *gin.Context) {
+		calls++
+		c.Header("Cache-Control", "private, no-store")
+		c.String(http.StatusCreated, "secret-%d", calls)
+	})
+
+	serveIdempotentRequest(r, http.MethodPost, "key-1", `{}`)
+	w := serveIdempotentRequest(r, http.MethodPost, "key-1", `{}`)
+
+	assert.Equal(t, "secret-2", w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	// The secrets never reach the cache
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(cacheDir, entry.Name()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret-")
+	}
+}
+
+func TestIdempotencyMiddleware_KeysOfPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newIdempotencyTestCache(t)
+	var calls int
+	alice := newIdempotencyTestRouter(cache, &domain.Principal{Subject: "alice"}, http.StatusCreated, &calls)
+	bob := newIdempotencyTestRouter(cache, &domain.Principal{Subject: "bob"}, http.StatusCreated, &calls)
+
+	serveIdempotentRequest(alice, http.MethodPost, "key-1", `{}`)
+	w := serveIdempotentRequest(bob, http.MethodPost, "key-1", `{}`)
+
+	assert.Equal(t, "call 2", w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_PassThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusOK, &calls)
+
+	// Without a key, and for other methods, every request is served
+	serveIdempotentRequest(r, http.MethodPost, "", `{}`)
+	serveIdempotentRequest(r, http.MethodPost, "", `{}`)
+	serveIdempotentRequest(r, http.MethodGet, "key-1", "")
+	w := serveIdempotentRequest(r, http.MethodGet, "key-1", "")
+
+	assert.Equal(t, 4, calls)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyMiddleware_InvalidKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int
+	r := newIdempotencyTestRouter(newIdempotencyTestCache(t), &domain.Principal{Subject: "user-123"}, http.StatusCreated, &calls)
+
+	w := serveIdempotentRequest(r, http.MethodPost, "key\x01", `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID of a request, from the client or generated, and is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients, like the audit log column they are stored in.
+const maxRequestIDLength = 255
+
+// RequestInfoMiddleware stores the ID and client IP of a request in the request context
+// (see domain.RequestInfoFromContext), for the audit log. The ID is the one the client
+// sent in X-Request-ID, if it is printable ASCII and not too long, and a new UUID otherwise.
+func RequestInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		info := domain.RequestInfo{ID: requestID, IP: c.ClientIP()}
+		c.Request = c.Request.WithContext(domain.ContextWithRequestInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestInfoMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		requestID string
+		kept      bool
+	}{
+		{"client ID", "req-42", true},
+		{"no ID", "", false},
+		{"too long", strings.Repeat("a", 256), false},
+		{"not printable", "req 42", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			var info domain.RequestInfo
+			r.Use(RequestInfoMiddleware())
+			r.GET("/test", func(c *gin.Context) {
+				info, _ = domain.RequestInfoFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = "203.0.113.7:4711"
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, "203.0.113.7", info.IP)
+			assert.Equal(t, info.ID, w.Header().Get(RequestIDHeader))
+			if tt.kept {
+				assert.Equal(t, tt.requestID, info.ID)
+			} else {
+				assert.NoError(t, uuid.Validate(info.ID))
+			}
+		})
+	}
+}
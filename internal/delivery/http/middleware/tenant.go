@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantConfig controls how TenantMiddleware resolves the tenant of a request.
+type TenantConfig struct {
+	// Header names the request header selecting a tenant, e.g. "X-Tenant-ID".
+	Header string
+	// BaseDomain enables tenant subdomains: "acme.api.example.com" selects
+	// the "acme" tenant when BaseDomain is "api.example.com".
+	BaseDomain string
+	// DefaultTenant is the tenant of credentials not bound to one.
+	DefaultTenant string
+}
+
+// TenantMiddleware resolves the tenant of a request and stores it in the request
+// context (see domain.TenantFromContext). A request may name its tenant in the
+// configured header or subdomain; otherwise the tenant the credential is bound to
+// is used (the DefaultTenant for unbound credentials). A credential can only act
+// within its own tenant: naming any other tenant gets 404, so callers cannot probe
+// which tenants exist. It must be registered after AuthMiddleware.
+func TenantMiddleware(cfg TenantConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c)
+			return
+		}
+
+		tenantID := principal.TenantID
+		if tenantID == "" {
+			tenantID = cfg.DefaultTenant
+		}
+		if requested := requestedTenant(c, cfg); requested != "" && requested != tenantID {
+			tenantNotFound(c)
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// requestedTenant returns the tenant named by the header or, failing that, the subdomain of the request.
+func requestedTenant(c *gin.Context, cfg TenantConfig) string {
+	if cfg.Header != "" {
+		if tenantID := strings.TrimSpace(c.GetHeader(cfg.Header)); tenantID != "" {
+			return tenantID
+		}
+	}
+	if cfg.BaseDomain == "" {
+		return ""
+	}
+
+	host := c.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	subdomain, found := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(cfg.BaseDomain))
+	if !found || subdomain == "" || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
+
+func tenantNotFound(c *gin.Context) {
+	apierror.Write(c, apierror.New(http.StatusNotFound, "tenant_not_found", "The requested tenant does not exist"))
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTenantConfig = TenantConfig{Header: "X-Tenant-ID", BaseDomain: "api.example.com", DefaultTenant: "default"}
+
+// serveTenantRequest runs TenantMiddleware for req and returns the response and the resolved tenant
+func serveTenantRequest(principal *domain.Principal, req *http.Request) (*httptest.ResponseRecorder, string) {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	var resolved string
+	r.Use(func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	})
+	r.Use(TenantMiddleware(testTenantConfig))
+	r.GET("/test", func(c *gin.Context) {
+		resolved, _ = domain.TenantFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(w, req)
+	return w, resolved
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	acmeUser := &domain.Principal{Subject: "user-123", TenantID: "acme"}
+	unboundUser := &domain.Principal{Subject: "user-123"}
+
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		host           string
+		header         string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{name: "Bound Credential", principal: acmeUser, expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Unbound Credential Uses Default", principal: unboundUser, expectedStatus: http.StatusOK, expectedTenant: "default"},
+		{name: "Matching Header", principal: acmeUser, header: "acme", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Matching Subdomain", principal: acmeUser, host: "acme.api.example.com:8080", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Subdomain Is Case Insensitive", principal: acmeUser, host: "ACME.api.example.com", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Base Domain Names No Tenant", principal: acmeUser, host: "api.example.com", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Nested Subdomain Names No Tenant", principal: acmeUser, host: "a.acme.api.example.com", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Header Takes Precedence Over Subdomain", principal: acmeUser, header: "acme", host: "globex.api.example.com", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Other Tenant In Header", principal: acmeUser, header: "globex", expectedStatus: http.StatusNotFound},
+		{name: "Other Tenant In Subdomain", principal: acmeUser, host: "globex.api.example.com", expectedStatus: http.StatusNotFound},
+		{name: "Unbound Credential Naming A Tenant", principal: unboundUser, header: "acme", expectedStatus: http.StatusNotFound},
+		{name: "Unauthenticated", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+
+			w, tenant := serveTenantRequest(tt.principal, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedTenant, tenant)
+		})
+	}
+}
+
+func TestTenantMiddleware_NotFoundResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Tenant-ID", "globex")
+	w, _ := serveTenantRequest(&domain.Principal{Subject: "user-123", TenantID: "acme"}, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"tenant_not_found"`)
+	// The response does not reveal whether the other tenant exists
+	assert.NotContains(t, w.Body.String(), "globex")
+}
@@ -2,25 +2,49 @@ package router
 
 import (
 	_ "github.com/gadz82/go-api-boilerplate/docs"
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/apierror"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/apikeys"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/health"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/middleware"
 	v1 "github.com/gadz82/go-api-boilerplate/internal/delivery/http/v1"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(itemHandler *items.ItemHandler, itemPropertyHandler *items.ItemPropertyHandler) *gin.Engine {
-	r := gin.Default()
+func NewRouter(itemHandler *items.ItemHandler, itemPropertyHandler *items.ItemPropertyHandler, auditHandler *items.AuditHandler, operationsHandler *items.OperationsHandler, apiKeyHandler *apikeys.APIKeyHandler, healthHandler *health.HealthHandler, verifier domain.TokenVerifier, apiKeyService domain.APIKeyService, cache domain.CacheRepository, cfg *config.Config) *gin.Engine {
+	r := gin.New()
+	// Errors, including panics and unknown routes, are answered with JSON:API error documents
+	r.Use(gin.Logger(), gin.CustomRecovery(func(c *gin.Context, _ any) {
+		apierror.Write(c, apierror.Internal())
+	}), middleware.RequestInfoMiddleware())
+	r.NoRoute(func(c *gin.Context) {
+		apierror.Write(c, apierror.NotFound("The requested route does not exist"))
+	})
 	// Set to specific IPs like []string{"192.168.1.0/24"} if behind a known proxy
 	err := r.SetTrustedProxies(nil)
 	if err != nil {
 		return nil
 	}
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/healthz", healthHandler.Live)
+	r.GET("/readyz", healthHandler.Ready)
 
 	api := r.Group("/api")
 	{
-		v1.RegisterRoutes(api, itemHandler, itemPropertyHandler)
+		tenantMiddleware := middleware.TenantMiddleware(middleware.TenantConfig{
+			Header:        cfg.TenantHeader,
+			BaseDomain:    cfg.TenantBaseDomain,
+			DefaultTenant: cfg.DefaultTenant,
+		})
+		idempotencyMiddleware := middleware.IdempotencyMiddleware(cache, middleware.IdempotencyConfig{
+			TTL:     cfg.IdempotencyTTL,
+			LockTTL: cfg.IdempotencyLockTTL,
+		})
+		v1.RegisterRoutes(api, itemHandler, itemPropertyHandler, auditHandler, operationsHandler, apiKeyHandler, middleware.AuthMiddleware(verifier, apiKeyService), tenantMiddleware, idempotencyMiddleware)
 	}
 
 	return r
@@ -2,14 +2,26 @@ package router
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/apikeys"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/health"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/service/auth"
 	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/jsonapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,17 +31,28 @@ type MockItemService struct {
 	mock.Mock
 }
 
-func (m *MockItemService) GetAllItems(ctx context.Context) ([]*domain.Item, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*domain.Item), args.Error(1)
+func (m *MockItemService) GetAllItems(ctx context.Context, query domain.ItemQuery) (*domain.Page[*domain.Item], domain.Validators, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(domain.Validators), args.Error(2)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Get(1).(domain.Validators), args.Error(2)
 }
 
-func (m *MockItemService) GetItemByID(ctx context.Context, id string) (*domain.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) SearchItems(ctx context.Context, search domain.ItemSearch) (*domain.Page[*domain.ItemSearchResult], error) {
+	args := m.Called(ctx, search)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.Item), args.Error(1)
+	return args.Get(0).(*domain.Page[*domain.ItemSearchResult]), args.Error(1)
+}
+
+func (m *MockItemService) GetItemByID(ctx context.Context, id string, opts domain.QueryOptions) (*domain.Item, domain.Validators, error) {
+	args := m.Called(ctx, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(domain.Validators), args.Error(2)
+	}
+	return args.Get(0).(*domain.Item), args.Get(1).(domain.Validators), args.Error(2)
 }
 
 func (m *MockItemService) CreateItem(ctx context.Context, item *domain.Item) error {
@@ -42,23 +65,111 @@ func (m *MockItemService) UpdateItem(ctx context.Context, item *domain.Item) err
 	return args.Error(0)
 }
 
-func (m *MockItemService) DeleteItem(ctx context.Context, id string) error {
+func (m *MockItemService) PatchItem(ctx context.Context, id string, patch *domain.Item, mask domain.FieldMask) (*domain.Item, error) {
+	args := m.Called(ctx, id, patch, mask)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) DeleteItem(ctx context.Context, id string, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemService) ListDeletedItems(ctx context.Context, page domain.PageRequest) (*domain.Page[*domain.Item], error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Error(1)
+}
+
+func (m *MockItemService) RestoreItem(ctx context.Context, id string) (*domain.Item, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) PurgeItem(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockItemService) ListItemRevisions(ctx context.Context, id string, page domain.PageRequest) (*domain.Page[*domain.ItemRevision], error) {
+	args := m.Called(ctx, id, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.ItemRevision]), args.Error(1)
+}
+
+func (m *MockItemService) GetItemAsOf(ctx context.Context, id string, at time.Time, opts domain.QueryOptions) (*domain.Item, error) {
+	args := m.Called(ctx, id, at, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) RevertItem(ctx context.Context, id string, revision int64, version int64) (*domain.Item, error) {
+	args := m.Called(ctx, id, revision, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemService) UpdateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemService) DeleteItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	args := m.Called(ctx, items, mode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
 // MockItemPropertyService implements domain.ItemPropertyService for testing
 type MockItemPropertyService struct {
 	mock.Mock
 }
 
-func (m *MockItemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string) ([]*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID)
-	return args.Get(0).([]*domain.ItemProperty), args.Error(1)
+func (m *MockItemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string, page domain.PageRequest, opts domain.QueryOptions) (*domain.Page[*domain.ItemProperty], error) {
+	args := m.Called(ctx, itemID, page, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.ItemProperty]), args.Error(1)
+}
+
+func (m *MockItemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string, opts domain.QueryOptions) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, itemID, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemProperty), args.Error(1)
 }
 
-func (m *MockItemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string) (*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyService) FindItemProperty(ctx context.Context, id string) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -75,11 +186,124 @@ func (m *MockItemPropertyService) UpdateItemProperty(ctx context.Context, itemPr
 	return args.Error(0)
 }
 
-func (m *MockItemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string) error {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyService) PatchItemProperty(ctx context.Context, itemID string, id string, patch *domain.ItemProperty, mask domain.FieldMask) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, itemID, id, patch, mask)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemProperty), args.Error(1)
+}
+
+func (m *MockItemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string, version int64) error {
+	args := m.Called(ctx, itemID, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyService) AttachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyService) ReplaceItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
 	return args.Error(0)
 }
 
+func (m *MockItemPropertyService) DetachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
+	return args.Error(0)
+}
+
+// MockAuditService implements domain.AuditService for testing
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) ListAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.Page[*domain.AuditEvent], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.AuditEvent]), args.Error(1)
+}
+
+// MockOperationService implements domain.OperationService for testing
+type MockOperationService struct {
+	mock.Mock
+}
+
+func (m *MockOperationService) ApplyOperations(ctx context.Context, operations []*domain.Operation) error {
+	args := m.Called(ctx, operations)
+	return args.Error(0)
+}
+
+// newTestOperationsHandler returns an operations handler over a service that applies every request
+func newTestOperationsHandler() *items.OperationsHandler {
+	service := new(MockOperationService)
+	service.On("ApplyOperations", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return items.NewOperationsHandler(service, newMockLogger(), newTestConfig())
+}
+
+// MockAPIKeyService implements domain.APIKeyService for testing
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) IssueAPIKey(ctx context.Context, apiKey *domain.APIKey) error {
+	args := m.Called(ctx, apiKey)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RotateAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	args := m.Called(ctx, plaintext)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Principal), args.Error(1)
+}
+
+// MockHealthService implements domain.HealthService for testing
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) Readiness(ctx context.Context) *domain.Readiness {
+	args := m.Called(ctx)
+	return args.Get(0).(*domain.Readiness)
+}
+
+func (m *MockHealthService) ShutDown() {
+	m.Called()
+}
+
+// newTestHealthHandler returns a health handler whose dependencies are all up
+func newTestHealthHandler() *health.HealthHandler {
+	healthService := new(MockHealthService)
+	healthService.On("Readiness", mock.Anything).Return(&domain.Readiness{
+		Ready:        true,
+		Dependencies: map[string]domain.DependencyHealth{"database": {Status: domain.HealthStatusUp, Backend: "mysql"}},
+	})
+	return health.NewHealthHandler(healthService)
+}
+
 // MockValidator implements domain.Validator for testing
 type MockValidator struct {
 	mock.Mock
@@ -114,23 +338,148 @@ func newMockLogger() logging.Logger {
 	return &MockLogger{}
 }
 
+// memoryCache is a CacheRepository keeping entries in memory, for the routes keeping responses
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newTestCache() *memoryCache {
+	return &memoryCache{entries: map[string]string{}}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	if !ok {
+		return "", errors.New("cache miss")
+	}
+	return value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (c *memoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memoryCache) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return false, nil
+	}
+	c.entries[key] = token
+	return true, nil
+}
+
+func (c *memoryCache) Unlock(ctx context.Context, key string, token string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == token {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+const testJWTSecret = "router-test-secret"
+
+// newTestConfig returns the configuration shared by the test verifier and routers
+func newTestConfig() *config.Config {
+	return &config.Config{
+		JWTSecret:          testJWTSecret,
+		TenantHeader:       "X-Tenant-ID",
+		TenantClaim:        "tenant_id",
+		DefaultTenant:      "default",
+		PageDefaultSize:    20,
+		PageMaxSize:        100,
+		BulkMaxItems:       1000,
+		IdempotencyTTL:     time.Hour,
+		IdempotencyLockTTL: time.Minute,
+	}
+}
+
+// newTestVerifier returns a JWT verifier accepting HS256 tokens signed with testJWTSecret
+func newTestVerifier() domain.TokenVerifier {
+	verifier, err := auth.NewJWTVerifier(newTestConfig())
+	if err != nil {
+		panic(err)
+	}
+	return verifier
+}
+
+// signTestToken signs the given claims with testJWTSecret, expiring in an hour
+func signTestToken(claims jwt.MapClaims) string {
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// newTestToken returns a signed bearer token for the given subject and scopes
+func newTestToken(subject string, scopes ...string) string {
+	return signTestToken(jwt.MapClaims{"sub": subject, "scope": strings.Join(scopes, " ")})
+}
+
+// newAdminTestToken returns a signed bearer token carrying the admin role
+func newAdminTestToken(subject string) string {
+	return signTestToken(jwt.MapClaims{"sub": subject, "roles": []string{domain.RoleAdmin}})
+}
+
+// allScopes grants access to every item and item property route
+var allScopes = []string{
+	domain.ScopeItemsRead, domain.ScopeItemsWrite,
+	domain.ScopeItemPropertiesRead, domain.ScopeItemPropertiesWrite,
+}
+
 func createTestHandlers() (*items.ItemHandler, *items.ItemPropertyHandler) {
 	mockItemService := new(MockItemService)
 	mockItemPropertyService := new(MockItemPropertyService)
 	mockValidator := new(MockValidator)
 	mockLogger := newMockLogger()
 
-	itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger)
-	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator)
+	itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger, newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator, newTestConfig())
 
 	return itemHandler, itemPropertyHandler
 }
 
+// newTestRouter builds a router with the test JWT verifier and an API key service that rejects every key
+func newTestRouter(itemHandler *items.ItemHandler, itemPropertyHandler *items.ItemPropertyHandler) *gin.Engine {
+	apiKeyService := new(MockAPIKeyService)
+	apiKeyService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, domain.ErrUnauthenticated)
+	apiKeyHandler := apikeys.NewAPIKeyHandler(apiKeyService, new(MockValidator), newMockLogger())
+
+	auditHandler := items.NewAuditHandler(new(MockAuditService), newTestConfig())
+
+	return NewRouter(itemHandler, itemPropertyHandler, auditHandler, newTestOperationsHandler(), apiKeyHandler, newTestHealthHandler(), newTestVerifier(), apiKeyService, newTestCache(), newTestConfig())
+}
+
 func TestNewRouter_ReturnsValidEngine(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	itemHandler, itemPropertyHandler := createTestHandlers()
-	router := NewRouter(itemHandler, itemPropertyHandler)
+	router := newTestRouter(itemHandler, itemPropertyHandler)
 
 	assert.NotNil(t, router, "Router should not be nil")
 }
@@ -139,7 +488,7 @@ func TestNewRouter_SwaggerRouteRegistered(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	itemHandler, itemPropertyHandler := createTestHandlers()
-	router := NewRouter(itemHandler, itemPropertyHandler)
+	router := newTestRouter(itemHandler, itemPropertyHandler)
 
 	// Test that swagger wildcard route exists by checking /swagger/
 	// The route is registered as /swagger/*any
@@ -164,16 +513,17 @@ func TestNewRouter_APIRoutesRegistered(t *testing.T) {
 	mockLogger := newMockLogger()
 
 	// Setup mock expectations for GetAllItems
-	mockItemService.On("GetAllItems", mock.Anything).Return([]*domain.Item{}, nil)
+	mockItemService.On("GetAllItems", mock.Anything, mock.Anything).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, domain.Validators{}, nil)
 
-	itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger)
-	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator)
+	itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger, newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator, newTestConfig())
 
-	router := NewRouter(itemHandler, itemPropertyHandler)
+	router := newTestRouter(itemHandler, itemPropertyHandler)
 
 	// Test that /api/v1/items route exists
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/api/v1/items", nil)
+	req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", domain.ScopeItemsRead))
 	router.ServeHTTP(w, req)
 
 	// Should return 200 (success) not 404
@@ -188,20 +538,29 @@ func TestNewRouter_ItemsEndpoints(t *testing.T) {
 		name           string
 		method         string
 		path           string
+		authenticated  bool
 		expectedStatus int
 	}{
 		{
 			name:           "GET /api/v1/items returns valid response",
 			method:         http.MethodGet,
 			path:           "/api/v1/items",
+			authenticated:  true,
 			expectedStatus: http.StatusOK,
 		},
 		{
 			name:           "GET /api/v1/items/:id with invalid UUID returns 400",
 			method:         http.MethodGet,
 			path:           "/api/v1/items/invalid-uuid",
+			authenticated:  true,
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "GET /api/v1/items returns 401 without auth",
+			method:         http.MethodGet,
+			path:           "/api/v1/items",
+			expectedStatus: http.StatusUnauthorized,
+		},
 		{
 			name:           "DELETE /api/v1/items/:id returns 401 without auth",
 			method:         http.MethodDelete,
@@ -225,15 +584,18 @@ func TestNewRouter_ItemsEndpoints(t *testing.T) {
 			mockLogger := newMockLogger()
 
 			if tc.method == http.MethodGet && tc.path == "/api/v1/items" {
-				mockItemService.On("GetAllItems", mock.Anything).Return([]*domain.Item{}, nil)
+				mockItemService.On("GetAllItems", mock.Anything, mock.Anything).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, domain.Validators{}, nil)
 			}
 
-			itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger)
-			itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator)
-			router := NewRouter(itemHandler, itemPropertyHandler)
+			itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger, newTestConfig())
+			itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator, newTestConfig())
+			router := newTestRouter(itemHandler, itemPropertyHandler)
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			if tc.authenticated {
+				req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", allScopes...))
+			}
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatus, w.Code)
@@ -277,28 +639,567 @@ func TestNewRouter_ItemPropertiesEndpoints(t *testing.T) {
 			mockValidator := new(MockValidator)
 			mockLogger := newMockLogger()
 
-			itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger)
-			itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator)
-			router := NewRouter(itemHandler, itemPropertyHandler)
+			itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger, newTestConfig())
+			itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator, newTestConfig())
+			router := newTestRouter(itemHandler, itemPropertyHandler)
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", allScopes...))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestNewRouter_AuthenticatedRouteAcceptsValidJWT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockItemService := new(MockItemService)
+	mockItemPropertyService := new(MockItemPropertyService)
+	mockValidator := new(MockValidator)
+	mockLogger := newMockLogger()
+
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	mockItemService.On("DeleteItem", mock.Anything, testUUID, int64(0)).Return(nil)
+
+	itemHandler := items.NewItemHandler(mockItemService, mockValidator, mockLogger, newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, mockValidator, newTestConfig())
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/items/"+testUUID, nil)
+	req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", domain.ScopeItemsWrite))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestNewRouter_AuthenticatedRouteRejectsForgedJWT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	itemHandler, itemPropertyHandler := createTestHandlers()
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-123",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("not-the-secret"))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/items/550e8400-e29b-41d4-a716-446655440000", nil)
+	req.Header.Set("Authorization", "Bearer "+forged)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"unauthorized"`)
+}
+
+func TestNewRouter_AuthenticatedRouteAcceptsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockItemService := new(MockItemService)
+	mockAPIKeyService := new(MockAPIKeyService)
+
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	testAPIKey := "ak_0123abcd_secret"
+	mockItemService.On("DeleteItem", mock.Anything, testUUID, int64(0)).Return(nil)
+	mockAPIKeyService.On("Authenticate", mock.Anything, testAPIKey).
+		Return(&domain.Principal{Subject: "billing-service", AuthMethod: domain.AuthMethodAPIKey, Scopes: []string{domain.ScopeItemsWrite}}, nil)
+
+	itemHandler := items.NewItemHandler(mockItemService, new(MockValidator), newMockLogger(), newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(new(MockItemPropertyService), new(MockValidator), newTestConfig())
+	apiKeyHandler := apikeys.NewAPIKeyHandler(mockAPIKeyService, new(MockValidator), newMockLogger())
+	router := NewRouter(itemHandler, itemPropertyHandler, items.NewAuditHandler(new(MockAuditService), newTestConfig()), newTestOperationsHandler(), apiKeyHandler, newTestHealthHandler(), newTestVerifier(), mockAPIKeyService, newTestCache(), newTestConfig())
+
+	for _, setHeader := range []func(*http.Request){
+		func(req *http.Request) { req.Header.Set("X-API-Key", testAPIKey) },
+		func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+testAPIKey) },
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/items/"+testUUID, nil)
+		setHeader(req)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	}
+	mockAPIKeyService.AssertExpectations(t)
+}
+
+func TestNewRouter_IdempotentCreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockItemService := new(MockItemService)
+	mockValidator := new(MockValidator)
+	mockItemService.On("CreateItem", mock.Anything, mock.Anything).Return(nil).Once()
+	mockValidator.On("Validate", mock.Anything).Return(nil)
+
+	itemHandler := items.NewItemHandler(mockItemService, mockValidator, newMockLogger(), newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(new(MockItemPropertyService), mockValidator, newTestConfig())
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", domain.ScopeItemsWrite))
+		req.Header.Set("Idempotency-Key", "create-lamp")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	body := `{"data":{"type":"items","attributes":{"title":"Lamp"}}}`
+
+	first := create(body)
+	retry := create(body)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, first.Header().Get("ETag"), retry.Header().Get("ETag"))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	mockItemService.AssertNumberOfCalls(t, "CreateItem", 1)
+
+	// The key cannot be reused for another item
+	w := create(`{"data":{"type":"items","attributes":{"title":"Desk"}}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockItemService.AssertNumberOfCalls(t, "CreateItem", 1)
+}
+
+func TestNewRouter_IdempotentAPIKeyNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAPIKeyService := new(MockAPIKeyService)
+	mockAPIKeyService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, domain.ErrUnauthenticated)
+	calls := 0
+	mockAPIKeyService.On("IssueAPIKey", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		calls++
+		apiKey := args.Get(1).(*domain.APIKey)
+		apiKey.ID = "550e8400-e29b-41d4-a716-446655440000"
+		apiKey.Key = fmt.Sprintf("ak_plaintext_%d", calls)
+	}).Return(nil)
+	mockValidator := new(MockValidator)
+	mockValidator.On("Validate", mock.Anything).Return(nil)
+
+	itemHandler, itemPropertyHandler := createTestHandlers()
+	apiKeyHandler := apikeys.NewAPIKeyHandler(mockAPIKeyService, mockValidator, newMockLogger())
+	cache := newTestCache()
+	router := NewRouter(itemHandler, itemPropertyHandler, items.NewAuditHandler(new(MockAuditService), newTestConfig()), newTestOperationsHandler(), apiKeyHandler, newTestHealthHandler(), newTestVerifier(), mockAPIKeyService, cache, newTestConfig())
+
+	issue := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/api_keys", strings.NewReader(`{"data":{"type":"api_keys","attributes":{"owner":"billing-service"}}}`))
+		req.Header.Set("Authorization", "Bearer "+newAdminTestToken("admin-1"))
+		req.Header.Set("Idempotency-Key", "issue-key")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	first := issue()
+	retry := issue()
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Contains(t, first.Body.String(), "ak_plaintext_1")
+	// The plaintext key is not replayed, which would require keeping it
+	assert.Empty(t, retry.Header().Get("Idempotent-Replayed"))
+	for _, value := range cache.entries {
+		assert.NotContains(t, value, "ak_plaintext_")
+	}
+}
+
+func TestNewRouter_IdempotentForbiddenNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockItemService := new(MockItemService)
+	mockValidator := new(MockValidator)
+	mockItemService.On("CreateItem", mock.Anything, mock.Anything).Return(nil)
+	mockValidator.On("Validate", mock.Anything).Return(nil)
+
+	itemHandler := items.NewItemHandler(mockItemService, mockValidator, newMockLogger(), newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(new(MockItemPropertyService), mockValidator, newTestConfig())
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	create := func(scopes ...string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(`{"data":{"type":"items","attributes":{"title":"Lamp"}}}`))
+		req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", scopes...))
+		req.Header.Set("Idempotency-Key", "create-lamp")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, create(domain.ScopeItemsRead).Code)
+	// Once granted the scope, the client retries with the same key
+	w := create(domain.ScopeItemsWrite)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	mockItemService.AssertNumberOfCalls(t, "CreateItem", 1)
+}
+
+func TestNewRouter_TenantResolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testUUID := "550e8400-e29b-41d4-a716-446655440000"
+	acmeToken := signTestToken(jwt.MapClaims{"sub": "user-123", "scope": domain.ScopeItemsRead, "tenant_id": "acme"})
+	unboundToken := newTestToken("user-123", domain.ScopeItemsRead)
+
+	testCases := []struct {
+		name           string
+		token          string
+		tenantHeader   string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{name: "Tenant from claim", token: acmeToken, expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Matching header", token: acmeToken, tenantHeader: "acme", expectedStatus: http.StatusOK, expectedTenant: "acme"},
+		{name: "Default tenant for unbound token", token: unboundToken, expectedStatus: http.StatusOK, expectedTenant: "default"},
+		{name: "Cross-tenant header returns 404", token: acmeToken, tenantHeader: "globex", expectedStatus: http.StatusNotFound},
+		{name: "Unbound token naming a tenant returns 404", token: unboundToken, tenantHeader: "acme", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockItemService := new(MockItemService)
+			var tenant string
+			mockItemService.On("GetItemByID", mock.Anything, testUUID, mock.Anything).Run(func(args mock.Arguments) {
+				tenant, _ = domain.TenantFromContext(args.Get(0).(context.Context))
+			}).Return(&domain.Item{ID: testUUID, Title: "Test"}, domain.Validators{}, nil)
+
+			itemHandler := items.NewItemHandler(mockItemService, new(MockValidator), newMockLogger(), newTestConfig())
+			itemPropertyHandler := items.NewItemPropertyHandler(new(MockItemPropertyService), new(MockValidator), newTestConfig())
+			router := newTestRouter(itemHandler, itemPropertyHandler)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/items/"+testUUID, nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			if tc.tenantHeader != "" {
+				req.Header.Set("X-Tenant-ID", tc.tenantHeader)
+			}
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, tc.expectedTenant, tenant)
+			} else {
+				mockItemService.AssertNotCalled(t, "GetItemByID", mock.Anything, mock.Anything, mock.Anything)
+			}
 		})
 	}
 }
 
+func TestNewRouter_AdminAPIKeysEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "Without auth returns 401",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Without admin role returns 403",
+			token:          newTestToken("user-123"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "With admin role returns 200",
+			token:          newAdminTestToken("admin-1"),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPIKeyService := new(MockAPIKeyService)
+			mockAPIKeyService.On("ListAPIKeys", mock.Anything).Return([]*domain.APIKey{}, nil)
+
+			itemHandler, itemPropertyHandler := createTestHandlers()
+			apiKeyHandler := apikeys.NewAPIKeyHandler(mockAPIKeyService, new(MockValidator), newMockLogger())
+			router := NewRouter(itemHandler, itemPropertyHandler, items.NewAuditHandler(new(MockAuditService), newTestConfig()), newTestOperationsHandler(), apiKeyHandler, newTestHealthHandler(), newTestVerifier(), mockAPIKeyService, newTestCache(), newTestConfig())
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/api_keys", nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				mockAPIKeyService.AssertNotCalled(t, "ListAPIKeys", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestNewRouter_AdminTrashEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	itemID := "550e8400-e29b-41d4-a716-446655440000"
+
+	routes := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/admin/trash/items", http.StatusOK},
+		{http.MethodPost, "/api/v1/admin/trash/items/" + itemID + "/restore", http.StatusOK},
+		{http.MethodDelete, "/api/v1/admin/trash/items/" + itemID, http.StatusNoContent},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			mockItemService := new(MockItemService)
+			mockItemService.On("ListDeletedItems", mock.Anything, mock.Anything).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, nil).Maybe()
+			mockItemService.On("RestoreItem", mock.Anything, itemID).Return(&domain.Item{ID: itemID, Title: "Lamp", Version: 2}, nil).Maybe()
+			mockItemService.On("PurgeItem", mock.Anything, itemID).Return(nil).Maybe()
+			itemHandler := items.NewItemHandler(mockItemService, new(MockValidator), newMockLogger(), newTestConfig())
+			_, itemPropertyHandler := createTestHandlers()
+			router := newTestRouter(itemHandler, itemPropertyHandler)
+
+			// Every scope of items is not enough, the trash is for admins only
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(route.method, route.path, nil)
+			req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", allScopes...))
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Empty(t, mockItemService.Calls)
+
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest(route.method, route.path, nil)
+			req.Header.Set("Authorization", "Bearer "+newAdminTestToken("admin-1"))
+			router.ServeHTTP(w, req)
+			assert.Equal(t, route.status, w.Code)
+		})
+	}
+}
+
+func TestNewRouter_AuditEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuditService := new(MockAuditService)
+	var request domain.RequestInfo
+	mockAuditService.On("ListAuditEvents", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		request, _ = domain.RequestInfoFromContext(args.Get(0).(context.Context))
+	}).Return(&domain.Page[*domain.AuditEvent]{Items: []*domain.AuditEvent{}}, nil).Maybe()
+	apiKeyService := new(MockAPIKeyService)
+	apiKeyService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, domain.ErrUnauthenticated)
+	itemHandler, itemPropertyHandler := createTestHandlers()
+	router := NewRouter(itemHandler, itemPropertyHandler, items.NewAuditHandler(mockAuditService, newTestConfig()), newTestOperationsHandler(),
+		apikeys.NewAPIKeyHandler(apiKeyService, new(MockValidator), newMockLogger()), newTestHealthHandler(), newTestVerifier(), apiKeyService, newTestCache(), newTestConfig())
+
+	// Every scope of items is not enough, the audit log is for admins only
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/audit", nil)
+	req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", allScopes...))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockAuditService.AssertNotCalled(t, "ListAuditEvents", mock.Anything, mock.Anything)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/audit?resource=items/550e8400-e29b-41d4-a716-446655440000", nil)
+	req.Header.Set("Authorization", "Bearer "+newAdminTestToken("admin-1"))
+	req.Header.Set("X-Request-ID", "req-42")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-42", w.Header().Get("X-Request-ID"))
+	// The request info reaches the services, which record it with the changes they make
+	assert.Equal(t, "req-42", request.ID)
+	mockAuditService.AssertCalled(t, "ListAuditEvents", mock.Anything, mock.MatchedBy(func(query domain.AuditQuery) bool {
+		return query.ResourceType == domain.ResourceItems && query.ResourceID == "550e8400-e29b-41d4-a716-446655440000"
+	}))
+}
+
+func TestNewRouter_PermissionMatrix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Services accept anything, so allowed requests reach the handlers and denied ones never do
+	mockItemService := new(MockItemService)
+	mockItemService.On("GetAllItems", mock.Anything, mock.Anything).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, domain.Validators{}, nil).Maybe()
+	mockItemService.On("SearchItems", mock.Anything, mock.Anything).Return(&domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{}}, nil).Maybe()
+	mockItemService.On("GetItemByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.Validators{}, errors.New("not found")).Maybe()
+	mockItemService.On("DeleteItem", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockItemService.On("ListItemRevisions", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Page[*domain.ItemRevision]{Items: []*domain.ItemRevision{}}, nil).Maybe()
+	mockItemService.On("RevertItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found")).Maybe()
+	mockItemPropertyService := new(MockItemPropertyService)
+	mockItemPropertyService.On("GetItemPropertiesByItemID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domain.Page[*domain.ItemProperty]{Items: []*domain.ItemProperty{}}, nil).Maybe()
+	mockItemPropertyService.On("GetItemPropertyByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("not found")).Maybe()
+	mockItemPropertyService.On("DeleteItemProperty", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	itemHandler := items.NewItemHandler(mockItemService, new(MockValidator), newMockLogger(), newTestConfig())
+	itemPropertyHandler := items.NewItemPropertyHandler(mockItemPropertyService, new(MockValidator), newTestConfig())
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	itemPath := "/api/v1/items/550e8400-e29b-41d4-a716-446655440000"
+	propertyPath := itemPath + "/item_properties/6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	relationshipPath := itemPath + "/relationships/item_properties"
+
+	routes := []struct {
+		method string
+		path   string
+		scopes []string
+	}{
+		{http.MethodGet, "/api/v1/items", []string{domain.ScopeItemsRead}},
+		{http.MethodGet, "/api/v1/items?include=item_properties", []string{domain.ScopeItemsRead, domain.ScopeItemPropertiesRead}},
+		{http.MethodPost, "/api/v1/items", []string{domain.ScopeItemsWrite}},
+		{http.MethodPost, "/api/v1/items/bulk", []string{domain.ScopeItemsWrite}},
+		{http.MethodPut, "/api/v1/items/bulk", []string{domain.ScopeItemsWrite}},
+		{http.MethodDelete, "/api/v1/items/bulk", []string{domain.ScopeItemsWrite}},
+		{http.MethodGet, "/api/v1/items/search?q=lamp", []string{domain.ScopeItemsRead}},
+		{http.MethodGet, "/api/v1/items/search?q=lamp&include=item_properties", []string{domain.ScopeItemsRead, domain.ScopeItemPropertiesRead}},
+		{http.MethodGet, itemPath, []string{domain.ScopeItemsRead}},
+		{http.MethodGet, itemPath + "?include=item_properties", []string{domain.ScopeItemsRead, domain.ScopeItemPropertiesRead}},
+		{http.MethodPut, itemPath, []string{domain.ScopeItemsWrite}},
+		{http.MethodPatch, itemPath, []string{domain.ScopeItemsWrite}},
+		{http.MethodDelete, itemPath, []string{domain.ScopeItemsWrite}},
+		{http.MethodGet, itemPath + "/revisions", []string{domain.ScopeItemsRead, domain.ScopeItemPropertiesRead}},
+		{http.MethodPost, itemPath + "/revisions/1/restore", []string{domain.ScopeItemsWrite}},
+		{http.MethodGet, itemPath + "/item_properties", []string{domain.ScopeItemPropertiesRead}},
+		{http.MethodPost, itemPath + "/item_properties", []string{domain.ScopeItemPropertiesWrite}},
+		{http.MethodGet, propertyPath, []string{domain.ScopeItemPropertiesRead}},
+		{http.MethodPut, propertyPath, []string{domain.ScopeItemPropertiesWrite}},
+		{http.MethodPatch, propertyPath, []string{domain.ScopeItemPropertiesWrite}},
+		{http.MethodDelete, propertyPath, []string{domain.ScopeItemPropertiesWrite}},
+		{http.MethodGet, relationshipPath, []string{domain.ScopeItemsRead, domain.ScopeItemPropertiesRead}},
+		{http.MethodPost, relationshipPath, []string{domain.ScopeItemsWrite, domain.ScopeItemPropertiesWrite}},
+		{http.MethodPatch, relationshipPath, []string{domain.ScopeItemsWrite, domain.ScopeItemPropertiesWrite}},
+		{http.MethodDelete, relationshipPath, []string{domain.ScopeItemsWrite, domain.ScopeItemPropertiesWrite}},
+	}
+
+	principals := []struct {
+		name   string
+		token  string
+		scopes []string
+		admin  bool
+	}{
+		{name: "anonymous"},
+		{name: "no scopes", token: newTestToken("user-123")},
+		{name: "items:read", token: newTestToken("user-123", domain.ScopeItemsRead), scopes: []string{domain.ScopeItemsRead}},
+		{name: "items:write", token: newTestToken("user-123", domain.ScopeItemsWrite), scopes: []string{domain.ScopeItemsWrite}},
+		{name: "item_properties:read", token: newTestToken("user-123", domain.ScopeItemPropertiesRead), scopes: []string{domain.ScopeItemPropertiesRead}},
+		{name: "item_properties:write", token: newTestToken("user-123", domain.ScopeItemPropertiesWrite), scopes: []string{domain.ScopeItemPropertiesWrite}},
+		{name: "all scopes", token: newTestToken("user-123", allScopes...), scopes: allScopes},
+		{name: "admin", token: newAdminTestToken("admin-1"), admin: true},
+	}
+
+	for _, route := range routes {
+		for _, principal := range principals {
+			t.Run(route.method+" "+route.path+" as "+principal.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest(route.method, route.path, nil)
+				if principal.token != "" {
+					req.Header.Set("Authorization", "Bearer "+principal.token)
+				}
+				router.ServeHTTP(w, req)
+
+				switch {
+				case principal.token == "":
+					assert.Equal(t, http.StatusUnauthorized, w.Code)
+				case principal.admin || containsAll(principal.scopes, route.scopes):
+					assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+					assert.NotEqual(t, http.StatusForbidden, w.Code)
+				default:
+					assert.Equal(t, http.StatusForbidden, w.Code)
+					assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+					assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+
+					var body struct {
+						Errors []*jsonapi.ErrorObject `json:"errors"`
+					}
+					assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+					if assert.Len(t, body.Errors, 1) {
+						assert.Equal(t, "403", body.Errors[0].Status)
+						assert.Equal(t, "insufficient_scope", body.Errors[0].Code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRouter_OperationsScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	itemHandler, itemPropertyHandler := createTestHandlers()
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	addItem := `{"op": "add", "data": {"type": "items", "lid": "lamp", "attributes": {"title": "Lamp"}}}`
+	addProperty := `{"op": "add", "data": {"type": "item_properties", "attributes": {"name": "color", "value": "red"},
+		"relationships": {"item": {"data": {"type": "items", "lid": "lamp"}}}}}`
+
+	tests := []struct {
+		name           string
+		scopes         []string
+		operations     []string
+		expectedStatus int
+	}{
+		{"items with items:write", []string{domain.ScopeItemsWrite}, []string{addItem}, http.StatusOK},
+		{"items with items:read", []string{domain.ScopeItemsRead}, []string{addItem}, http.StatusForbidden},
+		{"properties without item_properties:write", []string{domain.ScopeItemsWrite}, []string{addItem, addProperty}, http.StatusForbidden},
+		{"properties with item_properties:write", []string{domain.ScopeItemsWrite, domain.ScopeItemPropertiesWrite}, []string{addItem, addProperty}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"atomic:operations": [` + strings.Join(tt.operations, ",") + `]}`
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/operations", strings.NewReader(body))
+			req.Header.Set("Content-Type", `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`)
+			req.Header.Set("Authorization", "Bearer "+newTestToken("user-123", tt.scopes...))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func containsAll(granted []string, required []string) bool {
+	for _, r := range required {
+		found := false
+		for _, g := range granted {
+			if g == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewRouter_HealthEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	itemHandler, itemPropertyHandler := createTestHandlers()
+	router := newTestRouter(itemHandler, itemPropertyHandler)
+
+	// The probes need no credentials
+	for _, path := range []string{"/healthz", "/readyz"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"), path)
+	}
+}
+
 func TestNewRouter_NonExistentRouteReturns404(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	itemHandler, itemPropertyHandler := createTestHandlers()
-	router := NewRouter(itemHandler, itemPropertyHandler)
+	router := newTestRouter(itemHandler, itemPropertyHandler)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/api/v1/nonexistent", nil)
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code, "Non-existent route should return 404")
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"not_found"`)
 }
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/apikeys"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/middleware"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(rg *gin.RouterGroup, apiKeyHandler *apikeys.APIKeyHandler, itemHandler *items.ItemHandler, auditHandler *items.AuditHandler, authMiddleware gin.HandlerFunc, tenantMiddleware gin.HandlerFunc, idempotencyMiddleware gin.HandlerFunc) {
+	adminGroup := rg.Group("/admin")
+	adminGroup.Use(authMiddleware, tenantMiddleware, middleware.RequireRole(domain.RoleAdmin), idempotencyMiddleware)
+	{
+		apiKeyGroup := adminGroup.Group("/api_keys")
+		{
+			apiKeyGroup.GET("", apiKeyHandler.GetAll)
+			apiKeyGroup.POST("", apiKeyHandler.Create)
+			apiKeyGroup.POST("/:id/rotate", apiKeyHandler.Rotate)
+			apiKeyGroup.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+
+		trashGroup := adminGroup.Group("/trash/items")
+		{
+			trashGroup.GET("", itemHandler.GetDeleted)
+			trashGroup.POST("/:id/restore", itemHandler.Restore)
+			trashGroup.DELETE("/:id", itemHandler.Purge)
+		}
+	}
+
+	// The audit log is admin-only too, though it lives outside /admin
+	auditGroup := rg.Group("/audit")
+	auditGroup.Use(authMiddleware, tenantMiddleware, middleware.RequireRole(domain.RoleAdmin))
+	{
+		auditGroup.GET("", auditHandler.GetAll)
+	}
+}
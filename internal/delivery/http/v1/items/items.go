@@ -5,26 +5,39 @@ import (
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/middleware"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/v1/items/items_properties"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
 )
 
-func RegisterRoutes(rg *gin.RouterGroup, handler *items.ItemHandler, propertyHandler *items.ItemPropertyHandler) {
+func RegisterRoutes(rg *gin.RouterGroup, handler *items.ItemHandler, propertyHandler *items.ItemPropertyHandler, authMiddleware gin.HandlerFunc, tenantMiddleware gin.HandlerFunc, idempotencyMiddleware gin.HandlerFunc) {
 	itemGroup := rg.Group("/items")
+	itemGroup.Use(authMiddleware, tenantMiddleware, idempotencyMiddleware)
 	{
-		// Public routes
-		itemGroup.GET("", handler.GetAll)
-		itemGroup.GET("/:id", handler.GetByID)
-		itemGroup.POST("", handler.Create)
+		read := middleware.RequireScopes(domain.ScopeItemsRead)
+		write := middleware.RequireScopes(domain.ScopeItemsWrite)
+		// Including properties also requires permission to read them
+		include := middleware.RequireIncludeScopes(map[string]string{
+			"item_properties": domain.ScopeItemPropertiesRead,
+		})
+		// Creating properties along with an item also requires permission to write them
+		relationships := middleware.RequireRelationshipScopes(map[string]string{
+			"item_properties": domain.ScopeItemPropertiesWrite,
+		})
+
+		itemGroup.GET("", read, include, handler.GetAll)
+		itemGroup.GET("/search", read, include, handler.Search)
+		itemGroup.GET("/:id", read, include, handler.GetByID)
+		itemGroup.POST("", write, relationships, handler.Create)
+		itemGroup.POST("/bulk", write, handler.BulkCreate)
+		itemGroup.PUT("/bulk", write, handler.BulkUpdate)
+		itemGroup.DELETE("/bulk", write, handler.BulkDelete)
+		itemGroup.PUT("/:id", write, handler.Update)
+		itemGroup.PATCH("/:id", write, handler.Patch)
+		itemGroup.DELETE("/:id", write, handler.Delete)
+		// Revisions hold snapshots of the properties too
+		itemGroup.GET("/:id/revisions", read, middleware.RequireScopes(domain.ScopeItemPropertiesRead), handler.GetRevisions)
+		itemGroup.POST("/:id/revisions/:rev/restore", write, handler.RestoreRevision)
 
 		// Nested property routes
 		items_properties.RegisterRoutes(itemGroup, propertyHandler)
-
-		// Authenticated routes
-		authorized := itemGroup.Group("")
-		authorized.Use(middleware.AuthMiddleware())
-		{
-			authorized.PUT("/:id", handler.Update)
-			authorized.PATCH("/:id", handler.Patch)
-			authorized.DELETE("/:id", handler.Delete)
-		}
 	}
 }
@@ -4,20 +4,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/middleware"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
 )
 
+// RegisterRoutes registers the property routes on the item group, which is already authenticated.
 func RegisterRoutes(rg *gin.RouterGroup, propertyHandler *items.ItemPropertyHandler) {
 	properties := rg.Group("/:id/item_properties")
 	{
-		properties.GET("", propertyHandler.GetAll)
-		properties.GET("/:property_id", propertyHandler.GetByID)
-		authorized := properties.Group("/")
-		authorized.Use(middleware.AuthMiddleware())
-		{
-			properties.POST("", propertyHandler.Create)
-			properties.PUT("/:property_id", propertyHandler.Update)
-			properties.PATCH("/:property_id", propertyHandler.Patch)
-			properties.DELETE("/:property_id", propertyHandler.Delete)
-		}
+		read := middleware.RequireScopes(domain.ScopeItemPropertiesRead)
+		write := middleware.RequireScopes(domain.ScopeItemPropertiesWrite)
+
+		properties.GET("", read, propertyHandler.GetAll)
+		properties.GET("/:property_id", read, propertyHandler.GetByID)
+		properties.POST("", write, propertyHandler.Create)
+		properties.PUT("/:property_id", write, propertyHandler.Update)
+		properties.PATCH("/:property_id", write, propertyHandler.Patch)
+		properties.DELETE("/:property_id", write, propertyHandler.Delete)
+	}
+
+	// The relationship is part of the item as well as a list of its properties
+	relationship := rg.Group("/:id/relationships/item_properties")
+	{
+		read := middleware.RequireScopes(domain.ScopeItemsRead, domain.ScopeItemPropertiesRead)
+		write := middleware.RequireScopes(domain.ScopeItemsWrite, domain.ScopeItemPropertiesWrite)
+
+		relationship.GET("", read, propertyHandler.GetRelationship)
+		relationship.POST("", write, propertyHandler.AddRelationship)
+		relationship.PATCH("", write, propertyHandler.ReplaceRelationship)
+		relationship.DELETE("", write, propertyHandler.RemoveRelationship)
 	}
 }
@@ -0,0 +1,23 @@
+package operations
+
+import (
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/middleware"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers the atomic operations endpoint, whose operations each need
+// the write scope of the type of resource they change.
+func RegisterRoutes(rg *gin.RouterGroup, handler *items.OperationsHandler, authMiddleware gin.HandlerFunc, tenantMiddleware gin.HandlerFunc, idempotencyMiddleware gin.HandlerFunc) {
+	operations := rg.Group("/operations")
+	operations.Use(authMiddleware, tenantMiddleware, idempotencyMiddleware)
+	{
+		write := middleware.RequireOperationScopes(map[string]string{
+			domain.ResourceItems:          domain.ScopeItemsWrite,
+			domain.ResourceItemProperties: domain.ScopeItemPropertiesWrite,
+		})
+
+		operations.POST("", write, handler.Operations)
+	}
+}
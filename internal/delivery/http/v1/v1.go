@@ -1,14 +1,19 @@
 package v1
 
 import (
-	"github.com/gin-gonic/gin"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/apikeys"
 	items2 "github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/v1/admin"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/v1/items"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/v1/operations"
+	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(rg *gin.RouterGroup, itemHandler *items2.ItemHandler, itemPropertyHandler *items2.ItemPropertyHandler) {
+func RegisterRoutes(rg *gin.RouterGroup, itemHandler *items2.ItemHandler, itemPropertyHandler *items2.ItemPropertyHandler, auditHandler *items2.AuditHandler, operationsHandler *items2.OperationsHandler, apiKeyHandler *apikeys.APIKeyHandler, authMiddleware gin.HandlerFunc, tenantMiddleware gin.HandlerFunc, idempotencyMiddleware gin.HandlerFunc) {
 	v1 := rg.Group("/v1")
 	{
-		items.RegisterRoutes(v1, itemHandler, itemPropertyHandler)
+		items.RegisterRoutes(v1, itemHandler, itemPropertyHandler, authMiddleware, tenantMiddleware, idempotencyMiddleware)
+		operations.RegisterRoutes(v1, operationsHandler, authMiddleware, tenantMiddleware, idempotencyMiddleware)
+		admin.RegisterRoutes(v1, apiKeyHandler, itemHandler, auditHandler, authMiddleware, tenantMiddleware, idempotencyMiddleware)
 	}
 }
@@ -2,12 +2,15 @@ package di
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/gadz82/go-api-boilerplate/internal/config"
 	"github.com/gadz82/go-api-boilerplate/internal/database"
+	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/apikeys"
+	healthHandlers "github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/health"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/handlers/items"
 	"github.com/gadz82/go-api-boilerplate/internal/delivery/http/router"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
@@ -15,8 +18,12 @@ import (
 	repoMysql "github.com/gadz82/go-api-boilerplate/internal/repository/mysql"
 	redisRepo "github.com/gadz82/go-api-boilerplate/internal/repository/redis"
 	"github.com/gadz82/go-api-boilerplate/internal/server"
+	"github.com/gadz82/go-api-boilerplate/internal/service/audit"
+	"github.com/gadz82/go-api-boilerplate/internal/service/auth"
+	"github.com/gadz82/go-api-boilerplate/internal/service/health"
 	items2 "github.com/gadz82/go-api-boilerplate/internal/service/items"
 	"github.com/gadz82/go-api-boilerplate/internal/service/logging"
+	"github.com/gadz82/go-api-boilerplate/internal/service/retention"
 	"github.com/gadz82/go-api-boilerplate/internal/validation"
 	"go.uber.org/fx"
 	mysqlDriver "gorm.io/driver/mysql"
@@ -24,9 +31,11 @@ import (
 	"gorm.io/gorm"
 )
 
-// NewModule creates the main application module with all dependencies wired together.
-func NewModule() fx.Option {
+// NewModule creates the main application module with all dependencies wired together,
+// given the application configuration.
+func NewModule(cfg *config.Config) fx.Option {
 	return fx.Module("app",
+		fx.Supply(cfg),
 		fx.Options(
 			provideInfrastructure(),
 			provideRepositories(),
@@ -34,15 +43,15 @@ func NewModule() fx.Option {
 			provideHandlers(),
 			provideHTTP(),
 		),
-		fx.Invoke(server.RegisterHooks),
+		// health.RegisterHooks stops first, so the server serves while it reports unready
+		fx.Invoke(server.RegisterHooks, retention.RegisterHooks, health.RegisterHooks),
 	)
 }
 
 // provideInfrastructure provides core infrastructure dependencies:
-// configuration, database connection, validator, and logging service.
+// database connection, validator, and logging service.
 func provideInfrastructure() fx.Option {
 	return fx.Provide(
-		config.LoadConfig,
 		NewGormDB,
 		validation.NewValidator,
 		logging.NewLoggingService,
@@ -54,7 +63,11 @@ func provideRepositories() fx.Option {
 	return fx.Provide(
 		repoMysql.NewItemRepository,
 		repoMysql.NewItemPropertyRepository,
+		repoMysql.NewAPIKeyRepository,
+		repoMysql.NewAuditRepository,
+		repoMysql.NewTransactionManager,
 		NewCacheRepository,
+		NewHealthChecks,
 	)
 }
 
@@ -63,6 +76,12 @@ func provideServices() fx.Option {
 	return fx.Provide(
 		items2.NewItemService,
 		items2.NewItemPropertyService,
+		items2.NewOperationService,
+		auth.NewJWTVerifier,
+		auth.NewAPIKeyService,
+		audit.NewAuditService,
+		retention.NewJob,
+		health.NewHealthService,
 	)
 }
 
@@ -71,13 +90,18 @@ func provideHandlers() fx.Option {
 	return fx.Provide(
 		items.NewItemHandler,
 		items.NewItemPropertyHandler,
+		items.NewAuditHandler,
+		items.NewOperationsHandler,
+		apikeys.NewAPIKeyHandler,
+		healthHandlers.NewHealthHandler,
 	)
 }
 
-// provideHTTP provides HTTP-related dependencies: router and middleware.
+// provideHTTP provides HTTP-related dependencies: router, middleware and server.
 func provideHTTP() fx.Option {
 	return fx.Provide(
 		router.NewRouter,
+		server.NewServer,
 	)
 }
 
@@ -87,14 +111,25 @@ func provideHTTP() fx.Option {
 func NewGormDB(cfg *config.Config) (*gorm.DB, error) {
 	var dialect string
 
+	// Dialect errors such as duplicate keys are translated to GORM errors, which handlers map to responses
+	gormConfig := &gorm.Config{TranslateError: true}
+
 	dsn := cfg.GetMySQLDSN()
-	db, err := gorm.Open(mysqlDriver.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysqlDriver.Open(dsn), gormConfig)
 	if err != nil {
 		log.Printf("Failed to connect to MySQL: %v. Falling back to SQLite for demo.", err)
-		db, err = gorm.Open(sqlite.Open("gorm.db"), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open("gorm.db"), gormConfig)
 		if err != nil {
 			return nil, err
 		}
+		// Item search needs FTS5, which the migrations of SQLite set up
+		var fts5 bool
+		if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil {
+			return nil, err
+		}
+		if !fts5 {
+			return nil, errors.New("SQLite was built without FTS5: build the application with -tags sqlite_fts5 to fall back to SQLite")
+		}
 		dialect = "sqlite3"
 	} else {
 		dialect = "mysql"
@@ -115,9 +150,18 @@ func NewGormDB(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// CacheBackend names the implementation of the cache repository in use.
+type CacheBackend string
+
+const (
+	CacheBackendRedis CacheBackend = "redis"
+	CacheBackendFile  CacheBackend = "file"
+)
+
 // NewCacheRepository creates a cache repository.
-// It attempts to connect to Redis first, falling back to file-based cache if Redis is unavailable.
-func NewCacheRepository(cfg *config.Config) (domain.CacheRepository, error) {
+// It attempts to connect to Redis first, falling back to file-based cache if Redis is unavailable,
+// and returns the backend it uses.
+func NewCacheRepository(cfg *config.Config) (domain.CacheRepository, CacheBackend, error) {
 	// Try Redis first
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.GetRedisAddr(),
@@ -135,12 +179,57 @@ func NewCacheRepository(cfg *config.Config) (domain.CacheRepository, error) {
 		// Fall back to file-based cache
 		fileCache, err := fileRepo.NewCacheRepository(cfg.CacheDir)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		log.Printf("Using file-based cache in directory: %s", cfg.CacheDir)
-		return fileCache, nil
+		return fileCache, CacheBackendFile, nil
 	}
 
 	log.Printf("Connected to Redis at %s", cfg.GetRedisAddr())
-	return redisRepo.NewCacheRepository(redisClient), nil
+	return redisRepo.NewCacheRepository(redisClient), CacheBackendRedis, nil
+}
+
+// NewHealthChecks returns the checks of the dependencies the application needs to be
+// ready: the database connection, the migrations of the database, all applied, and the
+// cache. SQLite and the file-based cache are reported as fallbacks.
+func NewHealthChecks(db *gorm.DB, cache domain.CacheRepository, cacheBackend CacheBackend) ([]domain.HealthCheck, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	dialect := db.Dialector.Name()
+	fallback := dialect != "mysql"
+	// goose names the dialect of SQLite after its driver
+	gooseDialect := dialect
+	if dialect == "sqlite" {
+		gooseDialect = "sqlite3"
+	}
+	migrator := database.NewMigrator(sqlDB, gooseDialect)
+
+	return []domain.HealthCheck{
+		{
+			Name:     "database",
+			Backend:  dialect,
+			Fallback: fallback,
+			Check:    sqlDB.PingContext,
+		},
+		{
+			Name:     "migrations",
+			Backend:  dialect,
+			Fallback: fallback,
+			Check: func(ctx context.Context) error {
+				pending, err := migrator.HasPending(ctx)
+				if err == nil && pending {
+					err = errors.New("migrations are pending")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "cache",
+			Backend:  string(cacheBackend),
+			Fallback: cacheBackend != CacheBackendRedis,
+			Check:    cache.Ping,
+		},
+	}, nil
 }
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// APIKeyTokenPrefix marks a credential as an API key rather than a JWT.
+// Plaintext keys have the form "ak_<prefix>_<secret>".
+const APIKeyTokenPrefix = "ak_"
+
+// ErrAPIKeyRevoked is returned when trying to rotate a key that has been revoked.
+var ErrAPIKeyRevoked = fmt.Errorf("%w: api key is revoked", ErrConflict)
+
+type APIKey struct {
+	ID         string     `jsonapi:"primary,api_keys" json:"id" gorm:"primaryKey;type:char(36)"`
+	Prefix     string     `jsonapi:"attr,prefix" json:"prefix" gorm:"uniqueIndex;type:varchar(16)"`
+	KeyHash    string     `json:"key_hash" gorm:"type:char(64)"`
+	Owner      string     `jsonapi:"attr,owner" json:"owner" gorm:"index" validate:"required,max=255"`
+	Scopes     []string   `jsonapi:"attr,scopes" json:"scopes" gorm:"serializer:json;type:varchar(1000)" validate:"dive,min=1,max=100"`
+	CreatedAt  time.Time  `jsonapi:"attr,created_at,iso8601" json:"created_at"`
+	LastUsedAt *time.Time `jsonapi:"attr,last_used_at,iso8601,omitempty" json:"last_used_at,omitempty" gorm:"type:timestamp;default:null"`
+	ExpiresAt  *time.Time `jsonapi:"attr,expires_at,iso8601,omitempty" json:"expires_at,omitempty" gorm:"type:timestamp;default:null"`
+	Revoked    bool       `jsonapi:"attr,revoked" json:"revoked" gorm:"not null;default:false"`
+	// TenantID is the tenant the key was issued in; the key only authenticates within it.
+	TenantID string `json:"tenant_id" gorm:"index;type:varchar(255);not null;default:''"`
+
+	// Key holds the plaintext key. It is only populated in the response
+	// that issues or rotates the key and is never persisted.
+	Key string `jsonapi:"attr,key,omitempty" json:"-" gorm:"-"`
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.Revoked {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// APIKeyRepository queries are scoped to the tenant in the context, except
+// GetByPrefix and UpdateLastUsed which run before a tenant is resolved.
+type APIKeyRepository interface {
+	GetAll(ctx context.Context) ([]*APIKey, error)
+	GetByID(ctx context.Context, id string) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	Create(ctx context.Context, apiKey *APIKey) error
+	Update(ctx context.Context, apiKey *APIKey) error
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+}
+
+type APIKeyService interface {
+	// IssueAPIKey generates a new key for apiKey.Owner in the tenant of ctx and populates apiKey.Key with the plaintext.
+	IssueAPIKey(ctx context.Context, apiKey *APIKey) error
+	ListAPIKeys(ctx context.Context) ([]*APIKey, error)
+	// RotateAPIKey replaces the secret of an existing key, invalidating the previous one.
+	// The returned key carries the new plaintext in Key.
+	RotateAPIKey(ctx context.Context, id string) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	// Authenticate resolves a plaintext key to a Principal.
+	// Returns an error wrapping ErrUnauthenticated for unknown, revoked or expired keys.
+	Authenticate(ctx context.Context, plaintext string) (*Principal, error)
+}
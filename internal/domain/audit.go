@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionPatch   = "patch"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+	AuditActionRevert  = "revert"
+)
+
+// AuditActions lists every action recorded in the audit log.
+var AuditActions = []string{AuditActionCreate, AuditActionUpdate, AuditActionPatch, AuditActionDelete, AuditActionRestore, AuditActionPurge, AuditActionRevert}
+
+// AuditEvent records a change made to an item or an item property: who made it, in
+// which request, and the attributes it changed. Events are append-only: they are
+// written in the transaction of the change and never updated or deleted, not even
+// when the resource they record is purged.
+type AuditEvent struct {
+	ID       string `jsonapi:"primary,audit_events" json:"id" gorm:"primaryKey;type:char(36)"`
+	TenantID string `json:"tenant_id" gorm:"index;type:varchar(255);not null;default:''"`
+	// Actor is the subject of the principal that made the change.
+	Actor        string `jsonapi:"attr,actor" json:"actor" gorm:"type:varchar(255);not null"`
+	Action       string `jsonapi:"attr,action" json:"action" gorm:"type:varchar(16);not null"`
+	ResourceType string `jsonapi:"attr,resource_type" json:"resource_type" gorm:"type:varchar(64);not null"`
+	ResourceID   string `jsonapi:"attr,resource_id" json:"resource_id" gorm:"type:char(36);not null"`
+	// Before and After hold the attributes the change modified, as they were before and
+	// after it. A created resource has no Before, a purged one has no After.
+	Before    map[string]any `jsonapi:"attr,before" json:"before" gorm:"column:before_values;serializer:json;type:text"`
+	After     map[string]any `jsonapi:"attr,after" json:"after" gorm:"column:after_values;serializer:json;type:text"`
+	RequestID string         `jsonapi:"attr,request_id" json:"request_id" gorm:"type:varchar(255);not null;default:''"`
+	IP        string         `jsonapi:"attr,ip" json:"ip" gorm:"type:varchar(45);not null;default:''"`
+	CreatedAt time.Time      `jsonapi:"attr,created_at,iso8601" json:"created_at"`
+}
+
+// AuditQuery selects and paginates the events of the audit log, most recent first.
+type AuditQuery struct {
+	// ResourceType and ResourceID match the events of a resource type, or of one
+	// resource when ResourceID is set too.
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Actor        string `json:"actor,omitempty"`
+	Action       string `json:"action,omitempty"`
+	// CreatedAt matches the events recorded within the range.
+	CreatedAt TimeRange   `json:"created_at"`
+	Page      PageRequest `json:"page"`
+}
+
+// AuditRepository reads the audit log of the tenant in the context. Events are
+// written by the item and item property repositories along with their changes.
+type AuditRepository interface {
+	GetAll(ctx context.Context, query AuditQuery) (*Page[*AuditEvent], error)
+}
+
+type AuditService interface {
+	ListAuditEvents(ctx context.Context, query AuditQuery) (*Page[*AuditEvent], error)
+}
+
+type auditActionContextKey struct{}
+
+// ContextWithAuditAction returns a copy of ctx whose updates are recorded in the audit log
+// as action rather than as AuditActionUpdate, e.g. AuditActionPatch for a patch saved by Update.
+func ContextWithAuditAction(ctx context.Context, action string) context.Context {
+	return context.WithValue(ctx, auditActionContextKey{}, action)
+}
+
+// AuditActionFromContext returns the action set by ContextWithAuditAction, if any.
+func AuditActionFromContext(ctx context.Context) (string, bool) {
+	action, ok := ctx.Value(auditActionContextKey{}).(string)
+	return action, ok && action != ""
+}
@@ -0,0 +1,12 @@
+package domain
+
+// BulkMode tells a bulk write what to do with the elements that succeed when others fail.
+type BulkMode string
+
+const (
+	// BulkAllOrNothing applies the elements only if every one of them succeeds; otherwise
+	// none is applied, and those that did not fail are reported as ErrBulkAborted.
+	BulkAllOrNothing BulkMode = "all_or_nothing"
+	// BulkBestEffort applies the elements that succeed and reports those that fail.
+	BulkBestEffort BulkMode = "best_effort"
+)
@@ -24,4 +24,14 @@ type CacheRepository interface {
 
 	// Ping checks if the cache backend is available.
 	Ping(ctx context.Context) error
+
+	// Lock acquires the lock named key on behalf of the holder identified by token, and
+	// reports whether it did: false when the lock is already held. The lock is held until
+	// it is released with Unlock or ttl has passed, whichever comes first. Acquiring is
+	// atomic, so that concurrent callers, in this process or another, never both hold it.
+	Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error)
+
+	// Unlock releases the lock named key if it is still held with token, so that a holder
+	// whose lock expired never releases the lock of the next holder.
+	Unlock(ctx context.Context, key string, token string) error
 }
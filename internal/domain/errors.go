@@ -0,0 +1,24 @@
+package domain
+
+import "errors"
+
+// Errors returned by repositories and services. Implementations wrap them, keeping
+// the underlying error in the chain, so callers test for them with errors.Is.
+var (
+	// ErrNotFound is returned when a resource does not exist or is not visible to the caller.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a change conflicts with the stored state, e.g. a duplicate key.
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is returned when a conditional change expects a version of a
+	// resource that is no longer the stored one.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrValidation is returned when a resource is invalid; ValidationErrors match it.
+	ErrValidation = errors.New("validation failed")
+	// ErrForbidden is returned when the caller is known but not allowed to perform an operation.
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnavailable is returned when the storage cannot be reached or does not answer in time.
+	ErrUnavailable = errors.New("unavailable")
+	// ErrBulkAborted is returned for the elements of an all-or-nothing bulk write that
+	// did not fail themselves but were not applied because other elements did.
+	ErrBulkAborted = errors.New("bulk write aborted")
+)
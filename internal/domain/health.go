@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses of a dependency checked for readiness
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheck checks a dependency the application needs to serve requests.
+type HealthCheck struct {
+	// Name identifies the dependency, e.g. database or cache.
+	Name string
+	// Backend names the implementation of the dependency in use, e.g. mysql or sqlite.
+	Backend string
+	// Fallback reports that Backend stands in for the preferred implementation, which was
+	// unavailable at startup, so that the application runs degraded.
+	Fallback bool
+	// Check returns an error when the dependency is not usable.
+	Check func(ctx context.Context) error
+}
+
+// DependencyHealth is the outcome of the check of a dependency.
+type DependencyHealth struct {
+	Status   string
+	Backend  string
+	Fallback bool
+	Latency  time.Duration
+}
+
+// Readiness reports whether the application can serve requests, with the health of each
+// of its dependencies by name.
+type Readiness struct {
+	Ready bool
+	// ShuttingDown reports that the application is stopping, which makes it unready
+	// whatever the health of its dependencies.
+	ShuttingDown bool
+	// Degraded reports that a dependency runs on its fallback implementation.
+	Degraded     bool
+	Dependencies map[string]DependencyHealth
+}
+
+type HealthService interface {
+	// Readiness checks every dependency, at the same time.
+	Readiness(ctx context.Context) *Readiness
+	// ShutDown makes the application unready for good, for the time it drains its requests.
+	ShutDown()
+}
@@ -3,29 +3,117 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/google/jsonapi"
+	"gorm.io/gorm"
 )
 
+// ItemsPath is the path of the items collection in the API, which the links of items lead into.
+const ItemsPath = "/api/v1/items"
+
 type Item struct {
 	ID             string          `jsonapi:"primary,items" json:"id" gorm:"primaryKey;type:char(36)" validate:"omitempty,uuid4"`
 	Title          string          `jsonapi:"attr,title" json:"title" gorm:"index" validate:"required,min=1,max=255"`
 	Description    string          `jsonapi:"attr,description" json:"description" validate:"max=1000"`
+	OwnerID        string          `jsonapi:"attr,owner_id" json:"owner_id" gorm:"index;type:varchar(255);not null;default:''"`
+	TenantID       string          `json:"tenant_id" gorm:"index;type:varchar(255);not null;default:''"`
 	CreatedAt      *time.Time      `jsonapi:"attr,created_at,iso8601" json:"created_at,omitempty" gorm:"type:timestamp;default:null"`
 	UpdatedAt      time.Time       `jsonapi:"attr,updated_at,iso8601" json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Version        int64           `json:"version" gorm:"not null;default:1"`
+	DeletedAt      gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
 	ItemProperties []*ItemProperty `jsonapi:"relation,item_properties" json:"item_properties,omitempty" gorm:"foreignKey:ItemID;constraint:OnDelete:CASCADE"`
 }
 
+// JSONAPIRelationshipLinks returns the links of a relationship of the item in JSON:API
+// documents: self to the relationship itself, and related to the related resources.
+func (i *Item) JSONAPIRelationshipLinks(relation string) *jsonapi.Links {
+	if relation != ResourceItemProperties || i.ID == "" {
+		return nil
+	}
+	return &jsonapi.Links{
+		KeySelfLink: ItemsPath + "/" + i.ID + "/relationships/" + relation,
+		"related":   ItemsPath + "/" + i.ID + "/" + relation,
+	}
+}
+
+// ItemRepository queries are scoped to the tenant and the principal in the context:
+// admins see every item of their tenant, other callers only the items they own.
+// Create assigns the item to the tenant in the context.
+//
+// Update and Delete are conditional when given a version other than 0 (item.Version for
+// Update): the change applies only if the stored item is still at that version, and
+// fails with ErrPreconditionFailed otherwise. Update sets item.Version to the new version.
+//
+// Delete is a soft delete: the item and its properties are kept, hidden from every
+// query but GetDeleted, until they are restored or purged.
+//
+// Every change to an item or its properties stores a revision of the item (see
+// ItemRevision), which the revision queries read even once the item is deleted.
 type ItemRepository interface {
-	GetAll(ctx context.Context) ([]*Item, error)
-	GetByID(ctx context.Context, id string) (*Item, error)
+	GetAll(ctx context.Context, query ItemQuery) (*Page[*Item], error)
+	// Search returns the items matching the search, best matches first.
+	Search(ctx context.Context, search ItemSearch) (*Page[*ItemSearchResult], error)
+	GetByID(ctx context.Context, id string, opts QueryOptions) (*Item, error)
 	Create(ctx context.Context, item *Item) error
 	Update(ctx context.Context, item *Item) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
+	// GetDeleted returns a page of the soft-deleted items, most recently deleted first.
+	GetDeleted(ctx context.Context, page PageRequest) (*Page[*Item], error)
+	// Restore undeletes a soft-deleted item along with the properties deleted with it.
+	Restore(ctx context.Context, id string) error
+	// Purge permanently deletes a soft-deleted item and all of its properties.
+	Purge(ctx context.Context, id string) error
+	// PurgeDeleted permanently deletes the items and properties soft-deleted before
+	// the given time, in every tenant, and returns the number of rows it deleted.
+	// It runs outside of any request, so it is not scoped to a tenant or principal.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	GetRevisions(ctx context.Context, id string, page PageRequest) (*Page[*ItemRevision], error)
+	GetRevision(ctx context.Context, id string, revision int64) (*ItemRevision, error)
+	// GetRevisionAt returns the latest revision of an item stored at or before the given time.
+	GetRevisionAt(ctx context.Context, id string, at time.Time) (*ItemRevision, error)
+	// CreateBatch, UpdateBatch and DeleteBatch are the bulk counterparts of Create, Update
+	// and Delete, writing every item with a few batched statements in one transaction.
+	CreateBatch(ctx context.Context, items []*Item) error
+	// UpdateBatch and DeleteBatch return the error of each item, indexed like items:
+	// ErrNotFound, ErrPreconditionFailed, or ErrConflict for an item given more than
+	// once. They write the other items and read their stored state back into them.
+	// DeleteBatch deletes the items identified by ID, each at its Version when it is not 0.
+	UpdateBatch(ctx context.Context, items []*Item) ([]error, error)
+	DeleteBatch(ctx context.Context, items []*Item) ([]error, error)
 }
 
+// GetAllItems and GetItemByID return the validators of the page or item along with it,
+// computed from its cached representation, so that they cost no database query while
+// it is cached.
 type ItemService interface {
-	GetAllItems(ctx context.Context) ([]*Item, error)
-	GetItemByID(ctx context.Context, id string) (*Item, error)
+	GetAllItems(ctx context.Context, query ItemQuery) (*Page[*Item], Validators, error)
+	SearchItems(ctx context.Context, search ItemSearch) (*Page[*ItemSearchResult], error)
+	GetItemByID(ctx context.Context, id string, opts QueryOptions) (*Item, Validators, error)
 	CreateItem(ctx context.Context, item *Item) error
+	// UpdateItem replaces the item, conditionally when item.Version is not 0 (see ItemRepository).
 	UpdateItem(ctx context.Context, item *Item) error
-	DeleteItem(ctx context.Context, id string) error
+	// PatchItem changes the fields of the item in mask to their values in patch,
+	// validates the result and returns the updated item. When patch.Version is not 0
+	// the item must still be at that version.
+	PatchItem(ctx context.Context, id string, patch *Item, mask FieldMask) (*Item, error)
+	// DeleteItem deletes the item, conditionally when version is not 0 (see ItemRepository).
+	DeleteItem(ctx context.Context, id string, version int64) error
+	// ListDeletedItems, RestoreItem and PurgeItem manage the soft-deleted items (the trash).
+	ListDeletedItems(ctx context.Context, page PageRequest) (*Page[*Item], error)
+	// RestoreItem undeletes the item and its properties and returns the restored item.
+	RestoreItem(ctx context.Context, id string) (*Item, error)
+	// PurgeItem permanently deletes a soft-deleted item and its properties.
+	PurgeItem(ctx context.Context, id string) error
+	ListItemRevisions(ctx context.Context, id string, page PageRequest) (*Page[*ItemRevision], error)
+	// GetItemAsOf returns an item as it was at the given time, with its properties when opts includes them.
+	GetItemAsOf(ctx context.Context, id string, at time.Time, opts QueryOptions) (*Item, error)
+	// RevertItem saves the attributes an item had at a revision as a new change, at version when it is not 0.
+	RevertItem(ctx context.Context, id string, revision int64, version int64) (*Item, error)
+	// CreateItems, UpdateItems and DeleteItems write many items at once, in the given mode.
+	// They return the error of each element, indexed like items, nil for those applied;
+	// an error of their own fails every element. Created and updated items are validated.
+	CreateItems(ctx context.Context, items []*Item, mode BulkMode) ([]error, error)
+	UpdateItems(ctx context.Context, items []*Item, mode BulkMode) ([]error, error)
+	// DeleteItems deletes the items identified by ID, each at its Version when it is not 0.
+	DeleteItems(ctx context.Context, items []*Item, mode BulkMode) ([]error, error)
 }
@@ -1,26 +1,68 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 type ItemProperty struct {
-	ID     string `jsonapi:"primary,item_properties" json:"id" gorm:"primaryKey;type:char(36)" validate:"omitempty,uuid4"`
-	ItemID string `jsonapi:"attr,item_id" json:"item_id" gorm:"index;type:char(36)" validate:"omitempty,uuid4"`
-	Name   string `jsonapi:"attr,name" json:"name" gorm:"index" validate:"required,min=1,max=255"`
-	Value  string `jsonapi:"attr,value" json:"value" validate:"required,max=1000"`
+	ID        string         `jsonapi:"primary,item_properties" json:"id" gorm:"primaryKey;type:char(36)" validate:"omitempty,uuid4"`
+	ItemID    string         `jsonapi:"attr,item_id" json:"item_id" gorm:"index;type:char(36)" validate:"omitempty,uuid4"`
+	Name      string         `jsonapi:"attr,name" json:"name" gorm:"index" validate:"required,min=1,max=255"`
+	Value     string         `jsonapi:"attr,value" json:"value" validate:"required,max=1000"`
+	Version   int64          `json:"version" gorm:"not null;default:1"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
+// ItemPropertyFields lists the fields of item properties, as named in JSON:API documents.
+var ItemPropertyFields = []string{"item_id", "name", "value"}
+
+// ItemPropertyRepository queries are scoped to the properties of the items
+// visible to the principal in the context (see ItemRepository). Update and Delete
+// are conditional on the version of the property like those of ItemRepository,
+// and Delete is a soft delete like theirs.
+//
+// Attach, Replace and Detach change which properties an item has, the item_properties
+// relationship of the item, each in one transaction. A property belongs to exactly one
+// item, so attaching a property moves it from its item, and detaching one deletes it.
 type ItemPropertyRepository interface {
-	GetAllByItemID(ctx context.Context, itemID string) ([]*ItemProperty, error)
-	GetByID(ctx context.Context, itemID string, id string) (*ItemProperty, error)
+	GetAllByItemID(ctx context.Context, itemID string, page PageRequest, opts QueryOptions) (*Page[*ItemProperty], error)
+	GetByID(ctx context.Context, itemID string, id string, opts QueryOptions) (*ItemProperty, error)
+	// Find returns the property with the given ID, whichever visible item it belongs to.
+	Find(ctx context.Context, id string) (*ItemProperty, error)
 	Create(ctx context.Context, itemProperty *ItemProperty) error
 	Update(ctx context.Context, itemProperty *ItemProperty) error
-	Delete(ctx context.Context, itemID string, id string) error
+	Delete(ctx context.Context, itemID string, id string, version int64) error
+	// Attach moves the properties identified by ids, of any item visible to the caller, to
+	// the item and returns the items they were moved from. A property that is not visible
+	// fails the whole change with ErrNotFound.
+	Attach(ctx context.Context, itemID string, ids []string) ([]*Item, error)
+	// Replace attaches the properties identified by ids to the item like Attach, and
+	// deletes the other properties of the item.
+	Replace(ctx context.Context, itemID string, ids []string) ([]*Item, error)
+	// Detach deletes the properties of the item among ids; the others are left alone.
+	Detach(ctx context.Context, itemID string, ids []string) error
 }
 
 type ItemPropertyService interface {
-	GetItemPropertiesByItemID(ctx context.Context, itemID string) ([]*ItemProperty, error)
-	GetItemPropertyByID(ctx context.Context, itemID string, id string) (*ItemProperty, error)
+	GetItemPropertiesByItemID(ctx context.Context, itemID string, page PageRequest, opts QueryOptions) (*Page[*ItemProperty], error)
+	GetItemPropertyByID(ctx context.Context, itemID string, id string, opts QueryOptions) (*ItemProperty, error)
+	// FindItemProperty returns the property with the given ID, whichever item it belongs to.
+	// It reads the database rather than the cache, for changes to refer to the latest state.
+	FindItemProperty(ctx context.Context, id string) (*ItemProperty, error)
 	CreateItemProperty(ctx context.Context, itemProperty *ItemProperty) error
+	// UpdateItemProperty replaces the property, conditionally when itemProperty.Version is not 0.
 	UpdateItemProperty(ctx context.Context, itemProperty *ItemProperty) error
-	DeleteItemProperty(ctx context.Context, itemID string, id string) error
+	// PatchItemProperty changes the fields of the property in mask to their values
+	// in patch, validates the result and returns the updated property. When
+	// patch.Version is not 0 the property must still be at that version.
+	PatchItemProperty(ctx context.Context, itemID string, id string, patch *ItemProperty, mask FieldMask) (*ItemProperty, error)
+	// DeleteItemProperty deletes the property, conditionally when version is not 0.
+	DeleteItemProperty(ctx context.Context, itemID string, id string, version int64) error
+	// AttachItemProperties, ReplaceItemProperties and DetachItemProperties change the
+	// properties of the item as the repository's Attach, Replace and Detach do.
+	AttachItemProperties(ctx context.Context, itemID string, ids []string) error
+	ReplaceItemProperties(ctx context.Context, itemID string, ids []string) error
+	DetachItemProperties(ctx context.Context, itemID string, ids []string) error
 }
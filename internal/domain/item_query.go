@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Fields items can be sorted by.
+const (
+	ItemSortTitle     = "title"
+	ItemSortCreatedAt = "created_at"
+	ItemSortUpdatedAt = "updated_at"
+)
+
+// ItemSortFields lists every field items can be sorted by.
+var ItemSortFields = []string{ItemSortTitle, ItemSortCreatedAt, ItemSortUpdatedAt}
+
+// ItemIncludeProperties is the relationship path of the properties of items.
+const ItemIncludeProperties = "item_properties"
+
+// ItemIncludes lists every relationship path items can include.
+var ItemIncludes = []string{ItemIncludeProperties}
+
+// ItemFields lists the fields of items, as named in JSON:API documents.
+var ItemFields = []string{"title", "description", "owner_id", "created_at", "updated_at", "item_properties"}
+
+// SortField orders a list by one field, ascending unless Desc is set.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// TimeRange matches the times within all of its bounds. Nil bounds are not checked.
+type TimeRange struct {
+	GTE *time.Time `json:"gte,omitempty"`
+	GT  *time.Time `json:"gt,omitempty"`
+	LTE *time.Time `json:"lte,omitempty"`
+	LT  *time.Time `json:"lt,omitempty"`
+}
+
+// IsZero reports whether the range has no bounds.
+func (r TimeRange) IsZero() bool {
+	return r.GTE == nil && r.GT == nil && r.LTE == nil && r.LT == nil
+}
+
+// ItemQuery selects, orders and paginates the items of a list.
+type ItemQuery struct {
+	// TitlePrefix matches the items whose title starts with it.
+	TitlePrefix string `json:"title_prefix,omitempty"`
+	// CreatedAt matches the items created within the range.
+	CreatedAt TimeRange `json:"created_at"`
+	// Properties matches the items having a property with each name and value.
+	Properties map[string]string `json:"properties,omitempty"`
+	// Sort orders the items by each field in turn. Ties, and lists without sort
+	// fields, are ordered by ID.
+	Sort    []SortField  `json:"sort,omitempty"`
+	Page    PageRequest  `json:"page"`
+	Options QueryOptions `json:"options"`
+}
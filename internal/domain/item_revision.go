@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ItemRevision is a snapshot of an item and its properties, stored with every change to
+// either, so that the item can be read as it was at any past moment. Revisions of an
+// item are numbered from 1. The revision of a deletion holds the deleted item, with
+// DeletedAt set. Revisions are purged with their item.
+type ItemRevision struct {
+	ID       string `jsonapi:"primary,item_revisions" json:"id" gorm:"primaryKey;type:char(36)"`
+	ItemID   string `jsonapi:"attr,item_id" json:"item_id" gorm:"type:char(36);not null;uniqueIndex:idx_item_revisions_item_revision"`
+	Revision int64  `jsonapi:"attr,revision" json:"revision" gorm:"not null;uniqueIndex:idx_item_revisions_item_revision"`
+	// TenantID and OwnerID are those of the item, which revisions are visible with.
+	TenantID string `json:"tenant_id" gorm:"index;type:varchar(255);not null;default:''"`
+	OwnerID  string `json:"owner_id" gorm:"type:varchar(255);not null;default:''"`
+	// Item is the snapshot of the item, with the properties it had then.
+	Item      *Item     `jsonapi:"attr,item" json:"item" gorm:"column:snapshot;serializer:json;type:mediumtext"`
+	CreatedAt time.Time `jsonapi:"attr,created_at,iso8601" json:"created_at"`
+}
+
+// Deleted reports whether the revision records the deletion of its item.
+func (r *ItemRevision) Deleted() bool {
+	return r.Item == nil || r.Item.DeletedAt.Valid
+}
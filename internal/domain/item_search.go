@@ -0,0 +1,18 @@
+package domain
+
+// ItemSearch searches the title, description and property values of items.
+type ItemSearch struct {
+	// Text is matched word by word: items matching any of its words are found,
+	// and ranked higher the more and the rarer the words they match.
+	Text    string       `json:"text"`
+	Page    PageRequest  `json:"page"`
+	Options QueryOptions `json:"options"`
+}
+
+// ItemSearchResult is an item found by a search.
+type ItemSearchResult struct {
+	Item *Item `json:"item"`
+	// Score is the relevance of the item to the search, higher for better
+	// matches. Scores are only comparable within the results of one search.
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// OperationCode is the change an Operation makes to its resource.
+type OperationCode string
+
+const (
+	OperationAdd    OperationCode = "add"
+	OperationUpdate OperationCode = "update"
+	OperationRemove OperationCode = "remove"
+)
+
+// Operation is one change of an atomic request, which applies several changes to items
+// and item properties together (see OperationService). It changes either Item or Property.
+type Operation struct {
+	Code     OperationCode
+	Item     *Item
+	Property *ItemProperty
+	// Mask names the fields an update changes; the resource holds their new values, its ID
+	// and, when not 0, the version it must still be at. A removal only reads the ID and
+	// version of its resource. The properties updated or removed without an ItemID are
+	// looked up by ID.
+	Mask FieldMask
+}
+
+// OperationError is the error of the operation at Index of an atomic request, for which
+// every operation of the request was rolled back.
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+type OperationService interface {
+	// ApplyOperations applies the operations in order, in one transaction: either all of
+	// them are applied or none is, and the first to fail is returned as an *OperationError.
+	// The resources added and updated are validated, and their stored state is read back
+	// into their operations.
+	ApplyOperations(ctx context.Context, operations []*Operation) error
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "errors"
+
+// ErrInvalidCursor is returned when a page cursor is malformed or was not issued by the API.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// KeySelfLink is the key of the link to the resource or document itself in a JSON:API links
+// object, which jsonapi has no constant for.
+const KeySelfLink = "self"
+
+// PageRequest selects one page of a list. Pages are addressed by number
+// (1-based) when Number is set, and otherwise by cursor: the page following
+// the one that returned Cursor, or the first page if Cursor is empty.
+type PageRequest struct {
+	Number int    `json:"number"`
+	Size   int    `json:"size"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Offset returns the number of results before the requested page in number pagination.
+func (p PageRequest) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Page is one page of a list.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// Total is the number of results across every page.
+	Total int64 `json:"total"`
+	// NextCursor addresses the following page in cursor pagination. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
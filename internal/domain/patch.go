@@ -0,0 +1,38 @@
+package domain
+
+import "slices"
+
+// FieldMask names the fields a partial update changes, as named in JSON:API documents.
+// Fields outside the mask keep their stored values.
+type FieldMask []string
+
+// Has reports whether the mask changes field.
+func (m FieldMask) Has(field string) bool {
+	return slices.Contains(m, field)
+}
+
+// ItemPatchFields lists the fields of items a partial update can change; the others are managed by the server.
+var ItemPatchFields = []string{"title", "description"}
+
+// ItemPropertyPatchFields lists the fields of item properties a partial update can change.
+var ItemPropertyPatchFields = []string{"name", "value"}
+
+// Patch copies the fields of patch named in mask into i.
+func (i *Item) Patch(patch *Item, mask FieldMask) {
+	if mask.Has("title") {
+		i.Title = patch.Title
+	}
+	if mask.Has("description") {
+		i.Description = patch.Description
+	}
+}
+
+// Patch copies the fields of patch named in mask into p.
+func (p *ItemProperty) Patch(patch *ItemProperty, mask FieldMask) {
+	if mask.Has("name") {
+		p.Name = patch.Name
+	}
+	if mask.Has("value") {
+		p.Value = patch.Value
+	}
+}
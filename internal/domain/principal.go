@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnauthenticated is returned when a credential is missing, malformed or fails verification.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authentication methods a Principal can originate from.
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// RoleAdmin grants every scope as well as access to the admin API.
+const RoleAdmin = "admin"
+
+// Scopes required by the item and item property routes.
+const (
+	ScopeItemsRead           = "items:read"
+	ScopeItemsWrite          = "items:write"
+	ScopeItemPropertiesRead  = "item_properties:read"
+	ScopeItemPropertiesWrite = "item_properties:write"
+)
+
+// Principal represents the authenticated caller of a request.
+type Principal struct {
+	Subject    string
+	AuthMethod string
+	Issuer     string
+	Audience   []string
+	Scopes     []string
+	Roles      []string
+	// TenantID is the tenant the credential is bound to, empty if it is not bound to one.
+	TenantID  string
+	ExpiresAt *time.Time
+	// Claims holds the full set of verified token claims.
+	Claims map[string]interface{}
+}
+
+// HasRole reports whether the principal was granted the given role.
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the principal was granted the given scope.
+// Admins implicitly hold every scope.
+func (p *Principal) HasScope(scope string) bool {
+	if p.HasRole(RoleAdmin) {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenVerifier verifies bearer tokens and resolves them to a Principal.
+type TokenVerifier interface {
+	// Verify validates the token signature and registered claims.
+	// Returns an error wrapping ErrUnauthenticated if the token is not acceptable.
+	Verify(ctx context.Context, token string) (*Principal, error)
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the given principal.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
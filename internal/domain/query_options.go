@@ -0,0 +1,35 @@
+package domain
+
+import "slices"
+
+// Resource types, as named in JSON:API documents.
+const (
+	ResourceItems          = "items"
+	ResourceItemProperties = "item_properties"
+)
+
+// QueryOptions shapes the resources returned by a read: the related resources
+// loaded with them and the fields returned of each resource type.
+type QueryOptions struct {
+	// Include lists the relationship paths of the related resources to load, e.g. "item_properties".
+	Include []string `json:"include,omitempty"`
+	// Fields maps resource types to the only fields to return of them (a sparse
+	// fieldset). Every field is returned of types without an entry.
+	Fields map[string][]string `json:"fields,omitempty"`
+}
+
+// Includes reports whether the related resources at path are loaded.
+func (o QueryOptions) Includes(path string) bool {
+	return slices.Contains(o.Include, path)
+}
+
+// Fieldset returns the fields to return of a resource type. ok is false when every field is returned.
+func (o QueryOptions) Fieldset(resourceType string) (fields []string, ok bool) {
+	fields, ok = o.Fields[resourceType]
+	return fields, ok
+}
+
+// IsZero reports whether the options return whole resources without related ones.
+func (o QueryOptions) IsZero() bool {
+	return len(o.Include) == 0 && len(o.Fields) == 0
+}
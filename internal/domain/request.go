@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// RequestInfo identifies the HTTP request a change is made in, for the audit log.
+type RequestInfo struct {
+	// ID is the request ID, from the X-Request-ID header or generated.
+	ID string
+	// IP is the client IP address.
+	IP string
+}
+
+type requestInfoContextKey struct{}
+
+// ContextWithRequestInfo returns a copy of ctx carrying the given request info.
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info stored in ctx, if any.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoContextKey{}).(RequestInfo)
+	return info, ok
+}
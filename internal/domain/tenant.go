@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTenantRequired is returned when a tenant-scoped operation runs without a tenant in its context.
+var ErrTenantRequired = errors.New("tenant required")
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx scoped to the given tenant.
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant resolved for the current request, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
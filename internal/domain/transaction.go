@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"sync"
+)
+
+// TransactionManager runs operations spanning several repositories as one unit of work.
+type TransactionManager interface {
+	// WithinTransaction runs fn in a transaction, committed when fn returns nil and
+	// rolled back when it returns an error or panics. Repositories called with the
+	// context fn is given take part in the transaction; a WithinTransaction call
+	// with that context joins it rather than starting another. The functions
+	// registered with AfterCommit run once the transaction commits.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CommitHooks collects the functions to run once a transaction commits.
+type CommitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+type commitHooksContextKey struct{}
+
+// ContextWithCommitHooks returns a copy of ctx collecting the functions registered
+// with AfterCommit in the hooks it returns, for a TransactionManager to run on commit.
+func ContextWithCommitHooks(ctx context.Context) (context.Context, *CommitHooks) {
+	hooks := &CommitHooks{}
+	return context.WithValue(ctx, commitHooksContextKey{}, hooks), hooks
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, or right away when ctx
+// carries no transaction. fn never runs when the transaction is rolled back, so that
+// side effects such as cache invalidation are not triggered by changes that are undone.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksContextKey{}).(*CommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.hooks = append(hooks.hooks, fn)
+}
+
+// Run runs the registered functions, in the order they were registered.
+func (h *CommitHooks) Run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
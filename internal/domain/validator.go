@@ -4,6 +4,9 @@ package domain
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	// Pointer locates the invalid value in a JSON:API document, e.g. "/data/attributes/title".
+	// It is empty when the value does not come from a resource.
+	Pointer string `json:"pointer,omitempty"`
 }
 
 // ValidationErrors is a collection of validation errors
@@ -16,6 +19,11 @@ func (v ValidationErrors) Error() string {
 	return v[0].Message
 }
 
+// Is makes validation errors match ErrValidation.
+func (v ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // Validator defines the interface for validating domain objects
 // Following Interface Segregation Principle - only validation methods are exposed
 type Validator interface {
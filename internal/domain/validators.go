@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Validators describe one representation of a resource, or of a page of a list,
+// so that clients can ask for it only if it changed (conditional requests).
+type Validators struct {
+	// Digest is a hash of the representation, different for every content.
+	Digest string
+	// Version is the version of the resource represented, 0 for lists.
+	Version int64
+	// LastModified is when the representation last changed, zero when unknown.
+	LastModified time.Time
+}
@@ -1,15 +1,20 @@
 package file
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
 )
 
 // ErrCacheKeyNotFound is returned when a key is not found in the cache.
@@ -45,13 +50,11 @@ func NewCacheRepository(cacheDir string) (domain.CacheRepository, error) {
 }
 
 // keyToFilename converts a cache key to a safe filename.
+// Keys are hashed so that distinct keys (e.g. ones scoped to different owners)
+// can never collapse onto the same file.
 func (r *fileCacheRepository) keyToFilename(key string) string {
-	// Use a simple hash-like approach to create safe filenames
-	safeKey := filepath.Base(key)
-	if safeKey == "." || safeKey == "/" {
-		safeKey = "default"
-	}
-	return filepath.Join(r.cacheDir, safeKey+".cache")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(r.cacheDir, hex.EncodeToString(sum[:])+".cache")
 }
 
 func (r *fileCacheRepository) Get(ctx context.Context, key string) (string, error) {
@@ -151,3 +154,109 @@ func (r *fileCacheRepository) Ping(ctx context.Context) error {
 	_, err := os.Stat(r.cacheDir)
 	return err
 }
+
+// Lock creates the file of key, holding token, unless it exists and has not expired. The
+// file is written aside and then linked into place, which fails when the file exists, so
+// that processes sharing the cache directory do not both acquire a lock that is held. An
+// expired lock is released first (see releaseExpiredLock).
+func (r *fileCacheRepository) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filename := r.keyToFilename(key)
+	if data, err := os.ReadFile(filename); err == nil {
+		var item cacheItem
+		if err := json.Unmarshal(data, &item); err == nil && item.HasExpiry && time.Now().After(item.ExpiresAt) {
+			// An expired lock is free
+			if released, err := r.releaseExpiredLock(filename, data); err != nil || !released {
+				return false, err
+			}
+		}
+	}
+
+	item := cacheItem{
+		Value:     token,
+		HasExpiry: ttl > 0,
+	}
+	if ttl > 0 {
+		item.ExpiresAt = time.Now().Add(ttl)
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
+
+	tmp, err := os.CreateTemp(r.cacheDir, "lock-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	if err := os.Link(tmp.Name(), filename); err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// releaseExpiredLock removes the lock file filename, which was read holding the expired
+// lock data. The file is first renamed aside, which only one process can do, and put back
+// when it turns out to hold a lock acquired since it was read. It reports whether the
+// lock is free, another process having possibly released it first.
+func (r *fileCacheRepository) releaseExpiredLock(filename string, data []byte) (bool, error) {
+	stale := fmt.Sprintf("%s.%s.stale", filename, uuid.NewString())
+	if err := os.Rename(filename, stale); err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer os.Remove(stale)
+
+	current, err := os.ReadFile(stale)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(current, data) {
+		return true, nil
+	}
+	// The lock is held by another process: put it back, unless it was taken meanwhile
+	if err := os.Link(stale, filename); err != nil && !os.IsExist(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+func (r *fileCacheRepository) Unlock(ctx context.Context, key string, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filename := r.keyToFilename(key)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var item cacheItem
+	if err := json.Unmarshal(data, &item); err != nil || item.Value != token {
+		// Held by someone else, or not a lock at all
+		return nil
+	}
+	err = os.Remove(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package file
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -200,3 +201,146 @@ func TestFileCacheRepository_OverwriteValue(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "new-value", val)
 }
+
+func TestFileCacheRepository_DistinctKeysDoNotCollide(t *testing.T) {
+	cacheDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(cacheDir)
+
+	repo, err := NewCacheRepository(cacheDir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Keys sharing a trailing path element must still map to different files
+	require.NoError(t, repo.Set(ctx, "item:owner:alice/1", "alice-value", 0))
+	require.NoError(t, repo.Set(ctx, "item:owner:bob/1", "bob-value", 0))
+
+	val, err := repo.Get(ctx, "item:owner:alice/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice-value", val)
+
+	val, err = repo.Get(ctx, "item:owner:bob/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob-value", val)
+}
+
+func TestFileCacheRepository_Lock(t *testing.T) {
+	cacheDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(cacheDir)
+
+	repo, err := NewCacheRepository(cacheDir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	locked, err := repo.Lock(ctx, "lock-key", "token-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	// Held locks cannot be acquired, nor released without their token
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, locked)
+	require.NoError(t, repo.Unlock(ctx, "lock-key", "token-2"))
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, locked)
+
+	require.NoError(t, repo.Unlock(ctx, "lock-key", "token-1"))
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestFileCacheRepository_LockExpires(t *testing.T) {
+	cacheDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(cacheDir)
+
+	repo, err := NewCacheRepository(cacheDir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	locked, err := repo.Lock(ctx, "lock-key", "token-1", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	time.Sleep(100 * time.Millisecond)
+
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	// The expired holder does not release the lock of the next one
+	require.NoError(t, repo.Unlock(ctx, "lock-key", "token-1"))
+	locked, err = repo.Lock(ctx, "lock-key", "token-3", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, locked)
+}
+
+func TestFileCacheRepository_LockTakenWhileExpired(t *testing.T) {
+	cacheDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(cacheDir)
+
+	repo, err := NewCacheRepository(cacheDir)
+	require.NoError(t, err)
+	r := repo.(*fileCacheRepository)
+
+	ctx := context.Background()
+	filename := r.keyToFilename("lock-key")
+
+	locked, err := repo.Lock(ctx, "lock-key", "token-1", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, locked)
+	time.Sleep(100 * time.Millisecond)
+	expired, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	// Another process replaces the expired lock after it was read
+	require.NoError(t, os.Remove(filename))
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	released, err := r.releaseExpiredLock(filename, expired)
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	// The lock of the other process is kept
+	locked, err = repo.Lock(ctx, "lock-key", "token-3", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, locked)
+	value, err := repo.Get(ctx, "lock-key")
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", value)
+}
+
+func TestFileCacheRepository_LockConcurrently(t *testing.T) {
+	cacheDir := setupTestCacheDir(t)
+	defer cleanupTestCacheDir(cacheDir)
+
+	// Separate repositories share the directory like separate processes would
+	ctx := context.Background()
+	results := make(chan bool, 10)
+	for i := 0; i < 10; i++ {
+		repo, err := NewCacheRepository(cacheDir)
+		require.NoError(t, err)
+		go func(token string) {
+			locked, err := repo.Lock(ctx, "lock-key", token, time.Minute)
+			results <- err == nil && locked
+		}(fmt.Sprintf("token-%d", i))
+	}
+
+	acquired := 0
+	for i := 0; i < 10; i++ {
+		if <-results {
+			acquired++
+		}
+	}
+	assert.Equal(t, 1, acquired)
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	var apiKeys []*domain.APIKey
+	if err := conn(ctx, r.db).Scopes(inTenant(ctx, "api_keys")).Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	var apiKey domain.APIKey
+	if err := conn(ctx, r.db).Scopes(inTenant(ctx, "api_keys")).First(&apiKey, "api_keys.id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &apiKey, nil
+}
+
+// GetByPrefix is not tenant-scoped: it authenticates keys before the tenant of the request is known.
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	var apiKey domain.APIKey
+	if err := conn(ctx, r.db).First(&apiKey, "prefix = ?", prefix).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &apiKey, nil
+}
+
+// Create stores a key in the tenant of ctx.
+func (r *apiKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) error {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	apiKey.TenantID = tenantID
+	return translateError(conn(ctx, r.db).Create(apiKey).Error)
+}
+
+// Update saves a key, reporting a missing one as domain.ErrNotFound.
+func (r *apiKeyRepository) Update(ctx context.Context, apiKey *domain.APIKey) error {
+	return affected(conn(ctx, r.db).Model(apiKey).Select("*").Updates(apiKey))
+}
+
+// UpdateLastUsed only touches the last_used_at column so it never races with rotation or revocation.
+func (r *apiKeyRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	err := conn(ctx, r.db).Model(&domain.APIKey{}).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error
+	return translateError(err)
+}
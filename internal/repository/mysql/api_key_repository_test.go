@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAPIKeyTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+
+	err = db.AutoMigrate(&domain.APIKey{})
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestAPIKeyRepository_CRUD(t *testing.T) {
+	db := setupAPIKeyTestDB(t)
+	repo := NewAPIKeyRepository(db)
+	ctx := adminCtx()
+	id := uuid.New().String()
+
+	// Create
+	apiKey := &domain.APIKey{
+		ID:        id,
+		Prefix:    "0123abcd",
+		KeyHash:   "hash",
+		Owner:     "billing-service",
+		Scopes:    []string{"items:read", "items:write"},
+		CreatedAt: time.Now(),
+	}
+	err := repo.Create(ctx, apiKey)
+	assert.NoError(t, err)
+	assert.Equal(t, testTenant, apiKey.TenantID)
+
+	// GetByID
+	found, err := repo.GetByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "billing-service", found.Owner)
+	assert.Equal(t, []string{"items:read", "items:write"}, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+
+	// GetByPrefix
+	found, err = repo.GetByPrefix(ctx, "0123abcd")
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+
+	_, err = repo.GetByPrefix(ctx, "ffffffff")
+	assert.Error(t, err)
+
+	// GetAll
+	apiKeys, err := repo.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, apiKeys, 1)
+
+	// Update
+	apiKey.Revoked = true
+	err = repo.Update(ctx, apiKey)
+	assert.NoError(t, err)
+
+	updated, _ := repo.GetByID(ctx, id)
+	assert.True(t, updated.Revoked)
+}
+
+func TestAPIKeyRepository_UpdateLastUsed(t *testing.T) {
+	db := setupAPIKeyTestDB(t)
+	repo := NewAPIKeyRepository(db)
+	ctx := adminCtx()
+	id := uuid.New().String()
+
+	apiKey := &domain.APIKey{ID: id, Prefix: "0123abcd", KeyHash: "hash", Owner: "billing-service", CreatedAt: time.Now()}
+	assert.NoError(t, repo.Create(ctx, apiKey))
+
+	usedAt := time.Now().Truncate(time.Second)
+	err := repo.UpdateLastUsed(ctx, id, usedAt)
+	assert.NoError(t, err)
+
+	found, err := repo.GetByID(ctx, id)
+	assert.NoError(t, err)
+	if assert.NotNil(t, found.LastUsedAt) {
+		assert.True(t, usedAt.Equal(*found.LastUsedAt))
+	}
+	// Other columns are left untouched
+	assert.Equal(t, "hash", found.KeyHash)
+	assert.False(t, found.Revoked)
+}
+
+func TestAPIKeyRepository_PrefixIsUnique(t *testing.T) {
+	db := setupAPIKeyTestDB(t)
+	repo := NewAPIKeyRepository(db)
+	ctx := adminCtx()
+
+	first := &domain.APIKey{ID: uuid.New().String(), Prefix: "0123abcd", KeyHash: "a", Owner: "a", CreatedAt: time.Now()}
+	second := &domain.APIKey{ID: uuid.New().String(), Prefix: "0123abcd", KeyHash: "b", Owner: "b", CreatedAt: time.Now()}
+
+	assert.NoError(t, repo.Create(ctx, first))
+	assert.Error(t, repo.Create(ctx, second))
+}
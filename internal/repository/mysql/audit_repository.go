@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// auditEventOrder orders audit events, most recent first.
+var auditEventOrder = []orderBy[*domain.AuditEvent]{{
+	sortColumn: sortColumn[*domain.AuditEvent]{
+		column: "audit_events.created_at",
+		value:  func(event *domain.AuditEvent) *string { return formatTime(event.CreatedAt) },
+		arg:    parseTime,
+	},
+	desc: true,
+}}
+
+// auditFilters returns a scope applying the filters of query to audit events.
+func auditFilters(query domain.AuditQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.ResourceType != "" {
+			db = db.Where("audit_events.resource_type = ?", query.ResourceType)
+		}
+		if query.ResourceID != "" {
+			db = db.Where("audit_events.resource_id = ?", query.ResourceID)
+		}
+		if query.Actor != "" {
+			db = db.Where("audit_events.actor = ?", query.Actor)
+		}
+		if query.Action != "" {
+			db = db.Where("audit_events.action = ?", query.Action)
+		}
+		return db.Scopes(inTimeRange("audit_events.created_at", query.CreatedAt))
+	}
+}
+
+func (r *auditRepository) GetAll(ctx context.Context, query domain.AuditQuery) (*domain.Page[*domain.AuditEvent], error) {
+	db := conn(ctx, r.db).Model(&domain.AuditEvent{}).Scopes(inTenant(ctx, "audit_events"), auditFilters(query))
+	page, err := findPage(db, "audit_events", query.Page, auditEventOrder, func(event *domain.AuditEvent) string { return event.ID })
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return page, nil
+}
+
+// recordAudit appends the event of a change to the audit log in tx, the transaction
+// of the change, so that the change is not made without its event. before and after
+// are the resource as it was before and after the change, nil when it did not exist;
+// only the attributes that differ are recorded. The actor, tenant and request come
+// from ctx, and an update is recorded as the action set by domain.ContextWithAuditAction.
+func recordAudit(ctx context.Context, tx *gorm.DB, action string, resourceType string, resourceID string, before any, after any) error {
+	event, err := newAuditEvent(ctx, action, resourceType, resourceID, before, after)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
+
+// newAuditEvent returns the event of a change, as recordAudit records it.
+func newAuditEvent(ctx context.Context, action string, resourceType string, resourceID string, before any, after any) (*domain.AuditEvent, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if override, ok := domain.AuditActionFromContext(ctx); ok && action == domain.AuditActionUpdate {
+		action = override
+	}
+
+	beforeState, err := auditState(before)
+	if err != nil {
+		return nil, err
+	}
+	afterState, err := auditState(after)
+	if err != nil {
+		return nil, err
+	}
+	beforeState, afterState = auditDiff(beforeState, afterState)
+
+	request, _ := domain.RequestInfoFromContext(ctx)
+	return &domain.AuditEvent{
+		ID:           uuid.NewString(),
+		TenantID:     tenantID,
+		Actor:        principal.Subject,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Before:       beforeState,
+		After:        afterState,
+		RequestID:    request.ID,
+		IP:           request.IP,
+	}, nil
+}
+
+// auditState returns the attributes of a resource as the audit log records them,
+// nil for no resource. Related resources are left out: they have events of their own.
+func auditState(resource any) (map[string]any, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var state map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	delete(state, "item_properties")
+	return state, nil
+}
+
+// auditDiff keeps the attributes whose values differ between two states of a resource.
+// A missing state, before a creation or after a purge, leaves the other one whole.
+func auditDiff(before map[string]any, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore, changedAfter := map[string]any{}, map[string]any{}
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			changedBefore[key] = value
+			changedAfter[key] = nil
+		}
+	}
+	return changedBefore, changedAfter
+}
@@ -0,0 +1,175 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// requestCtx returns ctx carrying the info of a request
+func requestCtx(ctx context.Context, requestID string) context.Context {
+	return domain.ContextWithRequestInfo(ctx, domain.RequestInfo{ID: requestID, IP: "203.0.113.7"})
+}
+
+func TestAuditRepository_RecordsItemChanges(t *testing.T) {
+	db := setupTestDB(t)
+	items := NewItemRepository(db)
+	audit := NewAuditRepository(db)
+	ctx := requestCtx(ownerCtx("user-1"), "req-1")
+
+	id := uuid.NewString()
+	item := &domain.Item{ID: id, Title: "Lamp", Description: "Teak", OwnerID: "user-1"}
+	require.NoError(t, items.Create(ctx, item))
+	item.Title = "Desk lamp"
+	require.NoError(t, items.Update(domain.ContextWithAuditAction(ctx, domain.AuditActionPatch), item))
+	require.NoError(t, items.Delete(ctx, id, 0))
+	require.NoError(t, items.Restore(adminCtx(), id))
+	require.NoError(t, items.Delete(ctx, id, 0))
+	require.NoError(t, items.Purge(adminCtx(), id))
+
+	page, err := audit.GetAll(adminCtx(), domain.AuditQuery{ResourceType: domain.ResourceItems, ResourceID: id, Page: firstPage})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 6)
+	// Most recent first
+	actions := []string{}
+	for _, event := range page.Items {
+		actions = append(actions, event.Action)
+	}
+	assert.Equal(t, []string{"purge", "delete", "restore", "delete", "patch", "create"}, actions)
+
+	created := page.Items[5]
+	assert.Equal(t, "user-1", created.Actor)
+	assert.Equal(t, testTenant, created.TenantID)
+	assert.Equal(t, "req-1", created.RequestID)
+	assert.Equal(t, "203.0.113.7", created.IP)
+	assert.Nil(t, created.Before)
+	assert.Equal(t, "Lamp", created.After["title"])
+	assert.NotContains(t, created.After, "item_properties")
+
+	// Only the changed attributes are recorded
+	patched := page.Items[4]
+	assert.Equal(t, "Lamp", patched.Before["title"])
+	assert.Equal(t, "Desk lamp", patched.After["title"])
+	assert.Equal(t, float64(2), patched.After["version"])
+	assert.NotContains(t, patched.After, "description")
+
+	deleted := page.Items[3]
+	assert.Nil(t, deleted.Before["deleted_at"])
+	assert.NotNil(t, deleted.After["deleted_at"])
+
+	restored := page.Items[2]
+	assert.Equal(t, "admin-1", restored.Actor)
+	assert.NotNil(t, restored.Before["deleted_at"])
+	assert.Nil(t, restored.After["deleted_at"])
+
+	purged := page.Items[0]
+	assert.Equal(t, "Desk lamp", purged.Before["title"])
+	assert.Nil(t, purged.After)
+}
+
+func TestAuditRepository_RecordsItemPropertyChanges(t *testing.T) {
+	db := setupTestDB(t)
+	items := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	audit := NewAuditRepository(db)
+	ctx := ownerCtx("user-1")
+
+	itemID := uuid.NewString()
+	require.NoError(t, items.Create(ctx, &domain.Item{ID: itemID, Title: "Lamp", OwnerID: "user-1"}))
+	id := uuid.NewString()
+	property := &domain.ItemProperty{ID: id, ItemID: itemID, Name: "color", Value: "red"}
+	require.NoError(t, properties.Create(ctx, property))
+	property.Value = "blue"
+	require.NoError(t, properties.Update(ctx, property))
+	require.NoError(t, properties.Delete(ctx, itemID, id, 0))
+
+	page, err := audit.GetAll(adminCtx(), domain.AuditQuery{ResourceType: domain.ResourceItemProperties, Page: firstPage})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 3)
+	assert.Equal(t, "delete", page.Items[0].Action)
+	assert.Equal(t, map[string]any{"value": "red", "version": float64(1)}, page.Items[1].Before)
+	assert.Equal(t, map[string]any{"value": "blue", "version": float64(2)}, page.Items[1].After)
+	assert.Equal(t, "create", page.Items[2].Action)
+	assert.Equal(t, id, page.Items[2].ResourceID)
+}
+
+func TestAuditRepository_GetAll_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	items := NewItemRepository(db)
+	audit := NewAuditRepository(db)
+
+	first := uuid.NewString()
+	require.NoError(t, items.Create(ownerCtx("user-1"), &domain.Item{ID: first, Title: "Lamp", OwnerID: "user-1"}))
+	require.NoError(t, items.Create(ownerCtx("user-2"), &domain.Item{ID: uuid.NewString(), Title: "Desk", OwnerID: "user-2"}))
+	require.NoError(t, items.Delete(ownerCtx("user-1"), first, 0))
+	// Events of other tenants are never listed
+	require.NoError(t, items.Create(tenantCtx("globex", "user-1"), &domain.Item{ID: uuid.NewString(), Title: "Chair", OwnerID: "user-1"}))
+
+	tests := []struct {
+		name  string
+		query domain.AuditQuery
+		count int
+	}{
+		{"all", domain.AuditQuery{}, 3},
+		{"resource type", domain.AuditQuery{ResourceType: domain.ResourceItems}, 3},
+		{"resource", domain.AuditQuery{ResourceType: domain.ResourceItems, ResourceID: first}, 2},
+		{"actor", domain.AuditQuery{Actor: "user-2"}, 1},
+		{"action", domain.AuditQuery{Action: domain.AuditActionDelete}, 1},
+		{"other resource type", domain.AuditQuery{ResourceType: domain.ResourceItemProperties}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Page = firstPage
+			page, err := audit.GetAll(adminCtx(), tt.query)
+			require.NoError(t, err)
+			assert.Len(t, page.Items, tt.count)
+			assert.Equal(t, int64(tt.count), page.Total)
+		})
+	}
+}
+
+func TestAuditRepository_GetAll_CursorPagination(t *testing.T) {
+	db := setupTestDB(t)
+	items := NewItemRepository(db)
+	audit := NewAuditRepository(db)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, items.Create(ownerCtx("user-1"), &domain.Item{ID: uuid.NewString(), Title: "Lamp", OwnerID: "user-1"}))
+	}
+
+	seen := map[string]bool{}
+	page := domain.PageRequest{Size: 2}
+	for {
+		result, err := audit.GetAll(adminCtx(), domain.AuditQuery{Page: page})
+		require.NoError(t, err)
+		for _, event := range result.Items {
+			assert.False(t, seen[event.ID], "event %s listed twice", event.ID)
+			seen[event.ID] = true
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+	assert.Len(t, seen, 5)
+}
+
+func TestAuditRepository_ChangeFailsWithoutItsEvent(t *testing.T) {
+	// Without the audit log table the event cannot be written, so the change is rolled back
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Item{}, &domain.ItemProperty{}, &domain.ItemRevision{}))
+	items := NewItemRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	assert.Error(t, items.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}))
+
+	_, err = items.GetByID(ctx, id, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// translateError maps a database error to the domain errors, keeping the original error in the chain:
+// missing rows are domain.ErrNotFound, duplicate keys and foreign key violations domain.ErrConflict,
+// and timeouts and broken connections domain.ErrUnavailable. Other errors are returned as is.
+// Duplicate keys and foreign key violations are only recognised when the *gorm.DB translates
+// dialect errors (gorm.Config.TranslateError).
+func translateError(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrPreconditionFailed), errors.Is(err, domain.ErrUnavailable):
+		// Already translated
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey), errors.Is(err, gorm.ErrForeignKeyViolated):
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+	return err
+}
+
+// affected reports a write that matched no row as a missing row, see translateError.
+func affected(result *gorm.DB) error {
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// affectedAt is affected for a write restricted to a version of the row (see atVersion).
+// When the write matched no row but the row is found by current, the row is at another
+// version and the write is reported as domain.ErrPreconditionFailed. The write itself is
+// conditional; current is only read to tell why it matched nothing.
+func affectedAt(result *gorm.DB, version int64, current *gorm.DB) error {
+	err := affected(result)
+	if version == 0 || !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	var count int64
+	if err := current.Count(&count).Error; err != nil {
+		return translateError(err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: version %d is not the current version", domain.ErrPreconditionFailed, version)
+	}
+	return err
+}
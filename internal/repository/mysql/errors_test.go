@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"missing row", gorm.ErrRecordNotFound, domain.ErrNotFound},
+		{"duplicate key", gorm.ErrDuplicatedKey, domain.ErrConflict},
+		{"foreign key", gorm.ErrForeignKeyViolated, domain.ErrConflict},
+		{"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), domain.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+			assert.ErrorIs(t, err, tt.want)
+			// The database error stays in the chain
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// Translated errors are not wrapped again
+	translated := translateError(gorm.ErrRecordNotFound)
+	assert.Equal(t, translated, translateError(translated))
+
+	other := errors.New("syntax error")
+	assert.Equal(t, other, translateError(other))
+	assert.NoError(t, translateError(nil))
+}
+
+func TestItemRepository_UpdateAndDeleteMissing(t *testing.T) {
+	repo := NewItemRepository(setupTestDB(t))
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Item{ID: id, Title: "Missing"}), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, id, 0), domain.ErrNotFound)
+
+	// A deleted item is not found the second time
+	require.NoError(t, repo.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "alice"}))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	assert.ErrorIs(t, repo.Delete(ctx, id, 0), domain.ErrNotFound)
+}
+
+func TestItemPropertyRepository_UpdateAndDeleteMissing(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemID := createItem(t, NewItemRepository(db), "Lamp", nil)
+	repo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+
+	assert.ErrorIs(t, repo.Update(ctx, &domain.ItemProperty{ID: id, ItemID: itemID, Name: "color", Value: "red"}), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, itemID, id, 0), domain.ErrNotFound)
+}
+
+func TestAPIKeyRepository_Conflict(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.APIKey{}))
+	repo := NewAPIKeyRepository(db)
+	ctx := ownerCtx("admin-1")
+
+	require.NoError(t, repo.Create(ctx, &domain.APIKey{ID: uuid.New().String(), Prefix: "abcd1234", Owner: "alice"}))
+	err = repo.Create(ctx, &domain.APIKey{ID: uuid.New().String(), Prefix: "abcd1234", Owner: "bob"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	assert.ErrorIs(t, repo.Update(ctx, &domain.APIKey{ID: uuid.New().String(), Prefix: "efgh5678"}), domain.ErrNotFound)
+}
@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"slices"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// itemFieldColumns maps the fields of items to the columns they are loaded from.
+// Relationships have no column.
+var itemFieldColumns = map[string]string{
+	"title":       "items.title",
+	"description": "items.description",
+	"owner_id":    "items.owner_id",
+	"created_at":  "items.created_at",
+	"updated_at":  "items.updated_at",
+}
+
+// itemPropertyFieldColumns maps the fields of item properties to the columns they are loaded from.
+var itemPropertyFieldColumns = map[string]string{
+	"item_id": "item_properties.item_id",
+	"name":    "item_properties.name",
+	"value":   "item_properties.value",
+}
+
+// fieldColumns returns the columns to load for the sparse fieldset of
+// resourceType in opts: the required columns and those of the fields in the
+// fieldset. It returns nil, meaning every column, without a fieldset.
+func fieldColumns(opts domain.QueryOptions, resourceType string, fieldColumns map[string]string, required ...string) []string {
+	fields, ok := opts.Fieldset(resourceType)
+	if !ok {
+		return nil
+	}
+	columns := append([]string{}, required...)
+	for _, field := range fields {
+		if column, ok := fieldColumns[field]; ok && !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// withItemOptions loads the columns of items that opts asks for, along with
+// the id, the version and update time (the validators of the item) and the
+// required columns, and preloads the related resources opts includes.
+func withItemOptions(db *gorm.DB, opts domain.QueryOptions, required ...string) *gorm.DB {
+	if columns := fieldColumns(opts, domain.ResourceItems, itemFieldColumns, append([]string{"items.id", "items.version", "items.updated_at"}, required...)...); columns != nil {
+		db = db.Select(columns)
+	}
+	if opts.Includes(domain.ItemIncludeProperties) {
+		// Properties are matched to their item by item_id
+		columns := fieldColumns(opts, domain.ResourceItemProperties, itemPropertyFieldColumns, "item_properties.id", "item_properties.item_id")
+		db = db.Preload("ItemProperties", func(tx *gorm.DB) *gorm.DB {
+			if columns != nil {
+				return tx.Select(columns)
+			}
+			return tx
+		})
+	}
+	return db
+}
+
+// withItemPropertyOptions loads the columns of item properties that opts asks for, along with the id and version.
+func withItemPropertyOptions(db *gorm.DB, opts domain.QueryOptions) *gorm.DB {
+	if columns := fieldColumns(opts, domain.ResourceItemProperties, itemPropertyFieldColumns, "item_properties.id", "item_properties.version"); columns != nil {
+		db = db.Select(columns)
+	}
+	return db
+}
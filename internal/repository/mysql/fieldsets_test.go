@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemRepository_GetByID_SparseFieldset(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	id := uuid.New().String()
+	require.NoError(t, repo.Create(ownerCtx("alice"), &domain.Item{ID: id, Title: "Lamp", Description: "Teak lamp", OwnerID: "alice"}))
+
+	item, err := repo.GetByID(ownerCtx("alice"), id, domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"title"}}})
+	require.NoError(t, err)
+	assert.Equal(t, id, item.ID)
+	assert.Equal(t, "Lamp", item.Title)
+	// Columns outside the fieldset are not loaded
+	assert.Empty(t, item.Description)
+	assert.Empty(t, item.OwnerID)
+}
+
+func TestItemRepository_GetByID_IncludeProperties(t *testing.T) {
+	db := setupItemQueryDB(t)
+	repo := NewItemRepository(db)
+	propertyRepo := NewItemPropertyRepository(db)
+	id := createItem(t, repo, "Lamp", nil)
+	require.NoError(t, propertyRepo.Create(ownerCtx("alice"), &domain.ItemProperty{ID: uuid.New().String(), ItemID: id, Name: "color", Value: "red"}))
+
+	item, err := repo.GetByID(ownerCtx("alice"), id, domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, item.ItemProperties)
+
+	item, err = repo.GetByID(ownerCtx("alice"), id, domain.QueryOptions{
+		Include: []string{domain.ItemIncludeProperties},
+		Fields:  map[string][]string{domain.ResourceItemProperties: {"name"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Lamp", item.Title)
+	if assert.Len(t, item.ItemProperties, 1) {
+		assert.Equal(t, "color", item.ItemProperties[0].Name)
+		assert.Empty(t, item.ItemProperties[0].Value)
+	}
+}
+
+func TestItemRepository_GetAll_SparseFieldsetKeepsSortColumns(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	for _, title := range []string{"C", "A", "B"} {
+		createItem(t, repo, title, nil)
+	}
+
+	// Cursors are built from the sort columns, which are loaded even outside the fieldset
+	query := domain.ItemQuery{
+		Sort:    []domain.SortField{{Field: domain.ItemSortTitle}},
+		Page:    domain.PageRequest{Size: 2},
+		Options: domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"description"}}},
+	}
+	page, err := repo.GetAll(ownerCtx("alice"), query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, titles(page))
+	require.NotEmpty(t, page.NextCursor)
+
+	query.Page.Cursor = page.NextCursor
+	page, err = repo.GetAll(ownerCtx("alice"), query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C"}, titles(page))
+}
+
+func TestItemPropertyRepository_SparseFieldset(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemID := createItem(t, NewItemRepository(db), "Lamp", nil)
+	propertyRepo := NewItemPropertyRepository(db)
+	propertyID := uuid.New().String()
+	require.NoError(t, propertyRepo.Create(ownerCtx("alice"), &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}))
+	opts := domain.QueryOptions{Fields: map[string][]string{domain.ResourceItemProperties: {"value"}}}
+
+	property, err := propertyRepo.GetByID(ownerCtx("alice"), itemID, propertyID, opts)
+	require.NoError(t, err)
+	assert.Equal(t, propertyID, property.ID)
+	assert.Equal(t, "red", property.Value)
+	assert.Empty(t, property.Name)
+
+	properties, err := propertyRepo.GetAllByItemID(ownerCtx("alice"), itemID, firstPage, opts)
+	require.NoError(t, err)
+	if assert.Len(t, properties.Items, 1) {
+		assert.Equal(t, "red", properties.Items[0].Value)
+		assert.Empty(t, properties.Items[0].Name)
+	}
+}
@@ -0,0 +1,207 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bulkBatchSize is the number of rows inserted by each statement of a bulk write.
+const bulkBatchSize = 100
+
+// CreateBatch stores items in the tenant of ctx, at version 1, with batched inserts,
+// and records their revisions and creations in the audit log in the same transaction.
+// Their properties are not stored.
+func (r *itemRepository) CreateBatch(ctx context.Context, items []*domain.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		item.TenantID = tenantID
+		item.Version = 1
+		ids[i] = item.ID
+	}
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).CreateInBatches(items, bulkBatchSize).Error; err != nil {
+			return err
+		}
+		if err := recordRevisions(tx, ids); err != nil {
+			return err
+		}
+		return recordBulkAudit(ctx, tx, domain.AuditActionCreate, make([]*domain.Item, len(items)), items)
+	})
+	return translateError(err)
+}
+
+// UpdateBatch saves the title and description of the items that can be written (see
+// lockItems) and increments their versions with batched upserts of the rows read, which
+// are locked until the transaction ends. The stored items are then read back into items,
+// and the changes are recorded like those of Update.
+func (r *itemRepository) UpdateBatch(ctx context.Context, items []*domain.Item) ([]error, error) {
+	errs := make([]error, len(items))
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current, err := lockItems(ctx, tx, items, errs)
+		if err != nil || len(current) == 0 {
+			return err
+		}
+
+		now := tx.NowFunc()
+		rows := make([]*domain.Item, 0, len(current))
+		for i, item := range items {
+			if errs[i] != nil {
+				continue
+			}
+			row := *current[item.ID]
+			row.Title = item.Title
+			row.Description = item.Description
+			row.Version++
+			row.UpdatedAt = now
+			rows = append(rows, &row)
+		}
+		// Every row exists, so the upserts only ever update
+		upsert := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "version", "updated_at"}),
+		}
+		if err := tx.Clauses(upsert).Omit(clause.Associations).CreateInBatches(rows, bulkBatchSize).Error; err != nil {
+			return err
+		}
+		return finishBatch(ctx, tx, domain.AuditActionUpdate, visibleItems(ctx), items, errs, current)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return errs, nil
+}
+
+// DeleteBatch soft-deletes the items that can be written (see lockItems) along with their
+// properties, all with the same deletion time, like Delete does for a single item. The
+// deleted items are then read back into items, and the deletions recorded like Delete's.
+func (r *itemRepository) DeleteBatch(ctx context.Context, items []*domain.Item) ([]error, error) {
+	errs := make([]error, len(items))
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current, err := lockItems(ctx, tx, items, errs)
+		if err != nil || len(current) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(current))
+		for id := range current {
+			ids = append(ids, id)
+		}
+		deletedAt := tx.NowFunc()
+		if err := tx.Model(&domain.Item{}).Where("items.id IN ?", ids).Update("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.ItemProperty{}).Where("item_id IN ?", ids).Update("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		return finishBatch(ctx, tx, domain.AuditActionDelete, deletedItems(ctx), items, errs, current)
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return errs, nil
+}
+
+// lockItems reads and locks the stored items visible to the caller among those identified
+// by items, by ID, and sets the error of each item that cannot be written in errs: one that
+// is not visible is domain.ErrNotFound, one not at its Version, when set, is
+// domain.ErrPreconditionFailed, and one given again is domain.ErrConflict. It returns the
+// stored items that can be written, by ID.
+func lockItems(ctx context.Context, tx *gorm.DB, items []*domain.Item, errs []error) (map[string]*domain.Item, error) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	var stored []*domain.Item
+	if err := tx.Scopes(visibleItems(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("items.id IN ?", ids).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Item, len(stored))
+	for _, item := range stored {
+		byID[item.ID] = item
+	}
+
+	current := make(map[string]*domain.Item, len(stored))
+	for i, item := range items {
+		before, ok := byID[item.ID]
+		switch {
+		case current[item.ID] != nil:
+			errs[i] = fmt.Errorf("%w: item %s is given more than once", domain.ErrConflict, item.ID)
+		case !ok:
+			errs[i] = fmt.Errorf("%w: item %s", domain.ErrNotFound, item.ID)
+		case item.Version != 0 && item.Version != before.Version:
+			errs[i] = fmt.Errorf("%w: item %s is at version %d", domain.ErrPreconditionFailed, item.ID, before.Version)
+		default:
+			current[item.ID] = before
+		}
+	}
+	return current, nil
+}
+
+// finishBatch reads the items written by a bulk change, those with no error in errs, back
+// into items from the rows scope selects, and records their revisions and the change in
+// the audit log; current holds the stored items before the change, by ID.
+func finishBatch(ctx context.Context, tx *gorm.DB, action string, scope func(*gorm.DB) *gorm.DB, items []*domain.Item, errs []error, current map[string]*domain.Item) error {
+	ids := make([]string, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	var stored []*domain.Item
+	if err := tx.Scopes(scope).Where("items.id IN ?", ids).Find(&stored).Error; err != nil {
+		return err
+	}
+	after := make(map[string]*domain.Item, len(stored))
+	for _, item := range stored {
+		after[item.ID] = item
+	}
+
+	befores := make([]*domain.Item, 0, len(ids))
+	afters := make([]*domain.Item, 0, len(ids))
+	for i, item := range items {
+		if errs[i] != nil {
+			continue
+		}
+		stored, ok := after[item.ID]
+		if !ok {
+			return fmt.Errorf("item %s was not written", item.ID)
+		}
+		*item = *stored
+		befores = append(befores, current[item.ID])
+		afters = append(afters, item)
+	}
+	if err := recordRevisions(tx, ids); err != nil {
+		return err
+	}
+	return recordBulkAudit(ctx, tx, action, befores, afters)
+}
+
+// recordBulkAudit appends the events of a bulk change to the audit log in tx with batched
+// inserts, one event per item, recorded like recordAudit does. befores and afters hold the
+// items before and after the change, at the same indexes; a nil item did not exist.
+func recordBulkAudit(ctx context.Context, tx *gorm.DB, action string, befores []*domain.Item, afters []*domain.Item) error {
+	events := make([]*domain.AuditEvent, len(afters))
+	for i, after := range afters {
+		// A nil *domain.Item would not be a nil resource
+		var before any
+		if befores[i] != nil {
+			before = befores[i]
+		}
+		event, err := newAuditEvent(ctx, action, domain.ResourceItems, after.ID, before, after)
+		if err != nil {
+			return err
+		}
+		events[i] = event
+	}
+	return tx.CreateInBatches(events, bulkBatchSize).Error
+}
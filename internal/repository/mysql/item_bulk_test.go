@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createItems stores n items of owner in the test tenant and returns them
+func createItems(t *testing.T, repo domain.ItemRepository, owner string, n int) []*domain.Item {
+	t.Helper()
+	items := make([]*domain.Item, n)
+	for i := range items {
+		items[i] = &domain.Item{ID: uuid.NewString(), Title: "Lamp", OwnerID: owner}
+	}
+	require.NoError(t, repo.CreateBatch(ownerCtx(owner), items))
+	return items
+}
+
+// auditActions returns the actions recorded in the audit log for an item, most recent first
+func auditActions(t *testing.T, db *gorm.DB, id string) []string {
+	t.Helper()
+	page, err := NewAuditRepository(db).GetAll(adminCtx(), domain.AuditQuery{ResourceType: domain.ResourceItems, ResourceID: id, Page: firstPage})
+	require.NoError(t, err)
+	actions := []string{}
+	for _, event := range page.Items {
+		actions = append(actions, event.Action)
+	}
+	return actions
+}
+
+func TestItemRepository_CreateBatch(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+
+	// More items than a batch holds
+	items := createItems(t, repo, "user-1", bulkBatchSize+5)
+
+	page, err := repo.GetAll(ownerCtx("user-1"), domain.ItemQuery{Page: domain.PageRequest{Number: 1, Size: 1000}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(items)), page.Total)
+	for _, item := range items {
+		assert.Equal(t, testTenant, item.TenantID)
+		assert.Equal(t, int64(1), item.Version)
+	}
+
+	last := items[len(items)-1]
+	revisions, err := repo.GetRevisions(ownerCtx("user-1"), last.ID, firstPage)
+	require.NoError(t, err)
+	require.Len(t, revisions.Items, 1)
+	assert.Equal(t, "Lamp", revisions.Items[0].Item.Title)
+	assert.Equal(t, []string{"create"}, auditActions(t, db, last.ID))
+}
+
+func TestItemRepository_UpdateBatch(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("user-1")
+	stored := createItems(t, repo, "user-1", 3)
+	others := createItems(t, repo, "user-2", 1)
+
+	items := []*domain.Item{
+		{ID: stored[0].ID, Title: "Desk lamp", Description: "Brass"},
+		{ID: stored[1].ID, Title: "Floor lamp", Version: 1},
+		{ID: stored[2].ID, Title: "Stale", Version: 7},
+		{ID: others[0].ID, Title: "Not mine"},
+		{ID: uuid.NewString(), Title: "Missing"},
+		{ID: stored[0].ID, Title: "Again"},
+	}
+	errs, err := repo.UpdateBatch(ctx, items)
+	require.NoError(t, err)
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], domain.ErrPreconditionFailed)
+	assert.ErrorIs(t, errs[3], domain.ErrNotFound)
+	assert.ErrorIs(t, errs[4], domain.ErrNotFound)
+	assert.ErrorIs(t, errs[5], domain.ErrConflict)
+
+	// The stored state is read back into the written items
+	assert.Equal(t, int64(2), items[0].Version)
+	assert.Equal(t, "user-1", items[0].OwnerID)
+	assert.Equal(t, testTenant, items[0].TenantID)
+
+	updated, err := repo.GetByID(ctx, stored[0].ID, domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Desk lamp", updated.Title)
+	assert.Equal(t, "Brass", updated.Description)
+	assert.Equal(t, int64(2), updated.Version)
+
+	unchanged, err := repo.GetByID(ctx, stored[2].ID, domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Lamp", unchanged.Title)
+	assert.Equal(t, int64(1), unchanged.Version)
+
+	revisions, err := repo.GetRevisions(ctx, stored[1].ID, firstPage)
+	require.NoError(t, err)
+	require.Len(t, revisions.Items, 2)
+	assert.Equal(t, "Floor lamp", revisions.Items[0].Item.Title)
+	assert.Equal(t, []string{"update", "create"}, auditActions(t, db, stored[1].ID))
+	assert.Equal(t, []string{"create"}, auditActions(t, db, stored[2].ID))
+}
+
+func TestItemRepository_DeleteBatch(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	ctx := ownerCtx("user-1")
+	stored := createItems(t, repo, "user-1", 2)
+	require.NoError(t, properties.Create(ctx, &domain.ItemProperty{ID: uuid.NewString(), ItemID: stored[0].ID, Name: "color", Value: "red"}))
+
+	items := []*domain.Item{{ID: stored[0].ID}, {ID: stored[1].ID, Version: 3}}
+	errs, err := repo.DeleteBatch(ctx, items)
+	require.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], domain.ErrPreconditionFailed)
+
+	// The deleted item is read back, with its owner for cache invalidation
+	assert.Equal(t, "user-1", items[0].OwnerID)
+	assert.True(t, items[0].DeletedAt.Valid)
+
+	_, err = repo.GetByID(ctx, stored[0].ID, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetByID(ctx, stored[1].ID, domain.QueryOptions{})
+	assert.NoError(t, err)
+
+	// The properties are deleted with the item, and come back with it
+	page, err := properties.GetAllByItemID(ctx, stored[0].ID, firstPage, domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	require.NoError(t, repo.Restore(adminCtx(), stored[0].ID))
+	page, err = properties.GetAllByItemID(ctx, stored[0].ID, firstPage, domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	assert.Equal(t, []string{"restore", "delete", "create"}, auditActions(t, db, stored[0].ID))
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Attach moves the properties identified by ids to an item visible to the caller, from
+// whichever visible items they belong to, incrementing the version of each property moved.
+// It records the revisions of the items that changed and the moves in the audit log, as
+// updates of the item_id of the properties.
+func (r *itemPropertyRepository) Attach(ctx context.Context, itemID string, ids []string) ([]*domain.Item, error) {
+	var sources []*domain.Item
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		sources, err = attachItemProperties(ctx, tx, itemID, ids, false)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return sources, nil
+}
+
+// Replace attaches the properties identified by ids to an item visible to the caller like
+// Attach, and soft-deletes the other properties of the item, all at the same time.
+func (r *itemPropertyRepository) Replace(ctx context.Context, itemID string, ids []string) ([]*domain.Item, error) {
+	var sources []*domain.Item
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var err error
+		sources, err = attachItemProperties(ctx, tx, itemID, ids, true)
+		return err
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return sources, nil
+}
+
+// Detach soft-deletes the properties among ids of an item visible to the caller and
+// records their deletion like Delete does. The IDs of other properties are ignored.
+func (r *itemPropertyRepository) Detach(ctx context.Context, itemID string, ids []string) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockItem(ctx, tx, itemID); err != nil {
+			return err
+		}
+		var detached []*domain.ItemProperty
+		if err := tx.Scopes(visibleItemProperties(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("item_id = ? AND item_properties.id IN ?", itemID, ids).Find(&detached).Error; err != nil {
+			return err
+		}
+		if len(detached) == 0 {
+			return nil
+		}
+		return deleteItemProperties(ctx, tx, itemID, detached)
+	})
+	return translateError(err)
+}
+
+// attachItemProperties moves the properties identified by ids to the item, as Attach does,
+// and with replace deletes the other properties of the item. It returns the items the
+// properties were moved from.
+func attachItemProperties(ctx context.Context, tx *gorm.DB, itemID string, ids []string, replace bool) ([]*domain.Item, error) {
+	if err := lockItem(ctx, tx, itemID); err != nil {
+		return nil, err
+	}
+
+	var attached []*domain.ItemProperty
+	if len(ids) > 0 {
+		if err := tx.Scopes(visibleItemProperties(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("item_properties.id IN ?", ids).Find(&attached).Error; err != nil {
+			return nil, err
+		}
+	}
+	found := make(map[string]bool, len(attached))
+	for _, property := range attached {
+		found[property.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("%w: item property %s", domain.ErrNotFound, id)
+		}
+	}
+
+	var moved []*domain.ItemProperty
+	var sourceIDs []string
+	for _, property := range attached {
+		if property.ItemID == itemID {
+			continue
+		}
+		moved = append(moved, property)
+		if !slices.Contains(sourceIDs, property.ItemID) {
+			sourceIDs = append(sourceIDs, property.ItemID)
+		}
+	}
+	var sources []*domain.Item
+	if len(moved) > 0 {
+		movedIDs := make([]string, len(moved))
+		for i, property := range moved {
+			movedIDs[i] = property.ID
+		}
+		if err := tx.Find(&sources, "items.id IN ?", sourceIDs).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&domain.ItemProperty{}).Where("id IN ?", movedIDs).Updates(map[string]interface{}{
+			"item_id": itemID,
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return nil, err
+		}
+		var after []*domain.ItemProperty
+		if err := tx.Where("id IN ?", movedIDs).Find(&after).Error; err != nil {
+			return nil, err
+		}
+		afterByID := make(map[string]*domain.ItemProperty, len(after))
+		for _, property := range after {
+			afterByID[property.ID] = property
+		}
+		for _, before := range moved {
+			if err := recordAudit(ctx, tx, domain.AuditActionUpdate, domain.ResourceItemProperties, before.ID, before, afterByID[before.ID]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var removed []*domain.ItemProperty
+	if replace {
+		others := tx.Scopes(visibleItemProperties(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).Where("item_id = ?", itemID)
+		if len(ids) > 0 {
+			others = others.Where("item_properties.id NOT IN ?", ids)
+		}
+		if err := others.Find(&removed).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if len(removed) > 0 {
+		// deleteItemProperties records the revisions of the item and of the sources
+		if err := deleteItemProperties(ctx, tx, itemID, removed, sourceIDs...); err != nil {
+			return nil, err
+		}
+	} else if len(moved) > 0 {
+		if err := recordRevisions(tx, append([]string{itemID}, sourceIDs...)); err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// deleteItemProperties soft-deletes properties of the item, all with the same deletion
+// time, records their deletion in the audit log and the revisions of the item and of the
+// other items identified by changed.
+func deleteItemProperties(ctx context.Context, tx *gorm.DB, itemID string, properties []*domain.ItemProperty, changed ...string) error {
+	ids := make([]string, len(properties))
+	for i, property := range properties {
+		ids[i] = property.ID
+	}
+	if err := tx.Model(&domain.ItemProperty{}).Where("id IN ?", ids).Update("deleted_at", tx.NowFunc()).Error; err != nil {
+		return err
+	}
+	var after []*domain.ItemProperty
+	if err := tx.Unscoped().Where("id IN ?", ids).Find(&after).Error; err != nil {
+		return err
+	}
+	afterByID := make(map[string]*domain.ItemProperty, len(after))
+	for _, property := range after {
+		afterByID[property.ID] = property
+	}
+	for _, before := range properties {
+		if err := recordAudit(ctx, tx, domain.AuditActionDelete, domain.ResourceItemProperties, before.ID, before, afterByID[before.ID]); err != nil {
+			return err
+		}
+	}
+	return recordRevisions(tx, append([]string{itemID}, changed...))
+}
+
+// lockItem locks an item visible to the caller for the rest of tx, so that concurrent
+// changes to its properties are serialized.
+func lockItem(ctx context.Context, tx *gorm.DB, itemID string) error {
+	return tx.Scopes(visibleItems(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("items.id").First(&domain.Item{}, "items.id = ?", itemID).Error
+}
@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createProperty adds a property to an item of alice and returns its ID
+func createProperty(t *testing.T, repo domain.ItemPropertyRepository, itemID string, name string) string {
+	t.Helper()
+	id := uuid.NewString()
+	require.NoError(t, repo.Create(ownerCtx("alice"), &domain.ItemProperty{ID: id, ItemID: itemID, Name: name, Value: "v"}))
+	return id
+}
+
+// propertyNames returns the names of the properties of an item visible to alice
+func propertyNames(t *testing.T, repo domain.ItemPropertyRepository, itemID string) []string {
+	t.Helper()
+	page, err := repo.GetAllByItemID(ownerCtx("alice"), itemID, firstPage, domain.QueryOptions{})
+	require.NoError(t, err)
+	names := []string{}
+	for _, property := range page.Items {
+		names = append(names, property.Name)
+	}
+	return names
+}
+
+// revisionCount returns the number of revisions stored of an item
+func revisionCount(t *testing.T, db *gorm.DB, itemID string) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&domain.ItemRevision{}).Where("item_id = ?", itemID).Count(&count).Error)
+	return count
+}
+
+func TestItemPropertyRepository_Attach(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	repo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+
+	lamp := createItem(t, itemRepo, "Lamp", nil)
+	desk := createItem(t, itemRepo, "Desk", nil)
+	color := createProperty(t, repo, lamp, "color")
+	size := createProperty(t, repo, desk, "size")
+	lampRevisions, deskRevisions := revisionCount(t, db, lamp), revisionCount(t, db, desk)
+
+	sources, err := repo.Attach(ctx, lamp, []string{color, size})
+	require.NoError(t, err)
+
+	// Only the property of the desk moved, from the desk
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, desk, sources[0].ID)
+	}
+	assert.ElementsMatch(t, []string{"color", "size"}, propertyNames(t, repo, lamp))
+	assert.Empty(t, propertyNames(t, repo, desk))
+	moved, err := repo.Find(ctx, size)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), moved.Version)
+	assert.Equal(t, lampRevisions+1, revisionCount(t, db, lamp))
+	assert.Equal(t, deskRevisions+1, revisionCount(t, db, desk))
+
+	events, err := NewAuditRepository(db).GetAll(adminCtx(), domain.AuditQuery{ResourceType: domain.ResourceItemProperties, ResourceID: size, Page: firstPage})
+	require.NoError(t, err)
+	if assert.Len(t, events.Items, 2) {
+		assert.Equal(t, domain.AuditActionUpdate, events.Items[0].Action)
+		assert.Equal(t, map[string]any{"item_id": desk, "version": float64(1)}, events.Items[0].Before)
+	}
+
+	// Attaching the properties of the item changes nothing
+	sources, err = repo.Attach(ctx, lamp, []string{color})
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+	assert.Equal(t, lampRevisions+1, revisionCount(t, db, lamp))
+}
+
+func TestItemPropertyRepository_Attach_NotFound(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	repo := NewItemPropertyRepository(db)
+
+	lamp := createItem(t, itemRepo, "Lamp", nil)
+	desk := createItem(t, itemRepo, "Desk", nil)
+	size := createProperty(t, repo, desk, "size")
+
+	// A missing property fails the whole change
+	_, err := repo.Attach(ownerCtx("alice"), lamp, []string{size, uuid.NewString()})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, []string{"size"}, propertyNames(t, repo, desk))
+
+	// As do the properties and items of others
+	_, err = repo.Attach(ownerCtx("bob"), lamp, []string{size})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	bobs := uuid.NewString()
+	require.NoError(t, itemRepo.Create(ownerCtx("bob"), &domain.Item{ID: bobs, Title: "Chair", OwnerID: "bob"}))
+	_, err = repo.Attach(ownerCtx("bob"), bobs, []string{size})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Equal(t, []string{"size"}, propertyNames(t, repo, desk))
+}
+
+func TestItemPropertyRepository_Replace(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	repo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+
+	lamp := createItem(t, itemRepo, "Lamp", nil)
+	desk := createItem(t, itemRepo, "Desk", nil)
+	color := createProperty(t, repo, lamp, "color")
+	createProperty(t, repo, lamp, "weight")
+	size := createProperty(t, repo, desk, "size")
+	lampRevisions := revisionCount(t, db, lamp)
+
+	sources, err := repo.Replace(ctx, lamp, []string{color, size})
+	require.NoError(t, err)
+
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, desk, sources[0].ID)
+	}
+	assert.ElementsMatch(t, []string{"color", "size"}, propertyNames(t, repo, lamp))
+	assert.Empty(t, propertyNames(t, repo, desk))
+	// One revision for the whole change
+	assert.Equal(t, lampRevisions+1, revisionCount(t, db, lamp))
+
+	// An empty linkage deletes every property
+	_, err = repo.Replace(ctx, lamp, []string{})
+	require.NoError(t, err)
+	assert.Empty(t, propertyNames(t, repo, lamp))
+}
+
+func TestItemPropertyRepository_Detach(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	repo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+
+	lamp := createItem(t, itemRepo, "Lamp", nil)
+	desk := createItem(t, itemRepo, "Desk", nil)
+	color := createProperty(t, repo, lamp, "color")
+	createProperty(t, repo, lamp, "weight")
+	size := createProperty(t, repo, desk, "size")
+	deskRevisions := revisionCount(t, db, desk)
+
+	// The properties of other items are left alone
+	require.NoError(t, repo.Detach(ctx, lamp, []string{color, size}))
+
+	assert.Equal(t, []string{"weight"}, propertyNames(t, repo, lamp))
+	assert.Equal(t, []string{"size"}, propertyNames(t, repo, desk))
+	assert.Equal(t, deskRevisions, revisionCount(t, db, desk))
+	_, err := repo.Find(ctx, color)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	assert.ErrorIs(t, repo.Detach(ownerCtx("bob"), lamp, []string{size}), domain.ErrNotFound)
+}
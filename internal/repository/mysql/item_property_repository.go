@@ -15,30 +15,109 @@ func NewItemPropertyRepository(db *gorm.DB) domain.ItemPropertyRepository {
 	return &itemPropertyRepository{db: db}
 }
 
-func (r *itemPropertyRepository) GetAllByItemID(ctx context.Context, itemID string) ([]*domain.ItemProperty, error) {
-	var itemProperties []*domain.ItemProperty
-	if err := r.db.WithContext(ctx).Where("item_id = ?", itemID).Find(&itemProperties).Error; err != nil {
-		return nil, err
+func (r *itemPropertyRepository) GetAllByItemID(ctx context.Context, itemID string, page domain.PageRequest, opts domain.QueryOptions) (*domain.Page[*domain.ItemProperty], error) {
+	db := conn(ctx, r.db).Model(&domain.ItemProperty{}).Scopes(visibleItemProperties(ctx)).Where("item_id = ?", itemID)
+	db = withItemPropertyOptions(db, opts)
+	properties, err := findPage(db, "item_properties", page, nil, func(property *domain.ItemProperty) string { return property.ID })
+	if err != nil {
+		return nil, translateError(err)
 	}
-	return itemProperties, nil
+	return properties, nil
 }
 
-func (r *itemPropertyRepository) GetByID(ctx context.Context, itemID string, id string) (*domain.ItemProperty, error) {
+func (r *itemPropertyRepository) GetByID(ctx context.Context, itemID string, id string, opts domain.QueryOptions) (*domain.ItemProperty, error) {
 	var itemProperty domain.ItemProperty
-	if err := r.db.WithContext(ctx).Where("item_id = ?", itemID).First(&itemProperty, "id = ?", id).Error; err != nil {
-		return nil, err
+	db := withItemPropertyOptions(conn(ctx, r.db), opts)
+	if err := db.Scopes(visibleItemProperties(ctx)).Where("item_id = ?", itemID).First(&itemProperty, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &itemProperty, nil
 }
 
+func (r *itemPropertyRepository) Find(ctx context.Context, id string) (*domain.ItemProperty, error) {
+	var itemProperty domain.ItemProperty
+	if err := conn(ctx, r.db).Scopes(visibleItemProperties(ctx)).First(&itemProperty, "item_properties.id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &itemProperty, nil
+}
+
+// Create adds a property, at version 1, to an item visible to the caller and records it in the audit log.
 func (r *itemPropertyRepository) Create(ctx context.Context, itemProperty *domain.ItemProperty) error {
-	return r.db.WithContext(ctx).Create(itemProperty).Error
+	itemProperty.Version = 1
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(visibleItems(ctx)).Select("items.id").First(&domain.Item{}, "items.id = ?", itemProperty.ItemID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(itemProperty).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, itemProperty.ItemID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionCreate, domain.ResourceItemProperties, itemProperty.ID, nil, itemProperty)
+	})
+	return translateError(err)
 }
 
+// Update saves the name and value of a property of an item visible to the caller and
+// increments its version in a single statement, which matches only the property at
+// itemProperty.Version when it is set. The stored property is then read back into
+// itemProperty, and the change is recorded in the audit log. A missing property is
+// reported as domain.ErrNotFound.
 func (r *itemPropertyRepository) Update(ctx context.Context, itemProperty *domain.ItemProperty) error {
-	return r.db.WithContext(ctx).Save(itemProperty).Error
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current := func() *gorm.DB {
+			return tx.Model(&domain.ItemProperty{}).Scopes(visibleItemProperties(ctx)).
+				Where("item_id = ? AND id = ?", itemProperty.ItemID, itemProperty.ID)
+		}
+		var before domain.ItemProperty
+		if err := current().First(&before).Error; err != nil {
+			return err
+		}
+		result := current().Scopes(atVersion("item_properties", itemProperty.Version)).Updates(map[string]interface{}{
+			"name":    itemProperty.Name,
+			"value":   itemProperty.Value,
+			"version": gorm.Expr("version + 1"),
+		})
+		if err := affectedAt(result, itemProperty.Version, current()); err != nil {
+			return err
+		}
+		if err := tx.Scopes(visibleItemProperties(ctx)).Where("item_id = ?", itemProperty.ItemID).First(itemProperty, "id = ?", itemProperty.ID).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, itemProperty.ItemID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionUpdate, domain.ResourceItemProperties, itemProperty.ID, &before, itemProperty)
+	})
+	return translateError(err)
 }
 
-func (r *itemPropertyRepository) Delete(ctx context.Context, itemID string, id string) error {
-	return r.db.WithContext(ctx).Where("item_id = ?", itemID).Delete(&domain.ItemProperty{}, "id = ?", id).Error
+// Delete soft-deletes a property of an item visible to the caller, only at version when it
+// is not 0, and records the deletion in the audit log. A missing property is reported as
+// domain.ErrNotFound.
+func (r *itemPropertyRepository) Delete(ctx context.Context, itemID string, id string, version int64) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current := func() *gorm.DB {
+			return tx.Model(&domain.ItemProperty{}).Scopes(visibleItemProperties(ctx)).Where("item_id = ? AND id = ?", itemID, id)
+		}
+		var before domain.ItemProperty
+		if err := current().First(&before).Error; err != nil {
+			return err
+		}
+		result := tx.Scopes(visibleItemProperties(ctx), atVersion("item_properties", version)).Where("item_id = ?", itemID).Delete(&domain.ItemProperty{}, "id = ?", id)
+		if err := affectedAt(result, version, current()); err != nil {
+			return err
+		}
+		var after domain.ItemProperty
+		if err := tx.Unscoped().First(&after, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, itemID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionDelete, domain.ResourceItemProperties, id, &before, &after)
+	})
+	return translateError(err)
 }
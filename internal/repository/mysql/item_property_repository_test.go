@@ -1,7 +1,6 @@
 package mysql
 
 import (
-	"context"
 	"errors"
 	"testing"
 
@@ -18,7 +17,7 @@ func setupItemPropertyTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("failed to connect database: %v", err)
 	}
 
-	err = db.AutoMigrate(&domain.Item{}, &domain.ItemProperty{})
+	err = db.AutoMigrate(&domain.Item{}, &domain.ItemProperty{}, &domain.AuditEvent{}, &domain.ItemRevision{})
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
@@ -30,11 +29,11 @@ func TestItemPropertyRepository_CRUD(t *testing.T) {
 	db := setupItemPropertyTestDB(t)
 	itemRepo := NewItemRepository(db)
 	propertyRepo := NewItemPropertyRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 
 	// First create an item to associate properties with
 	itemID := uuid.New().String()
-	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description"}
+	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description", OwnerID: "user-1"}
 	err := itemRepo.Create(ctx, item)
 	assert.NoError(t, err)
 
@@ -51,17 +50,17 @@ func TestItemPropertyRepository_CRUD(t *testing.T) {
 	assert.NoError(t, err)
 
 	// GetByID
-	found, err := propertyRepo.GetByID(ctx, itemID, propertyID)
+	found, err := propertyRepo.GetByID(ctx, itemID, propertyID, domain.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, property.Name, found.Name)
 	assert.Equal(t, property.Value, found.Value)
 	assert.Equal(t, itemID, found.ItemID)
 
 	// GetAllByItemID
-	properties, err := propertyRepo.GetAllByItemID(ctx, itemID)
+	properties, err := propertyRepo.GetAllByItemID(ctx, itemID, firstPage, domain.QueryOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, properties, 1)
-	assert.Equal(t, property.Name, properties[0].Name)
+	assert.Len(t, properties.Items, 1)
+	assert.Equal(t, property.Name, properties.Items[0].Name)
 
 	// Update
 	property.Name = "Updated Property Name"
@@ -69,16 +68,16 @@ func TestItemPropertyRepository_CRUD(t *testing.T) {
 	err = propertyRepo.Update(ctx, property)
 	assert.NoError(t, err)
 
-	updated, err := propertyRepo.GetByID(ctx, itemID, propertyID)
+	updated, err := propertyRepo.GetByID(ctx, itemID, propertyID, domain.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Property Name", updated.Name)
 	assert.Equal(t, "Updated Value", updated.Value)
 
 	// Delete
-	err = propertyRepo.Delete(ctx, itemID, propertyID)
+	err = propertyRepo.Delete(ctx, itemID, propertyID, 0)
 	assert.NoError(t, err)
 
-	_, err = propertyRepo.GetByID(ctx, itemID, propertyID)
+	_, err = propertyRepo.GetByID(ctx, itemID, propertyID, domain.QueryOptions{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound) || err != nil)
 }
@@ -87,27 +86,27 @@ func TestItemPropertyRepository_GetAllByItemID_Empty(t *testing.T) {
 	db := setupItemPropertyTestDB(t)
 	itemRepo := NewItemRepository(db)
 	propertyRepo := NewItemPropertyRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 
 	// Create an item without properties
 	itemID := uuid.New().String()
-	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description"}
+	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description", OwnerID: "user-1"}
 	err := itemRepo.Create(ctx, item)
 	assert.NoError(t, err)
 
 	// GetAllByItemID should return empty slice
-	properties, err := propertyRepo.GetAllByItemID(ctx, itemID)
+	properties, err := propertyRepo.GetAllByItemID(ctx, itemID, firstPage, domain.QueryOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, properties, 0)
+	assert.Len(t, properties.Items, 0)
 }
 
 func TestItemPropertyRepository_GetByID_NotFound(t *testing.T) {
 	db := setupItemPropertyTestDB(t)
 	propertyRepo := NewItemPropertyRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 
 	// Try to get a non-existent property
-	_, err := propertyRepo.GetByID(ctx, "non-existent-item", "non-existent-property")
+	_, err := propertyRepo.GetByID(ctx, "non-existent-item", "non-existent-property", domain.QueryOptions{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
 }
@@ -116,11 +115,11 @@ func TestItemPropertyRepository_MultipleProperties(t *testing.T) {
 	db := setupItemPropertyTestDB(t)
 	itemRepo := NewItemRepository(db)
 	propertyRepo := NewItemPropertyRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 
 	// Create an item
 	itemID := uuid.New().String()
-	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description"}
+	item := &domain.Item{ID: itemID, Title: "Test Item", Description: "Test Description", OwnerID: "user-1"}
 	err := itemRepo.Create(ctx, item)
 	assert.NoError(t, err)
 
@@ -152,25 +151,25 @@ func TestItemPropertyRepository_MultipleProperties(t *testing.T) {
 	assert.NoError(t, err)
 
 	// GetAllByItemID should return all 3 properties
-	properties, err := propertyRepo.GetAllByItemID(ctx, itemID)
+	properties, err := propertyRepo.GetAllByItemID(ctx, itemID, firstPage, domain.QueryOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, properties, 3)
+	assert.Len(t, properties.Items, 3)
 }
 
 func TestItemPropertyRepository_PropertiesIsolatedByItem(t *testing.T) {
 	db := setupItemPropertyTestDB(t)
 	itemRepo := NewItemRepository(db)
 	propertyRepo := NewItemPropertyRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 
 	// Create two items
 	itemID1 := uuid.New().String()
-	item1 := &domain.Item{ID: itemID1, Title: "Item 1", Description: "Description 1"}
+	item1 := &domain.Item{ID: itemID1, Title: "Item 1", Description: "Description 1", OwnerID: "user-1"}
 	err := itemRepo.Create(ctx, item1)
 	assert.NoError(t, err)
 
 	itemID2 := uuid.New().String()
-	item2 := &domain.Item{ID: itemID2, Title: "Item 2", Description: "Description 2"}
+	item2 := &domain.Item{ID: itemID2, Title: "Item 2", Description: "Description 2", OwnerID: "user-1"}
 	err = itemRepo.Create(ctx, item2)
 	assert.NoError(t, err)
 
@@ -194,13 +193,114 @@ func TestItemPropertyRepository_PropertiesIsolatedByItem(t *testing.T) {
 	assert.NoError(t, err)
 
 	// GetAllByItemID should only return properties for the specific item
-	propertiesItem1, err := propertyRepo.GetAllByItemID(ctx, itemID1)
+	propertiesItem1, err := propertyRepo.GetAllByItemID(ctx, itemID1, firstPage, domain.QueryOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, propertiesItem1, 1)
-	assert.Equal(t, "Property for Item 1", propertiesItem1[0].Name)
+	assert.Len(t, propertiesItem1.Items, 1)
+	assert.Equal(t, "Property for Item 1", propertiesItem1.Items[0].Name)
 
-	propertiesItem2, err := propertyRepo.GetAllByItemID(ctx, itemID2)
+	propertiesItem2, err := propertyRepo.GetAllByItemID(ctx, itemID2, firstPage, domain.QueryOptions{})
 	assert.NoError(t, err)
-	assert.Len(t, propertiesItem2, 1)
-	assert.Equal(t, "Property for Item 2", propertiesItem2[0].Name)
+	assert.Len(t, propertiesItem2.Items, 1)
+	assert.Equal(t, "Property for Item 2", propertiesItem2.Items[0].Name)
+}
+
+func TestItemPropertyRepository_OwnerIsolation(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	propertyRepo := NewItemPropertyRepository(db)
+	alice := ownerCtx("alice")
+	bob := ownerCtx("bob")
+
+	itemID := uuid.New().String()
+	assert.NoError(t, itemRepo.Create(alice, &domain.Item{ID: itemID, Title: "Alice's Item", OwnerID: "alice"}))
+
+	propertyID := uuid.New().String()
+	property := &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}
+	assert.NoError(t, propertyRepo.Create(alice, property))
+
+	// Bob cannot read the properties of Alice's item
+	properties, err := propertyRepo.GetAllByItemID(bob, itemID, firstPage, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, properties.Items, 0)
+
+	_, err = propertyRepo.GetByID(bob, itemID, propertyID, domain.QueryOptions{})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	// Nor add, change or remove them
+	err = propertyRepo.Create(bob, &domain.ItemProperty{ID: uuid.New().String(), ItemID: itemID, Name: "size", Value: "large"})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	err = propertyRepo.Update(bob, &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "blue"})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	assert.ErrorIs(t, propertyRepo.Delete(bob, itemID, propertyID, 0), domain.ErrNotFound)
+
+	properties, err = propertyRepo.GetAllByItemID(alice, itemID, firstPage, domain.QueryOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, properties.Items, 1) {
+		assert.Equal(t, "red", properties.Items[0].Value)
+	}
+
+	// Admins see and manage every item's properties
+	properties, err = propertyRepo.GetAllByItemID(adminCtx(), itemID, firstPage, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, properties.Items, 1)
+	assert.NoError(t, propertyRepo.Delete(adminCtx(), itemID, propertyID, 0))
+
+	_, err = propertyRepo.GetByID(alice, itemID, propertyID, domain.QueryOptions{})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestItemPropertyRepository_ConditionalWrites(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemID := createItem(t, NewItemRepository(db), "Lamp", nil)
+	repo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+
+	property := &domain.ItemProperty{ID: id, ItemID: itemID, Name: "color", Value: "red"}
+	assert.NoError(t, repo.Create(ctx, property))
+	assert.Equal(t, int64(1), property.Version)
+
+	updated := &domain.ItemProperty{ID: id, ItemID: itemID, Name: "color", Value: "blue", Version: 1}
+	assert.NoError(t, repo.Update(ctx, updated))
+	assert.Equal(t, int64(2), updated.Version)
+
+	// Writes expecting the replaced version fail and leave the property as is
+	stale := &domain.ItemProperty{ID: id, ItemID: itemID, Name: "color", Value: "green", Version: 1}
+	assert.ErrorIs(t, repo.Update(ctx, stale), domain.ErrPreconditionFailed)
+	assert.ErrorIs(t, repo.Delete(ctx, itemID, id, 1), domain.ErrPreconditionFailed)
+
+	stored, err := repo.GetByID(ctx, itemID, id, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "blue", stored.Value)
+
+	assert.NoError(t, repo.Delete(ctx, itemID, id, 2))
+	assert.ErrorIs(t, repo.Delete(ctx, itemID, id, 2), domain.ErrNotFound)
+}
+
+func TestItemPropertyRepository_Find(t *testing.T) {
+	db := setupItemPropertyTestDB(t)
+	itemRepo := NewItemRepository(db)
+	propertyRepo := NewItemPropertyRepository(db)
+	alice := ownerCtx("alice")
+
+	itemID := uuid.New().String()
+	assert.NoError(t, itemRepo.Create(alice, &domain.Item{ID: itemID, Title: "Lamp", OwnerID: "alice"}))
+	propertyID := uuid.New().String()
+	assert.NoError(t, propertyRepo.Create(alice, &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}))
+
+	found, err := propertyRepo.Find(alice, propertyID)
+	assert.NoError(t, err)
+	assert.Equal(t, itemID, found.ItemID)
+	assert.Equal(t, "red", found.Value)
+
+	// Only within the items visible to the caller
+	_, err = propertyRepo.Find(ownerCtx("bob"), propertyID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Nor once the item is deleted
+	assert.NoError(t, itemRepo.Delete(alice, itemID, 0))
+	_, err = propertyRepo.Find(alice, propertyID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
 }
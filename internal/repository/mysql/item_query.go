@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// likeEscaper escapes the LIKE wildcards of a value with '!', which needs no
+// escaping in string literals of either MySQL or SQLite.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// itemFilters returns a scope applying the filters of query to items.
+func itemFilters(query domain.ItemQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.TitlePrefix != "" {
+			db = db.Where("items.title LIKE ? ESCAPE '!'", likeEscaper.Replace(query.TitlePrefix)+"%")
+		}
+
+		db = db.Scopes(inTimeRange("items.created_at", query.CreatedAt))
+
+		for name, value := range query.Properties {
+			db = db.Where("EXISTS (SELECT 1 FROM item_properties WHERE item_properties.item_id = items.id AND item_properties.name = ? AND item_properties.value = ? AND item_properties.deleted_at IS NULL)", name, value)
+		}
+		return db
+	}
+}
+
+// inTimeRange restricts a query to the rows whose time column is within r.
+func inTimeRange(column string, r domain.TimeRange) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// Times are compared in the server's location, which they are stored in
+		if r.GTE != nil {
+			db = db.Where(column+" >= ?", r.GTE.Local())
+		}
+		if r.GT != nil {
+			db = db.Where(column+" > ?", r.GT.Local())
+		}
+		if r.LTE != nil {
+			db = db.Where(column+" <= ?", r.LTE.Local())
+		}
+		if r.LT != nil {
+			db = db.Where(column+" < ?", r.LT.Local())
+		}
+		return db
+	}
+}
+
+// formatTime formats a time as stored in cursors.
+func formatTime(t time.Time) *string {
+	s := t.Format(time.RFC3339Nano)
+	return &s
+}
+
+func parseTime(s string) (any, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// itemSortColumns maps the fields items can be sorted by to their columns.
+var itemSortColumns = map[string]sortColumn[*domain.Item]{
+	domain.ItemSortTitle: {
+		column: "items.title",
+		value:  func(item *domain.Item) *string { return &item.Title },
+		arg:    func(s string) (any, error) { return s, nil },
+	},
+	domain.ItemSortCreatedAt: {
+		column: "items.created_at",
+		value: func(item *domain.Item) *string {
+			if item.CreatedAt == nil {
+				return nil
+			}
+			return formatTime(*item.CreatedAt)
+		},
+		arg: parseTime,
+	},
+	domain.ItemSortUpdatedAt: {
+		column: "items.updated_at",
+		value:  func(item *domain.Item) *string { return formatTime(item.UpdatedAt) },
+		arg:    parseTime,
+	},
+}
+
+// itemOrder resolves the sort fields of a query to columns.
+func itemOrder(sort []domain.SortField) ([]orderBy[*domain.Item], error) {
+	order := make([]orderBy[*domain.Item], 0, len(sort))
+	for _, field := range sort {
+		column, ok := itemSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("items cannot be sorted by %q", field.Field)
+		}
+		order = append(order, orderBy[*domain.Item]{sortColumn: column, desc: field.Desc})
+	}
+	return order, nil
+}
@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createItem stores an item owned by alice and returns its ID
+func createItem(t *testing.T, repo domain.ItemRepository, title string, createdAt *time.Time) string {
+	id := uuid.New().String()
+	require.NoError(t, repo.Create(ownerCtx("alice"), &domain.Item{ID: id, Title: title, OwnerID: "alice", CreatedAt: createdAt}))
+	return id
+}
+
+// titles returns the titles of the items of a page
+func titles(page *domain.Page[*domain.Item]) []string {
+	result := []string{}
+	for _, item := range page.Items {
+		result = append(result, item.Title)
+	}
+	return result
+}
+
+func setupItemQueryDB(t *testing.T) *gorm.DB {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.ItemProperty{}))
+	return db
+}
+
+func TestItemRepository_GetAll_FilterTitlePrefix(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	createItem(t, repo, "Apple", nil)
+	createItem(t, repo, "Apricot", nil)
+	createItem(t, repo, "Banana", nil)
+	createItem(t, repo, "50% off", nil)
+	createItem(t, repo, "500 items", nil)
+
+	page, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{TitlePrefix: "Ap", Sort: []domain.SortField{{Field: domain.ItemSortTitle}}, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "Apricot"}, titles(page))
+	assert.Equal(t, int64(2), page.Total)
+
+	// LIKE wildcards in the prefix match literally
+	page, err = repo.GetAll(ownerCtx("alice"), domain.ItemQuery{TitlePrefix: "50%", Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"50% off"}, titles(page))
+}
+
+func TestItemRepository_GetAll_FilterCreatedAt(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	day := func(d int) *time.Time {
+		t := time.Date(2024, time.January, d, 12, 0, 0, 0, time.Local)
+		return &t
+	}
+	createItem(t, repo, "First", day(1))
+	createItem(t, repo, "Second", day(2))
+	createItem(t, repo, "Third", day(3))
+	createItem(t, repo, "Undated", nil)
+
+	sort := []domain.SortField{{Field: domain.ItemSortCreatedAt}}
+	from := day(2).UTC()
+	page, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{CreatedAt: domain.TimeRange{GTE: &from}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Second", "Third"}, titles(page))
+
+	page, err = repo.GetAll(ownerCtx("alice"), domain.ItemQuery{CreatedAt: domain.TimeRange{GT: day(1), LT: day(3)}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Second"}, titles(page))
+
+	page, err = repo.GetAll(ownerCtx("alice"), domain.ItemQuery{CreatedAt: domain.TimeRange{LTE: day(2)}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, titles(page))
+}
+
+func TestItemRepository_GetAll_FilterProperties(t *testing.T) {
+	db := setupItemQueryDB(t)
+	repo := NewItemRepository(db)
+	propertyRepo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+
+	red := createItem(t, repo, "Red", nil)
+	redLarge := createItem(t, repo, "Red Large", nil)
+	createItem(t, repo, "Plain", nil)
+	for _, p := range []domain.ItemProperty{
+		{ItemID: red, Name: "color", Value: "red"},
+		{ItemID: redLarge, Name: "color", Value: "red"},
+		{ItemID: redLarge, Name: "size", Value: "large"},
+	} {
+		p.ID = uuid.New().String()
+		require.NoError(t, propertyRepo.Create(ctx, &p))
+	}
+	sort := []domain.SortField{{Field: domain.ItemSortTitle}}
+
+	page, err := repo.GetAll(ctx, domain.ItemQuery{Properties: map[string]string{"color": "red"}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Red", "Red Large"}, titles(page))
+
+	// Every property has to match
+	page, err = repo.GetAll(ctx, domain.ItemQuery{Properties: map[string]string{"color": "red", "size": "large"}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Red Large"}, titles(page))
+
+	page, err = repo.GetAll(ctx, domain.ItemQuery{Properties: map[string]string{"color": "blue"}, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Equal(t, int64(0), page.Total)
+
+	// Deleted properties do not match
+	large, err := propertyRepo.GetAllByItemID(ctx, redLarge, firstPage, domain.QueryOptions{})
+	require.NoError(t, err)
+	for _, p := range large.Items {
+		require.NoError(t, propertyRepo.Delete(ctx, redLarge, p.ID, 0))
+	}
+	page, err = repo.GetAll(ctx, domain.ItemQuery{Properties: map[string]string{"color": "red"}, Sort: sort, Page: firstPage})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Red"}, titles(page))
+}
+
+func TestItemRepository_GetAll_Sort(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	early := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+	late := early.Add(time.Hour)
+	createItem(t, repo, "B", &early)
+	createItem(t, repo, "A", &early)
+	createItem(t, repo, "C", &late)
+	createItem(t, repo, "D", nil)
+
+	page, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{
+		Sort: []domain.SortField{{Field: domain.ItemSortCreatedAt, Desc: true}, {Field: domain.ItemSortTitle}},
+		Page: firstPage,
+	})
+	assert.NoError(t, err)
+	// NULLs sort last in descending order
+	assert.Equal(t, []string{"C", "A", "B", "D"}, titles(page))
+
+	_, err = repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Sort: []domain.SortField{{Field: "owner_id"}}, Page: firstPage})
+	assert.Error(t, err)
+}
+
+func TestItemRepository_GetAll_SortedCursorPagination(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+	for i, title := range []string{"E", "B", "A", "D", "C", "F", "G"} {
+		var createdAt *time.Time
+		if i%3 != 0 {
+			// Several items share a creation time, and some have none
+			at := base.Add(time.Duration(i%2) * time.Hour)
+			createdAt = &at
+		}
+		createItem(t, repo, title, createdAt)
+	}
+
+	for _, sort := range [][]domain.SortField{
+		{{Field: domain.ItemSortCreatedAt}, {Field: domain.ItemSortTitle}},
+		{{Field: domain.ItemSortCreatedAt, Desc: true}, {Field: domain.ItemSortTitle, Desc: true}},
+		{{Field: domain.ItemSortTitle, Desc: true}},
+	} {
+		all, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Sort: sort, Page: firstPage})
+		require.NoError(t, err)
+
+		var seen []string
+		page := domain.PageRequest{Size: 2}
+		for {
+			result, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Sort: sort, Page: page})
+			require.NoError(t, err)
+			seen = append(seen, titles(result)...)
+			if result.NextCursor == "" {
+				break
+			}
+			page.Cursor = result.NextCursor
+		}
+		assert.Equal(t, titles(all), seen, "sort %v", sort)
+	}
+}
+
+func TestItemRepository_GetAll_CursorOfAnotherSort(t *testing.T) {
+	repo := NewItemRepository(setupItemQueryDB(t))
+	for _, title := range []string{"A", "B", "C"} {
+		createItem(t, repo, title, nil)
+	}
+
+	page, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Sort: []domain.SortField{{Field: domain.ItemSortTitle}}, Page: domain.PageRequest{Size: 1}})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	_, err = repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Page: domain.PageRequest{Size: 1, Cursor: page.NextCursor}})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"time"
 
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"gorm.io/gorm"
@@ -15,38 +16,232 @@ func NewItemRepository(db *gorm.DB) domain.ItemRepository {
 	return &itemRepository{db: db}
 }
 
-func (r *itemRepository) GetAll(ctx context.Context) ([]*domain.Item, error) {
-	var items []*domain.Item
-	db := r.db.WithContext(ctx)
-	if ctx.Value("include_properties") == true {
-		db = db.Preload("ItemProperties")
-	}
-	if err := db.Find(&items).Error; err != nil {
+func (r *itemRepository) GetAll(ctx context.Context, query domain.ItemQuery) (*domain.Page[*domain.Item], error) {
+	order, err := itemOrder(query.Sort)
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+
+	// Cursors are made of the sort columns, which are loaded whatever the fieldset
+	sortColumns := make([]string, len(order))
+	for i, o := range order {
+		sortColumns[i] = o.column
+	}
+	db := conn(ctx, r.db).Model(&domain.Item{}).Scopes(visibleItems(ctx), itemFilters(query))
+	db = withItemOptions(db, query.Options, sortColumns...)
+	page, err := findPage(db, "items", query.Page, order, func(item *domain.Item) string { return item.ID })
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return page, nil
 }
 
-func (r *itemRepository) GetByID(ctx context.Context, id string) (*domain.Item, error) {
+func (r *itemRepository) GetByID(ctx context.Context, id string, opts domain.QueryOptions) (*domain.Item, error) {
 	var item domain.Item
-	db := r.db.WithContext(ctx)
-	if ctx.Value("include_properties") == true {
-		db = db.Preload("ItemProperties")
-	}
-	if err := db.First(&item, "id = ?", id).Error; err != nil {
-		return nil, err
+	db := withItemOptions(conn(ctx, r.db).Scopes(visibleItems(ctx)), opts)
+	if err := db.First(&item, "items.id = ?", id).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &item, nil
 }
 
+// Create stores an item in the tenant of ctx, at version 1, and records it in the audit log.
 func (r *itemRepository) Create(ctx context.Context, item *domain.Item) error {
-	return r.db.WithContext(ctx).Create(item).Error
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return err
+	}
+	item.TenantID = tenantID
+	item.Version = 1
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, item.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionCreate, domain.ResourceItems, item.ID, nil, item)
+	})
+	return translateError(err)
 }
 
+// Update saves the title and description of an item visible to the caller and
+// increments its version in a single statement, which matches only the item at
+// item.Version when it is set. The stored item, with the owner, tenant and
+// creation time it keeps, is then read back into item, and the change is recorded
+// in the audit log. An item that is not stored, or not visible, is reported as
+// domain.ErrNotFound.
 func (r *itemRepository) Update(ctx context.Context, item *domain.Item) error {
-	return r.db.WithContext(ctx).Save(item).Error
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current := func() *gorm.DB {
+			return tx.Model(&domain.Item{}).Scopes(visibleItems(ctx)).Where("items.id = ?", item.ID)
+		}
+		var before domain.Item
+		if err := current().First(&before).Error; err != nil {
+			return err
+		}
+		result := current().Scopes(atVersion("items", item.Version)).Updates(map[string]interface{}{
+			"title":       item.Title,
+			"description": item.Description,
+			"version":     gorm.Expr("version + 1"),
+		})
+		if err := affectedAt(result, item.Version, current()); err != nil {
+			return err
+		}
+		if err := tx.Scopes(visibleItems(ctx)).First(item, "items.id = ?", item.ID).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, item.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionUpdate, domain.ResourceItems, item.ID, &before, item)
+	})
+	return translateError(err)
+}
+
+// Delete soft-deletes an item visible to the caller, only at version when it is not 0,
+// along with its properties. They are given the same deletion time, which tells the
+// properties deleted with the item from the ones deleted before it (see Restore).
+// The deletion of the item is recorded in the audit log. A missing item is reported
+// as domain.ErrNotFound.
+func (r *itemRepository) Delete(ctx context.Context, id string, version int64) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		current := func() *gorm.DB {
+			return tx.Model(&domain.Item{}).Scopes(visibleItems(ctx)).Where("items.id = ?", id)
+		}
+		var before domain.Item
+		if err := current().First(&before).Error; err != nil {
+			return err
+		}
+		deletedAt := tx.NowFunc()
+		result := current().Scopes(atVersion("items", version)).Update("deleted_at", deletedAt)
+		if err := affectedAt(result, version, current()); err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.ItemProperty{}).Where("item_id = ?", id).Update("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		var after domain.Item
+		if err := tx.Scopes(deletedItems(ctx)).First(&after, "items.id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionDelete, domain.ResourceItems, id, &before, &after)
+	})
+	return translateError(err)
 }
 
-func (r *itemRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&domain.Item{}, "id = ?", id).Error
+// deletedItemOrder orders soft-deleted items, most recently deleted first.
+var deletedItemOrder = []orderBy[*domain.Item]{{
+	sortColumn: sortColumn[*domain.Item]{
+		column: "items.deleted_at",
+		value:  func(item *domain.Item) *string { return formatTime(item.DeletedAt.Time) },
+		arg:    parseTime,
+	},
+	desc: true,
+}}
+
+// deletedItems restricts an items query to the soft-deleted items visible to the caller.
+func deletedItems(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Scopes(visibleItems(ctx)).Where("items.deleted_at IS NOT NULL")
+	}
+}
+
+func (r *itemRepository) GetDeleted(ctx context.Context, page domain.PageRequest) (*domain.Page[*domain.Item], error) {
+	db := conn(ctx, r.db).Model(&domain.Item{}).Scopes(deletedItems(ctx))
+	result, err := findPage(db, "items", page, deletedItemOrder, func(item *domain.Item) string { return item.ID })
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return result, nil
+}
+
+// Restore undeletes a soft-deleted item visible to the caller and the properties
+// deleted with it, and moves the item to its next version. Properties deleted
+// before the item stay deleted. The restoration is recorded in the audit log. An
+// item that is not deleted is reported as domain.ErrNotFound.
+func (r *itemRepository) Restore(ctx context.Context, id string) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var before domain.Item
+		if err := tx.Scopes(deletedItems(ctx)).First(&before, "items.id = ?", id).Error; err != nil {
+			return err
+		}
+		deletedAt := tx.Session(&gorm.Session{NewDB: true}).Table("items").Select("items.deleted_at").Where("items.id = ?", id)
+		if err := tx.Unscoped().Model(&domain.ItemProperty{}).Where("item_id = ? AND deleted_at = (?)", id, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&domain.Item{}).Where("items.id = ?", id).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		var after domain.Item
+		if err := tx.Scopes(visibleItems(ctx)).First(&after, "items.id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionRestore, domain.ResourceItems, id, &before, &after)
+	})
+	return translateError(err)
+}
+
+// Purge permanently deletes a soft-deleted item visible to the caller, all of its
+// properties and its revisions, and records the purge in the audit log. An item
+// that is not deleted is reported as domain.ErrNotFound.
+func (r *itemRepository) Purge(ctx context.Context, id string) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var before domain.Item
+		if err := tx.Scopes(deletedItems(ctx)).First(&before, "items.id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("item_id = ?", id).Delete(&domain.ItemProperty{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("item_id = ?", id).Delete(&domain.ItemRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&domain.Item{}, "items.id = ?", id).Error; err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, domain.AuditActionPurge, domain.ResourceItems, id, &before, nil)
+	})
+	return translateError(err)
+}
+
+// PurgeDeleted permanently deletes the items soft-deleted before the given time with
+// all of their properties and revisions, then the properties soft-deleted on their own before it.
+// It runs without a caller, across every tenant, and records nothing in the audit log.
+func (r *itemRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Session(&gorm.Session{NewDB: true}).Table("items").Select("items.id").Where("items.deleted_at < ?", before)
+		// Revisions are history rather than deleted rows, and are not counted
+		if err := tx.Where("item_id IN (?)", expired).Delete(&domain.ItemRevision{}).Error; err != nil {
+			return err
+		}
+		steps := []func() *gorm.DB{
+			func() *gorm.DB { return tx.Unscoped().Where("item_id IN (?)", expired).Delete(&domain.ItemProperty{}) },
+			func() *gorm.DB { return tx.Unscoped().Where("deleted_at < ?", before).Delete(&domain.Item{}) },
+			func() *gorm.DB { return tx.Unscoped().Where("deleted_at < ?", before).Delete(&domain.ItemProperty{}) },
+		}
+		for _, step := range steps {
+			result := step()
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return purged, nil
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -18,7 +20,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("failed to connect database: %v", err)
 	}
 
-	err = db.AutoMigrate(&domain.Item{})
+	err = db.AutoMigrate(&domain.Item{}, &domain.ItemProperty{}, &domain.AuditEvent{}, &domain.ItemRevision{})
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
@@ -26,39 +28,352 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// testTenant is the tenant of the contexts returned by ownerCtx and adminCtx
+const testTenant = "acme"
+
+// tenantCtx returns a context scoped to tenantID and authenticated as subject with the given roles
+func tenantCtx(tenantID string, subject string, roles ...string) context.Context {
+	ctx := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: subject, Roles: roles})
+	return domain.ContextWithTenant(ctx, tenantID)
+}
+
+// ownerCtx returns a context authenticated as a regular caller
+func ownerCtx(subject string) context.Context {
+	return tenantCtx(testTenant, subject)
+}
+
+// firstPage is large enough to hold every row created by a test
+var firstPage = domain.PageRequest{Number: 1, Size: 100}
+
+// adminCtx returns a context authenticated as an admin
+func adminCtx() context.Context {
+	return tenantCtx(testTenant, "admin-1", domain.RoleAdmin)
+}
+
 func TestItemRepository_CRUD(t *testing.T) {
 	db := setupTestDB(t)
 	repo := NewItemRepository(db)
-	ctx := context.Background()
+	ctx := ownerCtx("user-1")
 	uuidTest := uuid.New().String()
 	// Create
-	item := &domain.Item{ID: uuidTest, Title: "Test Item", Description: "Test Description"}
+	item := &domain.Item{ID: uuidTest, Title: "Test Item", Description: "Test Description", OwnerID: "user-1"}
 	err := repo.Create(ctx, item)
 	assert.NoError(t, err)
 
 	// GetByID
-	found, err := repo.GetByID(ctx, uuidTest)
+	found, err := repo.GetByID(ctx, uuidTest, domain.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, item.Title, found.Title)
 
 	// GetAll
-	items, err := repo.GetAll(ctx)
+	page, err := repo.GetAll(ctx, domain.ItemQuery{Page: firstPage})
 	assert.NoError(t, err)
-	assert.Len(t, items, 1)
+	assert.Len(t, page.Items, 1)
 
 	// Update
 	item.Title = "Updated Title"
 	err = repo.Update(ctx, item)
 	assert.NoError(t, err)
 
-	updated, _ := repo.GetByID(ctx, uuidTest)
+	updated, _ := repo.GetByID(ctx, uuidTest, domain.QueryOptions{})
 	assert.Equal(t, "Updated Title", updated.Title)
 
 	// Delete
-	err = repo.Delete(ctx, uuidTest)
+	err = repo.Delete(ctx, uuidTest, 0)
 	assert.NoError(t, err)
 
-	_, err = repo.GetByID(ctx, uuidTest)
+	_, err = repo.GetByID(ctx, uuidTest, domain.QueryOptions{})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound) || err != nil)
 }
+
+func TestItemRepository_OwnerIsolation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	alice := ownerCtx("alice")
+	bob := ownerCtx("bob")
+
+	aliceItemID := uuid.New().String()
+	assert.NoError(t, repo.Create(alice, &domain.Item{ID: aliceItemID, Title: "Alice's Item", OwnerID: "alice"}))
+	assert.NoError(t, repo.Create(bob, &domain.Item{ID: uuid.New().String(), Title: "Bob's Item", OwnerID: "bob"}))
+
+	// Each owner only lists their own items
+	page, err := repo.GetAll(alice, domain.ItemQuery{Page: firstPage})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Items, 1) {
+		assert.Equal(t, aliceItemID, page.Items[0].ID)
+	}
+
+	// Bob can neither read nor mutate Alice's item
+	_, err = repo.GetByID(bob, aliceItemID, domain.QueryOptions{})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	err = repo.Update(bob, &domain.Item{ID: aliceItemID, Title: "Hijacked"})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	assert.ErrorIs(t, repo.Delete(bob, aliceItemID, 0), domain.ErrNotFound)
+
+	found, err := repo.GetByID(alice, aliceItemID, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice's Item", found.Title)
+	assert.Equal(t, "alice", found.OwnerID)
+}
+
+func TestItemRepository_UpdateKeepsOwner(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+
+	assert.NoError(t, repo.Create(ctx, &domain.Item{ID: id, Title: "Alice's Item", OwnerID: "alice"}))
+
+	// An owner sent in the update payload is ignored
+	item := &domain.Item{ID: id, Title: "Renamed", OwnerID: "bob"}
+	assert.NoError(t, repo.Update(ctx, item))
+	assert.Equal(t, "alice", item.OwnerID)
+
+	found, err := repo.GetByID(ctx, id, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", found.Title)
+	assert.Equal(t, "alice", found.OwnerID)
+}
+
+func TestItemRepository_AdminSeesAllItems(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	aliceItemID := uuid.New().String()
+
+	assert.NoError(t, repo.Create(ownerCtx("alice"), &domain.Item{ID: aliceItemID, Title: "Alice's Item", OwnerID: "alice"}))
+	assert.NoError(t, repo.Create(ownerCtx("bob"), &domain.Item{ID: uuid.New().String(), Title: "Bob's Item", OwnerID: "bob"}))
+
+	page, err := repo.GetAll(adminCtx(), domain.ItemQuery{Page: firstPage})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+
+	assert.NoError(t, repo.Update(adminCtx(), &domain.Item{ID: aliceItemID, Title: "Moderated"}))
+	assert.NoError(t, repo.Delete(adminCtx(), aliceItemID, 0))
+
+	_, err = repo.GetByID(ownerCtx("alice"), aliceItemID, domain.QueryOptions{})
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestItemRepository_RequiresPrincipal(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	id := uuid.New().String()
+
+	assert.NoError(t, repo.Create(ownerCtx("alice"), &domain.Item{ID: id, Title: "Alice's Item", OwnerID: "alice"}))
+
+	anonymous := domain.ContextWithTenant(context.Background(), testTenant)
+
+	_, err := repo.GetAll(anonymous, domain.ItemQuery{Page: firstPage})
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	_, err = repo.GetByID(anonymous, id, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	assert.ErrorIs(t, repo.Delete(anonymous, id, 0), domain.ErrUnauthenticated)
+
+	_, err = repo.GetByID(ownerCtx("alice"), id, domain.QueryOptions{})
+	assert.NoError(t, err)
+}
+
+func TestItemRepository_GetAll_NumberPagination(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("alice")
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.Create(ctx, &domain.Item{ID: uuid.New().String(), Title: "Item", OwnerID: "alice"}))
+	}
+
+	page, err := repo.GetAll(ctx, domain.ItemQuery{Page: domain.PageRequest{Number: 2, Size: 2}})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, int64(5), page.Total)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = repo.GetAll(ctx, domain.ItemQuery{Page: domain.PageRequest{Number: 3, Size: 2}})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	page, err = repo.GetAll(ctx, domain.ItemQuery{Page: domain.PageRequest{Number: 4, Size: 2}})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 0)
+	assert.Equal(t, int64(5), page.Total)
+}
+
+func TestItemRepository_GetAll_CursorPagination(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("alice")
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.Create(ctx, &domain.Item{ID: uuid.New().String(), Title: "Item", OwnerID: "alice"}))
+	}
+
+	// Walk the list with cursors, starting from the first page
+	var seen []string
+	page := domain.PageRequest{Size: 2}
+	for {
+		result, err := repo.GetAll(ctx, domain.ItemQuery{Page: page})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.Total)
+		for _, item := range result.Items {
+			seen = append(seen, item.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	all, err := repo.GetAll(ctx, domain.ItemQuery{Page: firstPage})
+	assert.NoError(t, err)
+	var ids []string
+	for _, item := range all.Items {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, ids, seen)
+}
+
+func TestItemRepository_GetAll_InvalidCursor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+
+	_, err := repo.GetAll(ownerCtx("alice"), domain.ItemQuery{Page: domain.PageRequest{Size: 2, Cursor: "not a cursor"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestItemRepository_ConditionalWrites(t *testing.T) {
+	repo := NewItemRepository(setupTestDB(t))
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+
+	item := &domain.Item{ID: id, Title: "Lamp", OwnerID: "alice"}
+	assert.NoError(t, repo.Create(ctx, item))
+	assert.Equal(t, int64(1), item.Version)
+
+	// An update at the stored version applies and moves the item to the next one
+	first := &domain.Item{ID: id, Title: "Teak lamp", Version: 1}
+	assert.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, int64(2), first.Version)
+	assert.Equal(t, "alice", first.OwnerID)
+	assert.False(t, first.UpdatedAt.Before(item.UpdatedAt))
+
+	// A second writer that read version 1 does not overwrite the first one
+	second := &domain.Item{ID: id, Title: "Oak lamp", Version: 1}
+	assert.ErrorIs(t, repo.Update(ctx, second), domain.ErrPreconditionFailed)
+	assert.ErrorIs(t, repo.Delete(ctx, id, 1), domain.ErrPreconditionFailed)
+
+	// The version and update time are loaded whatever the fieldset
+	stored, err := repo.GetByID(ctx, id, domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"title"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Teak lamp", stored.Title)
+	assert.Equal(t, int64(2), stored.Version)
+	assert.False(t, stored.UpdatedAt.IsZero())
+
+	// Unconditional writes apply to any version
+	assert.NoError(t, repo.Update(ctx, &domain.Item{ID: id, Title: "Pine lamp"}))
+	assert.NoError(t, repo.Delete(ctx, id, 3))
+
+	// A missing item is not found rather than at another version
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Item{ID: id, Title: "Lamp", Version: 3}), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, id, 3), domain.ErrNotFound)
+}
+
+func TestItemRepository_SoftDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+	id := uuid.New().String()
+	kept, dropped := uuid.New().String(), uuid.New().String()
+
+	require.NoError(t, repo.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "alice", ItemProperties: []*domain.ItemProperty{
+		{ID: kept, Name: "color", Value: "red"},
+		{ID: dropped, Name: "size", Value: "small"},
+	}}))
+	// A property deleted before its item stays deleted when the item is restored
+	require.NoError(t, properties.Delete(ctx, id, dropped, 0))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+
+	// The item and its properties are hidden, but kept
+	_, err := repo.GetByID(ctx, id, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = properties.GetByID(ctx, id, kept, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, id, 0), domain.ErrNotFound)
+	var stored int64
+	require.NoError(t, db.Unscoped().Model(&domain.ItemProperty{}).Where("item_id = ?", id).Count(&stored).Error)
+	assert.Equal(t, int64(2), stored)
+
+	deleted, err := repo.GetDeleted(adminCtx(), firstPage)
+	require.NoError(t, err)
+	if assert.Len(t, deleted.Items, 1) {
+		assert.Equal(t, id, deleted.Items[0].ID)
+		assert.True(t, deleted.Items[0].DeletedAt.Valid)
+	}
+	// Other owners do not see it in the trash either
+	deleted, err = repo.GetDeleted(ownerCtx("bob"), firstPage)
+	require.NoError(t, err)
+	assert.Empty(t, deleted.Items)
+
+	require.NoError(t, repo.Restore(adminCtx(), id))
+	restored, err := repo.GetByID(ctx, id, domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), restored.Version)
+	if assert.Len(t, restored.ItemProperties, 1) {
+		assert.Equal(t, kept, restored.ItemProperties[0].ID)
+	}
+	// Only deleted items can be restored or purged
+	assert.ErrorIs(t, repo.Restore(adminCtx(), id), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Purge(adminCtx(), id), domain.ErrNotFound)
+
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	require.NoError(t, repo.Purge(adminCtx(), id))
+	require.NoError(t, db.Unscoped().Model(&domain.ItemProperty{}).Where("item_id = ?", id).Count(&stored).Error)
+	assert.Zero(t, stored)
+	deleted, err = repo.GetDeleted(adminCtx(), firstPage)
+	require.NoError(t, err)
+	assert.Empty(t, deleted.Items)
+}
+
+func TestItemRepository_PurgeDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+	expired, recent, live := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	property := uuid.New().String()
+
+	for _, id := range []string{expired, recent, live} {
+		require.NoError(t, repo.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "alice", ItemProperties: []*domain.ItemProperty{
+			{ID: uuid.New().String(), Name: "color", Value: "red"},
+		}}))
+	}
+	require.NoError(t, properties.Create(ctx, &domain.ItemProperty{ID: property, ItemID: live, Name: "size", Value: "small"}))
+	require.NoError(t, repo.Delete(ctx, expired, 0))
+	require.NoError(t, properties.Delete(ctx, live, property, 0))
+	// Age the rows deleted so far past the retention period
+	past := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, db.Unscoped().Model(&domain.Item{}).Where("deleted_at IS NOT NULL").Update("deleted_at", past).Error)
+	require.NoError(t, db.Unscoped().Model(&domain.ItemProperty{}).Where("deleted_at IS NOT NULL").Update("deleted_at", past).Error)
+	require.NoError(t, repo.Delete(ctx, recent, 0))
+
+	// The expired item with its property, and the expired property of the live item
+	purged, err := repo.PurgeDeleted(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+
+	var items, props int64
+	require.NoError(t, db.Unscoped().Model(&domain.Item{}).Count(&items).Error)
+	require.NoError(t, db.Unscoped().Model(&domain.ItemProperty{}).Count(&props).Error)
+	assert.Equal(t, int64(2), items)
+	assert.Equal(t, int64(2), props)
+
+	// The revisions of the expired item go with it, uncounted
+	var revisions int64
+	require.NoError(t, db.Model(&domain.ItemRevision{}).Where("item_id = ?", expired).Count(&revisions).Error)
+	assert.Zero(t, revisions)
+}
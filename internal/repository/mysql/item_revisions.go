@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// revisionOrder orders the revisions of an item, most recent first.
+var revisionOrder = []orderBy[*domain.ItemRevision]{{
+	sortColumn: sortColumn[*domain.ItemRevision]{
+		column: "item_revisions.revision",
+		value: func(revision *domain.ItemRevision) *string {
+			s := strconv.FormatInt(revision.Revision, 10)
+			return &s
+		},
+		arg: func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) },
+	},
+	desc: true,
+}}
+
+// visibleRevisions restricts an item_revisions query to the revisions of the items of the
+// tenant in ctx that the principal in ctx may see, whether the items are deleted or not.
+func visibleRevisions(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantID, err := tenantOf(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		ownerID, all, err := visibleOwner(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		db = db.Where("item_revisions.tenant_id = ?", tenantID)
+		if !all {
+			db = db.Where("item_revisions.owner_id = ?", ownerID)
+		}
+		return db
+	}
+}
+
+// recordRevision stores the next revision of an item in tx, the transaction of the change
+// to the item or its properties: a snapshot of the item, deleted or not, and of the
+// properties it has after the change.
+func recordRevision(tx *gorm.DB, itemID string) error {
+	return recordRevisions(tx, []string{itemID})
+}
+
+// recordRevisions stores the next revision of each of the distinct items identified by
+// itemIDs, like recordRevision, with a few queries however many items there are.
+func recordRevisions(tx *gorm.DB, itemIDs []string) error {
+	// Locking the items serializes the revisions of concurrent changes to their properties,
+	// which would otherwise pick the same number (SQLite locks the whole database anyway)
+	var items []*domain.Item
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("items.id IN ?", itemIDs).Find(&items).Error; err != nil {
+		return err
+	}
+	if len(items) != len(itemIDs) {
+		return gorm.ErrRecordNotFound
+	}
+	var properties []*domain.ItemProperty
+	if err := tx.Where("item_id IN ?", itemIDs).Order("item_properties.id").Find(&properties).Error; err != nil {
+		return err
+	}
+	var last []struct {
+		ItemID   string
+		Revision int64
+	}
+	if err := tx.Model(&domain.ItemRevision{}).Where("item_id IN ?", itemIDs).Group("item_id").
+		Select("item_id, MAX(revision) AS revision").Scan(&last).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[string]*domain.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for _, property := range properties {
+		item := byID[property.ItemID]
+		item.ItemProperties = append(item.ItemProperties, property)
+	}
+	next := make(map[string]int64, len(items))
+	for _, l := range last {
+		next[l.ItemID] = l.Revision
+	}
+	revisions := make([]*domain.ItemRevision, len(items))
+	for i, item := range items {
+		revisions[i] = &domain.ItemRevision{
+			ID:       uuid.NewString(),
+			ItemID:   item.ID,
+			Revision: next[item.ID] + 1,
+			TenantID: item.TenantID,
+			OwnerID:  item.OwnerID,
+			Item:     item,
+		}
+	}
+	return tx.CreateInBatches(revisions, bulkBatchSize).Error
+}
+
+func (r *itemRepository) GetRevisions(ctx context.Context, id string, page domain.PageRequest) (*domain.Page[*domain.ItemRevision], error) {
+	db := conn(ctx, r.db).Model(&domain.ItemRevision{}).Scopes(visibleRevisions(ctx)).Where("item_revisions.item_id = ?", id)
+	revisions, err := findPage(db, "item_revisions", page, revisionOrder, func(revision *domain.ItemRevision) string { return revision.ID })
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return revisions, nil
+}
+
+func (r *itemRepository) GetRevision(ctx context.Context, id string, revision int64) (*domain.ItemRevision, error) {
+	var itemRevision domain.ItemRevision
+	err := conn(ctx, r.db).Scopes(visibleRevisions(ctx)).
+		First(&itemRevision, "item_revisions.item_id = ? AND item_revisions.revision = ?", id, revision).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &itemRevision, nil
+}
+
+// GetRevisionAt reports an item that had no revision yet at the given time as domain.ErrNotFound.
+func (r *itemRepository) GetRevisionAt(ctx context.Context, id string, at time.Time) (*domain.ItemRevision, error) {
+	var itemRevision domain.ItemRevision
+	err := conn(ctx, r.db).Scopes(visibleRevisions(ctx)).
+		Where("item_revisions.item_id = ? AND item_revisions.created_at <= ?", id, at.Local()).
+		Order("item_revisions.revision DESC").Take(&itemRevision).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &itemRevision, nil
+}
@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemRepository_Revisions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	item := &domain.Item{ID: id, Title: "Lamp", Description: "Teak", OwnerID: "user-1"}
+	require.NoError(t, repo.Create(ctx, item))
+	item.Title = "Desk lamp"
+	require.NoError(t, repo.Update(ctx, item))
+	require.NoError(t, properties.Create(ctx, &domain.ItemProperty{ID: uuid.NewString(), ItemID: id, Name: "color", Value: "red"}))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+
+	page, err := repo.GetRevisions(ctx, id, firstPage)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 4)
+	assert.Equal(t, int64(4), page.Total)
+	// Most recent first
+	for i, revision := range page.Items {
+		assert.Equal(t, int64(4-i), revision.Revision)
+		assert.Equal(t, id, revision.ItemID)
+	}
+
+	deleted := page.Items[0]
+	assert.True(t, deleted.Deleted())
+	assert.Equal(t, "Desk lamp", deleted.Item.Title)
+
+	withProperty := page.Items[1]
+	assert.False(t, withProperty.Deleted())
+	require.Len(t, withProperty.Item.ItemProperties, 1)
+	assert.Equal(t, "red", withProperty.Item.ItemProperties[0].Value)
+
+	first, err := repo.GetRevision(ctx, id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Lamp", first.Item.Title)
+	assert.Equal(t, int64(1), first.Item.Version)
+	assert.Empty(t, first.Item.ItemProperties)
+
+	_, err = repo.GetRevision(ctx, id, 5)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemRepository_GetRevisionAt(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	item := &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}
+	require.NoError(t, repo.Create(ctx, item))
+	item.Title = "Desk lamp"
+	require.NoError(t, repo.Update(ctx, item))
+	// Spread the revisions over two days
+	start := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, db.Model(&domain.ItemRevision{}).Where("revision = 1").Update("created_at", start).Error)
+	require.NoError(t, db.Model(&domain.ItemRevision{}).Where("revision = 2").Update("created_at", start.Add(24*time.Hour)).Error)
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		title string
+	}{
+		{"at the first revision", start, "Lamp"},
+		{"between the revisions", start.Add(time.Hour), "Lamp"},
+		{"after the last revision", time.Now(), "Desk lamp"},
+		{"in another time zone", start.Add(25 * time.Hour).In(time.FixedZone("UTC+5", 5*3600)), "Desk lamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revision, err := repo.GetRevisionAt(ctx, id, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, revision.Item.Title)
+		})
+	}
+
+	_, err := repo.GetRevisionAt(ctx, id, start.Add(-time.Hour))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemRepository_RevisionsVisibility(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+
+	id := uuid.NewString()
+	require.NoError(t, repo.Create(ownerCtx("user-1"), &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}))
+
+	// Revisions are visible to the owner and the admins of the tenant of the item only
+	page, err := repo.GetRevisions(ownerCtx("user-2"), id, firstPage)
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+	page, err = repo.GetRevisions(tenantCtx("globex", "admin-1", domain.RoleAdmin), id, firstPage)
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+	page, err = repo.GetRevisions(adminCtx(), id, firstPage)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	_, err = repo.GetRevision(ownerCtx("user-2"), id, 1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemRepository_PurgeDeletesRevisions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	require.NoError(t, repo.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	require.NoError(t, repo.Purge(adminCtx(), id))
+
+	page, err := repo.GetRevisions(adminCtx(), id, firstPage)
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+}
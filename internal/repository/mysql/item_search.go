@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// Weights of the matches in each searched column in the relevance of an item
+const (
+	searchTitleWeight       = 2.0
+	searchDescriptionWeight = 1.0
+	searchPropertiesWeight  = 1.0
+)
+
+// searchHit is the id and relevance score of an item matched by a search.
+type searchHit struct {
+	ID    string
+	Score float64
+}
+
+// searchHitOrder orders search hits by relevance, best first.
+var searchHitOrder = []orderBy[*searchHit]{{
+	sortColumn: sortColumn[*searchHit]{
+		column: "hits.score",
+		value: func(hit *searchHit) *string {
+			s := strconv.FormatFloat(hit.Score, 'g', -1, 64)
+			return &s
+		},
+		arg: func(s string) (any, error) { return strconv.ParseFloat(s, 64) },
+	},
+	desc: true,
+}}
+
+// searchTerms splits a search text into words, dropping the punctuation that
+// full-text query syntaxes give a meaning to.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Search finds the items visible to the caller that match any word of the
+// search text, using the MySQL FULLTEXT indexes or, on SQLite, the FTS5
+// index created by the migrations.
+func (r *itemRepository) Search(ctx context.Context, search domain.ItemSearch) (*domain.Page[*domain.ItemSearchResult], error) {
+	// Resolved here rather than inside the hits subquery, whose errors would not reach the query
+	if _, err := tenantOf(ctx); err != nil {
+		return nil, err
+	}
+	if _, _, err := visibleOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(search.Text)
+	if len(terms) == 0 {
+		return &domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{}}, nil
+	}
+
+	var hits *gorm.DB
+	if r.db.Dialector.Name() == "sqlite" {
+		hits = sqliteSearchHits(r.db, terms)
+	} else {
+		hits = mysqlSearchHits(r.db, terms)
+	}
+	// The hits are read from the items table, which does not hide soft-deleted items
+	hits = hits.Scopes(visibleItems(ctx)).Where("items.deleted_at IS NULL")
+
+	db := conn(ctx, r.db).Table("(?) AS hits", hits).Where("hits.score > 0")
+	page, err := findPage(db, "hits", search.Page, searchHitOrder, func(hit *searchHit) string { return hit.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{}, Total: page.Total, NextCursor: page.NextCursor}
+	if len(page.Items) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, len(page.Items))
+	for i, hit := range page.Items {
+		ids[i] = hit.ID
+	}
+	var items []*domain.Item
+	itemsDB := withItemOptions(conn(ctx, r.db).Scopes(visibleItems(ctx)), search.Options)
+	if err := itemsDB.Find(&items, "items.id IN ?", ids).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for _, hit := range page.Items {
+		// Items deleted since the hits were read are left out
+		if item, ok := byID[hit.ID]; ok {
+			result.Items = append(result.Items, &domain.ItemSearchResult{Item: item, Score: hit.Score})
+		}
+	}
+	return result, nil
+}
+
+// mysqlSearchHits scores every item by the natural language relevance of its
+// title, description and property values to the terms.
+func mysqlSearchHits(db *gorm.DB, terms []string) *gorm.DB {
+	text := strings.Join(terms, " ")
+	score := "? * MATCH(items.title) AGAINST (? IN NATURAL LANGUAGE MODE)" +
+		" + ? * MATCH(items.description) AGAINST (? IN NATURAL LANGUAGE MODE)" +
+		" + ? * COALESCE((SELECT SUM(MATCH(item_properties.value) AGAINST (? IN NATURAL LANGUAGE MODE))" +
+		" FROM item_properties WHERE item_properties.item_id = items.id AND item_properties.deleted_at IS NULL), 0)"
+	return db.Session(&gorm.Session{NewDB: true}).Table("items").
+		Select("items.id, "+score+" AS score",
+			searchTitleWeight, text, searchDescriptionWeight, text, searchPropertiesWeight, text)
+}
+
+// sqliteSearchHits scores the items matching any of the terms by the BM25
+// rank of their row in the items_search FTS5 table.
+func sqliteSearchHits(db *gorm.DB, terms []string) *gorm.DB {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		// Terms hold letters and digits only, so quoting them needs no escaping
+		quoted[i] = `"` + term + `"`
+	}
+	// bm25 is negative, lower for better matches
+	return db.Session(&gorm.Session{NewDB: true}).Table("items_search").
+		Joins("JOIN items ON items.rowid = items_search.rowid").
+		Select("items.id, -bm25(items_search, ?, ?, ?) AS score",
+			searchTitleWeight, searchDescriptionWeight, searchPropertiesWeight).
+		Where("items_search MATCH ?", strings.Join(quoted, " OR "))
+}
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/database"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupSearchDB returns an SQLite database migrated like the SQLite fallback of
+// NewGormDB, which search needs for its FTS5 index.
+func setupSearchDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own
+	sqlDB.SetMaxOpenConns(1)
+
+	var fts5 bool
+	require.NoError(t, db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error)
+	if !fts5 {
+		t.Skip("SQLite was built without FTS5, run the tests with -tags sqlite_fts5")
+	}
+
+	require.NoError(t, database.NewMigrator(sqlDB, "sqlite3").Up())
+	return db
+}
+
+// createSearchItem stores an item owned by owner with the given properties and returns its ID
+func createSearchItem(t *testing.T, repo domain.ItemRepository, owner string, title string, description string, properties map[string]string) string {
+	id := uuid.New().String()
+	item := &domain.Item{ID: id, Title: title, Description: description, OwnerID: owner}
+	for name, value := range properties {
+		item.ItemProperties = append(item.ItemProperties, &domain.ItemProperty{ID: uuid.New().String(), Name: name, Value: value})
+	}
+	require.NoError(t, repo.Create(ownerCtx(owner), item))
+	return id
+}
+
+// hitTitles returns the titles of the items of a page of search results
+func hitTitles(page *domain.Page[*domain.ItemSearchResult]) []string {
+	result := []string{}
+	for _, hit := range page.Items {
+		result = append(result, hit.Item.Title)
+	}
+	return result
+}
+
+func TestItemRepository_Search_Ranking(t *testing.T) {
+	repo := NewItemRepository(setupSearchDB(t))
+	createSearchItem(t, repo, "alice", "Garden chair", "Folding chair made of teak", nil)
+	createSearchItem(t, repo, "alice", "Teak table", "Seats six", map[string]string{"wood": "teak"})
+	createSearchItem(t, repo, "alice", "Office lamp", "Adjustable arm", map[string]string{"finish": "brass"})
+
+	page, err := repo.Search(ownerCtx("alice"), domain.ItemSearch{Text: "teak", Page: firstPage})
+	require.NoError(t, err)
+	// A match in the title outweighs one in the description
+	assert.Equal(t, []string{"Teak table", "Garden chair"}, hitTitles(page))
+	assert.Equal(t, int64(2), page.Total)
+	assert.Greater(t, page.Items[0].Score, page.Items[1].Score)
+	assert.Greater(t, page.Items[1].Score, 0.0)
+
+	// Property values are searched, and any word of the text matches
+	page, err = repo.Search(ownerCtx("alice"), domain.ItemSearch{Text: "brass, (granite)", Page: firstPage})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Office lamp"}, hitTitles(page))
+
+	page, err = repo.Search(ownerCtx("alice"), domain.ItemSearch{Text: "marble", Page: firstPage})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Equal(t, int64(0), page.Total)
+}
+
+func TestItemRepository_Search_FollowsChanges(t *testing.T) {
+	db := setupSearchDB(t)
+	repo := NewItemRepository(db)
+	propertyRepo := NewItemPropertyRepository(db)
+	ctx := ownerCtx("alice")
+	id := createSearchItem(t, repo, "alice", "Desk", "Standing desk", nil)
+
+	require.NoError(t, repo.Update(ctx, &domain.Item{ID: id, Title: "Walnut desk", Description: "Standing desk"}))
+	page, err := repo.Search(ctx, domain.ItemSearch{Text: "walnut", Page: firstPage})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Walnut desk"}, hitTitles(page))
+
+	property := &domain.ItemProperty{ID: uuid.New().String(), ItemID: id, Name: "color", Value: "ivory"}
+	require.NoError(t, propertyRepo.Create(ctx, property))
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "ivory", Page: firstPage})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	require.NoError(t, propertyRepo.Delete(ctx, id, property.ID, 0))
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "ivory", Page: firstPage})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "walnut", Page: firstPage})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	// Deleted items are not counted either
+	assert.Equal(t, int64(0), page.Total)
+
+	require.NoError(t, repo.Restore(ctx, id))
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "walnut", Page: firstPage})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Walnut desk"}, hitTitles(page))
+}
+
+func TestItemRepository_Search_FollowsBulkChanges(t *testing.T) {
+	db := setupSearchDB(t)
+	repo := NewItemRepository(db)
+	ctx := ownerCtx("alice")
+
+	items := []*domain.Item{
+		{ID: uuid.New().String(), Title: "Oak shelf", OwnerID: "alice"},
+		{ID: uuid.New().String(), Title: "Oak table", OwnerID: "alice"},
+	}
+	require.NoError(t, repo.CreateBatch(ctx, items))
+	page, err := repo.Search(ctx, domain.ItemSearch{Text: "oak", Page: firstPage})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+
+	// The upserts of a bulk update reindex the items like an update
+	_, err = repo.UpdateBatch(ctx, []*domain.Item{{ID: items[0].ID, Title: "Cherry shelf"}})
+	require.NoError(t, err)
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "cherry", Page: firstPage})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cherry shelf"}, hitTitles(page))
+
+	_, err = repo.DeleteBatch(ctx, []*domain.Item{{ID: items[1].ID}})
+	require.NoError(t, err)
+	page, err = repo.Search(ctx, domain.ItemSearch{Text: "oak", Page: firstPage})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+}
+
+func TestItemRepository_Search_Visibility(t *testing.T) {
+	repo := NewItemRepository(setupSearchDB(t))
+	createSearchItem(t, repo, "alice", "Alice's lantern", "", nil)
+	createSearchItem(t, repo, "bob", "Bob's lantern", "", nil)
+
+	page, err := repo.Search(ownerCtx("alice"), domain.ItemSearch{Text: "lantern", Page: firstPage})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice's lantern"}, hitTitles(page))
+
+	page, err = repo.Search(adminCtx(), domain.ItemSearch{Text: "lantern", Page: firstPage})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+
+	page, err = repo.Search(tenantCtx("other", "alice"), domain.ItemSearch{Text: "lantern", Page: firstPage})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+}
+
+func TestItemRepository_Search_CursorPagination(t *testing.T) {
+	repo := NewItemRepository(setupSearchDB(t))
+	for i := 0; i < 5; i++ {
+		// Equal scores, which the cursor tells apart by ID
+		createSearchItem(t, repo, "alice", "Candle", "", nil)
+	}
+	createSearchItem(t, repo, "alice", "Candle candle", "", nil)
+
+	var ids []string
+	page := domain.PageRequest{Size: 2}
+	for {
+		result, err := repo.Search(ownerCtx("alice"), domain.ItemSearch{Text: "candle", Page: page})
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), result.Total)
+		for _, hit := range result.Items {
+			ids = append(ids, hit.Item.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	assert.Len(t, ids, 6)
+	seen := map[string]bool{}
+	for _, id := range ids {
+		assert.False(t, seen[id], "item %s returned twice", id)
+		seen[id] = true
+	}
+}
@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// sortColumn is a column a list of T can be ordered by.
+type sortColumn[T any] struct {
+	// column is the qualified column name, e.g. "items.title"
+	column string
+	// value returns the value of the column in a row as it is stored in cursors, nil for NULL
+	value func(T) *string
+	// arg converts a value stored in a cursor back to a query argument
+	arg func(string) (any, error)
+}
+
+// orderBy orders a list by a column, ascending unless desc is set.
+type orderBy[T any] struct {
+	sortColumn[T]
+	desc bool
+}
+
+// orderKey identifies an ordering, so that a cursor is only used with the ordering it was issued for.
+func orderKey[T any](order []orderBy[T]) string {
+	keys := make([]string, len(order))
+	for i, o := range order {
+		keys[i] = o.column
+		if o.desc {
+			keys[i] = "-" + o.column
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// pageCursor is the position a cursor points after: the values of the sort
+// columns and the id of the last row of a page. It is sent to clients
+// base64url-encoded and is opaque to them.
+type pageCursor struct {
+	Order  string    `json:"order,omitempty"`
+	Values []*string `json:"values,omitempty"`
+	ID     string    `json:"id"`
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, domain.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return cursor, domain.ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// afterCursor returns the condition matching the rows ordered after cursor,
+// comparing the sort columns in turn and the id last. NULLs sort before any
+// value, as they do in both MySQL and SQLite.
+func afterCursor[T any](table string, order []orderBy[T], cursor pageCursor) (string, []any, error) {
+	if cursor.Order != orderKey(order) || len(cursor.Values) != len(order) {
+		return "", nil, domain.ErrInvalidCursor
+	}
+
+	var terms []string
+	var args []any
+	// Conditions matching the rows that tie with the cursor on every column so far
+	var ties []string
+	var tieArgs []any
+	for i, o := range order {
+		var arg any
+		if value := cursor.Values[i]; value != nil {
+			var err error
+			if arg, err = o.arg(*value); err != nil {
+				return "", nil, domain.ErrInvalidCursor
+			}
+		}
+
+		var after string
+		switch {
+		case arg == nil && !o.desc:
+			after = o.column + " IS NOT NULL"
+		case arg == nil && o.desc:
+			// Nothing sorts after NULL in descending order
+		case !o.desc:
+			after = o.column + " > ?"
+		default:
+			after = "(" + o.column + " < ? OR " + o.column + " IS NULL)"
+		}
+		if after != "" {
+			terms = append(terms, "("+strings.Join(append(append([]string{}, ties...), after), " AND ")+")")
+			args = append(args, tieArgs...)
+			if arg != nil {
+				args = append(args, arg)
+			}
+		}
+
+		if arg == nil {
+			ties = append(ties, o.column+" IS NULL")
+		} else {
+			ties = append(ties, o.column+" = ?")
+			tieArgs = append(tieArgs, arg)
+		}
+	}
+	terms = append(terms, "("+strings.Join(append(ties, table+".id > ?"), " AND ")+")")
+	args = append(append(args, tieArgs...), cursor.ID)
+
+	return "(" + strings.Join(terms, " OR ") + ")", args, nil
+}
+
+// findPage loads the requested page of the rows of table matched by db,
+// sorted by order and then by id. id returns the primary key of a row.
+func findPage[T any](db *gorm.DB, table string, page domain.PageRequest, order []orderBy[T], id func(T) string) (*domain.Page[T], error) {
+	result := &domain.Page[T]{Items: []T{}}
+	if err := db.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	for _, o := range order {
+		if o.desc {
+			db = db.Order(o.column + " DESC")
+		} else {
+			db = db.Order(o.column + " ASC")
+		}
+	}
+	db = db.Order(table + ".id ASC")
+
+	if page.Number > 0 {
+		if err := db.Offset(page.Offset()).Limit(page.Size).Find(&result.Items).Error; err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		condition, args, err := afterCursor(table, order, cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(condition, args...)
+	}
+	// Fetch one extra row to find out whether there is a following page
+	if err := db.Limit(page.Size + 1).Find(&result.Items).Error; err != nil {
+		return nil, err
+	}
+	if len(result.Items) > page.Size {
+		result.Items = result.Items[:page.Size]
+		last := result.Items[page.Size-1]
+		next := pageCursor{Order: orderKey(order), ID: id(last)}
+		for _, o := range order {
+			next.Values = append(next.Values, o.value(last))
+		}
+		result.NextCursor = encodeCursor(next)
+	}
+	return result, nil
+}
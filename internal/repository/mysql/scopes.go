@@ -0,0 +1,103 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// tenantOf returns the tenant resolved for the request in ctx.
+func tenantOf(ctx context.Context) (string, error) {
+	tenantID, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return "", domain.ErrTenantRequired
+	}
+	return tenantID, nil
+}
+
+// inTenant restricts a query on table to the rows of the tenant in ctx.
+// Without a tenant the query fails instead of returning every tenant's rows.
+func inTenant(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantID, err := tenantOf(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		return db.Where(table+".tenant_id = ?", tenantID)
+	}
+}
+
+// visibleOwner resolves which item owner the principal in ctx is restricted to.
+// Admins are not restricted (all is true). Without a principal nothing is visible.
+func visibleOwner(ctx context.Context) (ownerID string, all bool, err error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return "", false, domain.ErrUnauthenticated
+	}
+	if principal.HasRole(domain.RoleAdmin) {
+		return "", true, nil
+	}
+	return principal.Subject, false, nil
+}
+
+// ownedItems restricts an items query to the items owned by the principal in ctx.
+func ownedItems(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		ownerID, all, err := visibleOwner(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		if all {
+			return db
+		}
+		return db.Where("items.owner_id = ?", ownerID)
+	}
+}
+
+// visibleItems restricts an items query to the items of the tenant in ctx
+// that the principal in ctx may see.
+func visibleItems(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(inTenant(ctx, "items"), ownedItems(ctx))
+	}
+}
+
+// visibleItemProperties restricts an item_properties query to the properties
+// of the items visible to the request in ctx (see visibleItems) that are not deleted.
+func visibleItemProperties(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// Resolved here rather than inside the subquery, whose errors would not reach db
+		tenantID, err := tenantOf(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		ownerID, all, err := visibleOwner(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		// Properties of soft-deleted items are deleted with them, but the table does not hide them
+		visible := db.Session(&gorm.Session{NewDB: true}).Table("items").Select("items.id").
+			Where("items.tenant_id = ? AND items.deleted_at IS NULL", tenantID)
+		if !all {
+			visible = visible.Where("items.owner_id = ?", ownerID)
+		}
+		return db.Where("item_properties.item_id IN (?)", visible)
+	}
+}
+
+// atVersion restricts a write on table to the row at version, the version the
+// caller expects to change. Version 0 does not restrict the write.
+func atVersion(table string, version int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if version == 0 {
+			return db
+		}
+		return db.Where(table+".version = ?", version)
+	}
+}
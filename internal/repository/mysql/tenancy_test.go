@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mysqlDriver "gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testMySQLDSNEnv names the environment variable holding the DSN of a MySQL
+// database to run the tenant isolation tests against, e.g.
+// "user:pass@tcp(127.0.0.1:3306)/test?charset=utf8mb4&parseTime=True&loc=Local&clientFoundRows=true".
+const testMySQLDSNEnv = "TEST_MYSQL_DSN"
+
+// isolationDBs returns the databases the tenant isolation tests run against:
+// an in-memory SQLite database and, if TEST_MYSQL_DSN is set, a MySQL database.
+func isolationDBs(t *testing.T) map[string]*gorm.DB {
+	dbs := map[string]*gorm.DB{}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	dbs["SQLite"] = db
+
+	if dsn := os.Getenv(testMySQLDSNEnv); dsn != "" {
+		db, err := gorm.Open(mysqlDriver.Open(dsn), &gorm.Config{})
+		require.NoError(t, err)
+		dbs["MySQL"] = db
+	} else {
+		t.Logf("%s not set, skipping MySQL", testMySQLDSNEnv)
+	}
+
+	for _, db := range dbs {
+		require.NoError(t, db.AutoMigrate(&domain.Item{}, &domain.ItemProperty{}, &domain.APIKey{}, &domain.AuditEvent{}, &domain.ItemRevision{}))
+	}
+	return dbs
+}
+
+// newTestTenants returns two tenant IDs unique to this run, and removes their rows once the test ends.
+func newTestTenants(t *testing.T, db *gorm.DB) (string, string) {
+	tenantA := "tenant-a-" + uuid.New().String()
+	tenantB := "tenant-b-" + uuid.New().String()
+
+	t.Cleanup(func() {
+		tenants := []string{tenantA, tenantB}
+		items := db.Model(&domain.Item{}).Select("id").Where("tenant_id IN ?", tenants)
+		db.Where("item_id IN (?)", items).Delete(&domain.ItemProperty{})
+		db.Where("tenant_id IN ?", tenants).Delete(&domain.Item{})
+		db.Where("tenant_id IN ?", tenants).Delete(&domain.APIKey{})
+	})
+	return tenantA, tenantB
+}
+
+func TestTenantIsolation_Items(t *testing.T) {
+	for name, db := range isolationDBs(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := NewItemRepository(db)
+			tenantA, tenantB := newTestTenants(t, db)
+
+			// The same subject exists in both tenants
+			aliceA := tenantCtx(tenantA, "alice")
+			aliceB := tenantCtx(tenantB, "alice")
+			adminB := tenantCtx(tenantB, "admin-1", domain.RoleAdmin)
+
+			id := uuid.New().String()
+			item := &domain.Item{ID: id, Title: "Tenant A Item", OwnerID: "alice"}
+			require.NoError(t, repo.Create(aliceA, item))
+			assert.Equal(t, tenantA, item.TenantID)
+
+			for role, other := range map[string]context.Context{"Owner": aliceB, "Admin": adminB} {
+				t.Run(role, func(t *testing.T) {
+					page, err := repo.GetAll(other, domain.ItemQuery{Page: firstPage})
+					assert.NoError(t, err)
+					assert.Len(t, page.Items, 0)
+
+					_, err = repo.GetByID(other, id, domain.QueryOptions{})
+					assert.ErrorIs(t, err, domain.ErrNotFound)
+
+					err = repo.Update(other, &domain.Item{ID: id, Title: "Hijacked"})
+					assert.ErrorIs(t, err, domain.ErrNotFound)
+
+					assert.ErrorIs(t, repo.Delete(other, id, 0), domain.ErrNotFound)
+				})
+			}
+
+			found, err := repo.GetByID(aliceA, id, domain.QueryOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "Tenant A Item", found.Title)
+			assert.Equal(t, tenantA, found.TenantID)
+		})
+	}
+}
+
+func TestTenantIsolation_ItemProperties(t *testing.T) {
+	for name, db := range isolationDBs(t) {
+		t.Run(name, func(t *testing.T) {
+			itemRepo := NewItemRepository(db)
+			propertyRepo := NewItemPropertyRepository(db)
+			tenantA, tenantB := newTestTenants(t, db)
+
+			adminA := tenantCtx(tenantA, "admin-1", domain.RoleAdmin)
+			adminB := tenantCtx(tenantB, "admin-1", domain.RoleAdmin)
+
+			itemID := uuid.New().String()
+			require.NoError(t, itemRepo.Create(adminA, &domain.Item{ID: itemID, Title: "Tenant A Item", OwnerID: "alice"}))
+
+			propertyID := uuid.New().String()
+			require.NoError(t, propertyRepo.Create(adminA, &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "red"}))
+
+			properties, err := propertyRepo.GetAllByItemID(adminB, itemID, firstPage, domain.QueryOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, properties.Items, 0)
+
+			_, err = propertyRepo.GetByID(adminB, itemID, propertyID, domain.QueryOptions{})
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			err = propertyRepo.Create(adminB, &domain.ItemProperty{ID: uuid.New().String(), ItemID: itemID, Name: "size", Value: "large"})
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			err = propertyRepo.Update(adminB, &domain.ItemProperty{ID: propertyID, ItemID: itemID, Name: "color", Value: "blue"})
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			assert.ErrorIs(t, propertyRepo.Delete(adminB, itemID, propertyID, 0), domain.ErrNotFound)
+
+			properties, err = propertyRepo.GetAllByItemID(adminA, itemID, firstPage, domain.QueryOptions{})
+			assert.NoError(t, err)
+			if assert.Len(t, properties.Items, 1) {
+				assert.Equal(t, "red", properties.Items[0].Value)
+			}
+		})
+	}
+}
+
+func TestTenantIsolation_APIKeys(t *testing.T) {
+	for name, db := range isolationDBs(t) {
+		t.Run(name, func(t *testing.T) {
+			repo := NewAPIKeyRepository(db)
+			tenantA, tenantB := newTestTenants(t, db)
+
+			adminA := tenantCtx(tenantA, "admin-1", domain.RoleAdmin)
+			adminB := tenantCtx(tenantB, "admin-1", domain.RoleAdmin)
+
+			id := uuid.New().String()
+			prefix := uuid.New().String()[:8]
+			apiKey := &domain.APIKey{ID: id, Prefix: prefix, KeyHash: "hash", Owner: "billing-service", Scopes: []string{}, CreatedAt: time.Now()}
+			require.NoError(t, repo.Create(adminA, apiKey))
+
+			apiKeys, err := repo.GetAll(adminB)
+			assert.NoError(t, err)
+			assert.Len(t, apiKeys, 0)
+
+			_, err = repo.GetByID(adminB, id)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			// Authentication looks keys up before the tenant is known, and binds the caller to the key's tenant
+			found, err := repo.GetByPrefix(adminB, prefix)
+			require.NoError(t, err)
+			assert.Equal(t, tenantA, found.TenantID)
+		})
+	}
+}
+
+func TestTenantIsolation_RequiresTenant(t *testing.T) {
+	for name, db := range isolationDBs(t) {
+		t.Run(name, func(t *testing.T) {
+			itemRepo := NewItemRepository(db)
+			propertyRepo := NewItemPropertyRepository(db)
+			apiKeyRepo := NewAPIKeyRepository(db)
+
+			// Authenticated, but no tenant was resolved
+			ctx := domain.ContextWithPrincipal(t.Context(), &domain.Principal{Subject: "admin-1", Roles: []string{domain.RoleAdmin}})
+			id := uuid.New().String()
+
+			assert.ErrorIs(t, itemRepo.Create(ctx, &domain.Item{ID: id, Title: "No Tenant"}), domain.ErrTenantRequired)
+
+			_, err := itemRepo.GetAll(ctx, domain.ItemQuery{Page: firstPage})
+			assert.ErrorIs(t, err, domain.ErrTenantRequired)
+
+			_, err = itemRepo.GetByID(ctx, id, domain.QueryOptions{})
+			assert.ErrorIs(t, err, domain.ErrTenantRequired)
+
+			_, err = propertyRepo.GetAllByItemID(ctx, id, firstPage, domain.QueryOptions{})
+			assert.ErrorIs(t, err, domain.ErrTenantRequired)
+
+			_, err = apiKeyRepo.GetAll(ctx)
+			assert.ErrorIs(t, err, domain.ErrTenantRequired)
+		})
+	}
+}
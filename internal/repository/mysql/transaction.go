@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"gorm.io/gorm"
+)
+
+type transactionContextKey struct{}
+
+type transactionManager struct {
+	db *gorm.DB
+}
+
+func NewTransactionManager(db *gorm.DB) domain.TransactionManager {
+	return &transactionManager{db: db}
+}
+
+// WithinTransaction runs fn in a GORM transaction carried by the context fn is given,
+// which the repositories of this package run their queries in (see conn). The writes
+// the repositories make in transactions of their own become savepoints of it.
+func (m *transactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	txCtx, hooks := domain.ContextWithCommitHooks(ctx)
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(txCtx, transactionContextKey{}, tx))
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	hooks.Run()
+	return nil
+}
+
+// conn returns the connection to run the queries of ctx on: the transaction started
+// by WithinTransaction when ctx carries one, db otherwise.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
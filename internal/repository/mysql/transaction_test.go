@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionManager_Commits(t *testing.T) {
+	db := setupTestDB(t)
+	transactions := NewTransactionManager(db)
+	items := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	committed := false
+	err := transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, items.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}))
+		// The property sees the item created in the transaction
+		require.NoError(t, properties.Create(ctx, &domain.ItemProperty{ID: uuid.NewString(), ItemID: id, Name: "color", Value: "red"}))
+		domain.AfterCommit(ctx, func() { committed = true })
+		assert.False(t, committed)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, committed)
+	item, err := items.GetByID(ctx, id, domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}})
+	require.NoError(t, err)
+	assert.Len(t, item.ItemProperties, 1)
+}
+
+func TestTransactionManager_RollsBack(t *testing.T) {
+	db := setupTestDB(t)
+	transactions := NewTransactionManager(db)
+	items := NewItemRepository(db)
+	properties := NewItemPropertyRepository(db)
+	audit := NewAuditRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	committed := false
+	err := transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, items.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"}))
+		domain.AfterCommit(ctx, func() { committed = true })
+		// The property of another item fails, undoing the item
+		return properties.Create(ctx, &domain.ItemProperty{ID: uuid.NewString(), ItemID: uuid.NewString(), Name: "color", Value: "red"})
+	})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.False(t, committed)
+	_, err = items.GetByID(ctx, id, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	// Along with its revision and audit event
+	revisions, err := items.GetRevisions(ctx, id, firstPage)
+	require.NoError(t, err)
+	assert.Zero(t, revisions.Total)
+	events, err := audit.GetAll(adminCtx(), domain.AuditQuery{Page: firstPage})
+	require.NoError(t, err)
+	assert.Zero(t, events.Total)
+}
+
+func TestTransactionManager_Nested(t *testing.T) {
+	db := setupTestDB(t)
+	transactions := NewTransactionManager(db)
+	items := NewItemRepository(db)
+	ctx := ownerCtx("user-1")
+
+	id := uuid.NewString()
+	failure := errors.New("outer failure")
+	err := transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The inner unit of work joins the outer one, and is undone with it
+		require.NoError(t, transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+			return items.Create(ctx, &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1"})
+		}))
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	_, err = items.GetByID(ctx, id, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
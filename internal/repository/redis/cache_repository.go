@@ -8,6 +8,9 @@ import (
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
 )
 
+// unlockScript deletes a lock only while it holds the token of the caller, in one atomic step.
+const unlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`
+
 type cacheRepository struct {
 	client redis.Cmdable
 }
@@ -40,3 +43,12 @@ func (r *cacheRepository) Exists(ctx context.Context, key string) (bool, error)
 func (r *cacheRepository) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
+
+// Lock sets key to token unless it exists (SET NX), expiring after ttl.
+func (r *cacheRepository) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, token, ttl).Result()
+}
+
+func (r *cacheRepository) Unlock(ctx context.Context, key string, token string) error {
+	return r.client.Eval(ctx, unlockScript, []string{key}, token).Err()
+}
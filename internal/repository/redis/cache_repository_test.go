@@ -96,3 +96,34 @@ func TestCacheRepository_Ping(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCacheRepository_Lock(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	repo := NewCacheRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectSetNX("lock-key", "token-1", time.Minute).SetVal(true)
+	mock.ExpectSetNX("lock-key", "token-2", time.Minute).SetVal(false)
+
+	locked, err := repo.Lock(ctx, "lock-key", "token-1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	locked, err = repo.Lock(ctx, "lock-key", "token-2", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCacheRepository_Unlock(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	repo := NewCacheRepository(db)
+	ctx := context.Background()
+
+	// The lock is only deleted while it holds the token
+	mock.ExpectEval(unlockScript, []string{"lock-key"}, "token-1").SetVal(int64(1))
+
+	err := repo.Unlock(ctx, "lock-key", "token-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,27 +2,116 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
 
+	"github.com/gadz82/go-api-boilerplate/internal/config"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 )
 
+// NewServer creates the HTTP server of the router from the configuration. It serves TLS
+// when a certificate and key are configured, and with a client CA also requires clients
+// to present a certificate it signed.
+func NewServer(r *gin.Engine, cfg *config.Config) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              cfg.ServerAddr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" {
+		if cfg.TLSClientCAFile != "" {
+			return nil, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return srv, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	srv.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		data, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificate found in %s", cfg.TLSClientCAFile)
+		}
+		srv.TLSConfig.ClientCAs = pool
+		srv.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return srv, nil
+}
+
 // RegisterHooks registers the HTTP server lifecycle hooks with the fx application.
-// It starts the server on application start and logs when the server stops.
-func RegisterHooks(lc fx.Lifecycle, r *gin.Engine) {
+// The server starts listening when the application starts, so that an address that
+// cannot be listened on fails the start. When the application stops, the server
+// stops accepting connections and waits up to the configured grace period for the
+// requests in flight, then closes the connections left.
+func RegisterHooks(lc fx.Lifecycle, shutdowner fx.Shutdowner, srv *http.Server, cfg *config.Config) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			log.Println("Server starting on :8080")
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", srv.Addr, err)
+			}
+			scheme := "http"
+			if srv.TLSConfig != nil {
+				scheme = "https"
+			}
+			log.Printf("Server listening on %s (%s)", ln.Addr(), scheme)
+
 			go func() {
-				if err := r.Run(":8080"); err != nil {
-					log.Printf("Failed to start server: %v", err)
+				var err error
+				if srv.TLSConfig != nil {
+					// The certificate is already in TLSConfig
+					err = srv.ServeTLS(ln, "", "")
+				} else {
+					err = srv.Serve(ln)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Printf("Server failed: %v", err)
+					// Stop the application rather than keep it running without a server
+					if err := shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+						log.Printf("Failed to stop the application: %v", err)
+					}
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			log.Println("Server stopping")
+			log.Printf("Server stopping, waiting up to %s for requests in flight", cfg.ShutdownTimeout)
+			shutdownCtx, cancel := context.WithTimeout(ctx, cfg.ShutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				// The grace period is over, the connections left are dropped
+				if closeErr := srv.Close(); closeErr != nil {
+					log.Printf("Failed to close server: %v", closeErr)
+				}
+				return fmt.Errorf("server did not stop gracefully: %w", err)
+			}
+			log.Println("Server stopped")
 			return nil
 		},
 	})
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// stubShutdowner records whether the application was asked to stop
+type stubShutdowner struct {
+	called bool
+}
+
+func (s *stubShutdowner) Shutdown(...fx.ShutdownOption) error {
+	s.called = true
+	return nil
+}
+
+// freeAddr returns a local address nothing listens on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return addr
+}
+
+func newTestConfig(addr string) *config.Config {
+	return &config.Config{
+		ServerAddr:        addr,
+		ReadHeaderTimeout: time.Second,
+		ReadTimeout:       2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+		MaxHeaderBytes:    4096,
+		ShutdownTimeout:   5 * time.Second,
+	}
+}
+
+// writeCertificate writes a certificate for 127.0.0.1, and its key, signed by parent
+// (self-signed when nil), and returns them with the paths of their PEM files.
+func writeCertificate(t *testing.T, dir string, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return cert, key, certFile, keyFile
+}
+
+func TestNewServer(t *testing.T) {
+	cfg := newTestConfig("127.0.0.1:8443")
+
+	srv, err := NewServer(gin.New(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8443", srv.Addr)
+	assert.Equal(t, time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 3*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 4*time.Second, srv.IdleTimeout)
+	assert.Equal(t, 4096, srv.MaxHeaderBytes)
+	assert.Nil(t, srv.TLSConfig)
+}
+
+func TestNewServer_TLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFile, _ := writeCertificate(t, dir, "ca", nil, nil)
+	_, _, certFile, keyFile := writeCertificate(t, dir, "server", ca, caKey)
+
+	cfg := newTestConfig("127.0.0.1:8443")
+	cfg.TLSCertFile, cfg.TLSKeyFile = certFile, keyFile
+	srv, err := NewServer(gin.New(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, srv.TLSConfig)
+	assert.Len(t, srv.TLSConfig.Certificates, 1)
+	assert.Equal(t, tls.NoClientCert, srv.TLSConfig.ClientAuth)
+
+	cfg.TLSClientCAFile = caFile
+	srv, err = NewServer(gin.New(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, srv.TLSConfig.ClientAuth)
+	assert.NotNil(t, srv.TLSConfig.ClientCAs)
+}
+
+func TestNewServer_InvalidTLS(t *testing.T) {
+	dir := t.TempDir()
+	_, _, certFile, keyFile := writeCertificate(t, dir, "server", nil, nil)
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		caFile   string
+	}{
+		{name: "Certificate Without Key", certFile: certFile},
+		{name: "Key Without Certificate", keyFile: keyFile},
+		{name: "Client CA Without Certificate", caFile: certFile},
+		{name: "Missing Certificate File", certFile: filepath.Join(dir, "missing.crt"), keyFile: keyFile},
+		{name: "Missing Client CA File", certFile: certFile, keyFile: keyFile, caFile: filepath.Join(dir, "missing.crt")},
+		{name: "Client CA Without Certificates", certFile: certFile, keyFile: keyFile, caFile: keyFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig("127.0.0.1:8443")
+			cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile = tt.certFile, tt.keyFile, tt.caFile
+
+			_, err := NewServer(gin.New(), cfg)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRegisterHooks_AddressInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	cfg := newTestConfig(ln.Addr().String())
+	srv, err := NewServer(gin.New(), cfg)
+	require.NoError(t, err)
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, &stubShutdowner{}, srv, cfg)
+
+	assert.Error(t, lc.Start(context.Background()))
+}
+
+func TestRegisterHooks_GracefulShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started, release := make(chan struct{}), make(chan struct{})
+	r := gin.New()
+	r.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	cfg := newTestConfig(freeAddr(t))
+	srv, err := NewServer(r, cfg)
+	require.NoError(t, err)
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, &stubShutdowner{}, srv, cfg)
+	lc.RequireStart()
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + cfg.ServerAddr + "/slow")
+		if err != nil {
+			status <- 0
+			return
+		}
+		defer resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- lc.Stop(context.Background()) }()
+
+	// New connections are refused while the request in flight completes
+	require.Eventually(t, func() bool {
+		_, err := net.Dial("tcp", cfg.ServerAddr)
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	close(release)
+
+	assert.Equal(t, http.StatusOK, <-status)
+	assert.NoError(t, <-stopped)
+}
+
+func TestRegisterHooks_ShutdownTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	r := gin.New()
+	r.GET("/stuck", func(c *gin.Context) {
+		close(started)
+		<-release
+	})
+
+	cfg := newTestConfig(freeAddr(t))
+	cfg.ShutdownTimeout = 50 * time.Millisecond
+	srv, err := NewServer(r, cfg)
+	require.NoError(t, err)
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, &stubShutdowner{}, srv, cfg)
+	lc.RequireStart()
+
+	failed := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + cfg.ServerAddr + "/stuck")
+		if err == nil {
+			resp.Body.Close()
+		}
+		failed <- err
+	}()
+	<-started
+
+	// The connection of the request is dropped once the grace period is over
+	assert.Error(t, lc.Stop(context.Background()))
+	assert.Error(t, <-failed)
+}
+
+func TestRegisterHooks_MutualTLS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	ca, caKey, caFile, _ := writeCertificate(t, dir, "ca", nil, nil)
+	_, _, certFile, keyFile := writeCertificate(t, dir, "server", ca, caKey)
+	_, _, clientCertFile, clientKeyFile := writeCertificate(t, dir, "client", ca, caKey)
+
+	r := gin.New()
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	cfg := newTestConfig(freeAddr(t))
+	cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile = certFile, keyFile, caFile
+	srv, err := NewServer(r, cfg)
+	require.NoError(t, err)
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, &stubShutdowner{}, srv, cfg)
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(certificates ...tls.Certificate) (*http.Response, error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates}}}
+		defer client.CloseIdleConnections()
+		return client.Get("https://" + cfg.ServerAddr + "/ping")
+	}
+
+	// A client without a certificate is refused
+	_, err = get()
+	assert.Error(t, err)
+
+	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	require.NoError(t, err)
+	resp, err := get(clientCert)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+)
+
+type auditService struct {
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditService(auditRepo domain.AuditRepository) domain.AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// ListAuditEvents retrieves a page of the audit log of the tenant in ctx. The log is
+// not cached: every change to an item or an item property appends to it.
+func (s *auditService) ListAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.Page[*domain.AuditEvent], error) {
+	return s.auditRepo.GetAll(ctx, query)
+}
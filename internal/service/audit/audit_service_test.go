@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is a mock implementation of domain.AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) GetAll(ctx context.Context, query domain.AuditQuery) (*domain.Page[*domain.AuditEvent], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.AuditEvent]), args.Error(1)
+}
+
+func TestAuditService_ListAuditEvents(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	query := domain.AuditQuery{ResourceType: domain.ResourceItems, Page: domain.PageRequest{Size: 20}}
+	expected := &domain.Page[*domain.AuditEvent]{Items: []*domain.AuditEvent{{ID: "1", Action: domain.AuditActionCreate}}, Total: 1}
+
+	repo.On("GetAll", mock.Anything, query).Return(expected, nil)
+
+	page, err := svc.ListAuditEvents(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, page)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// Cache key prefix for API key lookups by prefix
+	apiKeyCacheKeyPrefix = "api_key:"
+
+	// Short TTL so that changes made by other instances propagate quickly
+	defaultAPIKeyCacheTTL = time.Minute
+
+	// lastUsedResolution limits how often last_used_at is written for a busy key
+	lastUsedResolution = time.Minute
+
+	// Timeout for the asynchronous last_used_at update
+	lastUsedUpdateTimeout = 5 * time.Second
+
+	apiKeyPrefixBytes = 4
+	apiKeySecretBytes = 32
+)
+
+type apiKeyService struct {
+	apiKeyRepo domain.APIKeyRepository
+	cacheRepo  domain.CacheRepository
+}
+
+func NewAPIKeyService(apiKeyRepo domain.APIKeyRepository, cacheRepo domain.CacheRepository) domain.APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		cacheRepo:  cacheRepo,
+	}
+}
+
+// IssueAPIKey generates a new key, stores only its hash and prefix, and returns the plaintext in apiKey.Key.
+func (s *apiKeyService) IssueAPIKey(ctx context.Context, apiKey *domain.APIKey) error {
+	plaintext, prefix, err := generateAPIKey()
+	if err != nil {
+		return err
+	}
+
+	apiKey.ID = uuid.New().String()
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = hashAPIKey(plaintext)
+	apiKey.CreatedAt = time.Now()
+	apiKey.LastUsedAt = nil
+	apiKey.Revoked = false
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return err
+	}
+
+	apiKey.Key = plaintext
+	return nil
+}
+
+// ListAPIKeys returns all keys of the caller's tenant. Hashes are never exposed through the API representation.
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	return s.apiKeyRepo.GetAll(ctx)
+}
+
+// RotateAPIKey issues a new secret for an existing key and invalidates the cached lookup of the old one.
+func (s *apiKeyService) RotateAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.Revoked {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	plaintext, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	oldPrefix := apiKey.Prefix
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = hashAPIKey(plaintext)
+	apiKey.LastUsedAt = nil
+
+	if err := s.apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, oldPrefix)
+
+	apiKey.Key = plaintext
+	return apiKey, nil
+}
+
+// RevokeAPIKey marks the key as revoked and invalidates its cached lookup.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	apiKey.Revoked = true
+	if err := s.apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, apiKey.Prefix)
+	return nil
+}
+
+// Authenticate resolves a plaintext key to a Principal owned by the key's owner with the key's scopes.
+// Lookups by prefix are cached; last_used_at is updated asynchronously.
+func (s *apiKeyService) Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed api key", domain.ErrUnauthenticated)
+	}
+
+	apiKey, err := s.getByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown api key", domain.ErrUnauthenticated)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(plaintext)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, fmt.Errorf("%w: invalid api key", domain.ErrUnauthenticated)
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, fmt.Errorf("%w: api key is revoked or expired", domain.ErrUnauthenticated)
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		go s.touch(apiKey.ID, apiKey.Prefix, now)
+	}
+
+	return &domain.Principal{
+		Subject:    apiKey.Owner,
+		AuthMethod: domain.AuthMethodAPIKey,
+		Scopes:     apiKey.Scopes,
+		TenantID:   apiKey.TenantID,
+		ExpiresAt:  apiKey.ExpiresAt,
+		Claims:     map[string]interface{}{"api_key_id": apiKey.ID},
+	}, nil
+}
+
+// getByPrefix retrieves a key by prefix with lazy caching strategy.
+func (s *apiKeyService) getByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	cacheKey := apiKeyCacheKeyPrefix + prefix
+
+	// Try to get from cache first
+	cached, err := s.cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cached != "" {
+		var apiKey domain.APIKey
+		if err := json.Unmarshal([]byte(cached), &apiKey); err == nil {
+			return &apiKey, nil
+		}
+	}
+
+	// Cache miss - fetch from database
+	apiKey, err := s.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(apiKey); err == nil {
+		if err := s.cacheRepo.Set(ctx, cacheKey, string(data), defaultAPIKeyCacheTTL); err != nil {
+			log.Printf("Failed to cache api key %s: %v", prefix, err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+// touch records the last use of a key. It runs detached from the request so
+// authentication latency does not depend on a database write.
+func (s *apiKeyService) touch(id string, prefix string, usedAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), lastUsedUpdateTimeout)
+	defer cancel()
+
+	if err := s.apiKeyRepo.UpdateLastUsed(ctx, id, usedAt); err != nil {
+		log.Printf("Failed to update last use of api key %s: %v", prefix, err)
+		return
+	}
+
+	// Drop the cached copy so the next lookup sees the new last_used_at
+	s.invalidate(ctx, prefix)
+}
+
+func (s *apiKeyService) invalidate(ctx context.Context, prefix string) {
+	if err := s.cacheRepo.Delete(ctx, apiKeyCacheKeyPrefix+prefix); err != nil {
+		log.Printf("Failed to invalidate api key cache %s: %v", prefix, err)
+	}
+}
+
+// generateAPIKey returns a new plaintext key and its lookup prefix.
+func generateAPIKey() (plaintext string, prefix string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	plaintext = domain.APIKeyTokenPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+	return plaintext, prefix, nil
+}
+
+// parseAPIKeyPrefix extracts the lookup prefix from a plaintext key of the form "ak_<prefix>_<secret>".
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, domain.APIKeyTokenPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, found := strings.Cut(rest, "_")
+	if !found || len(prefix) != apiKeyPrefixBytes*2 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// hashAPIKey returns the hex-encoded SHA-256 of the plaintext key.
+// API keys carry 256 bits of entropy, so a fast hash is sufficient.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,325 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIKeyRepository is a mock of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) error {
+	args := m.Called(ctx, apiKey)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) Update(ctx context.Context, apiKey *domain.APIKey) error {
+	args := m.Called(ctx, apiKey)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	args := m.Called(ctx, id, lastUsedAt)
+	return args.Error(0)
+}
+
+// MockCacheRepository is a mock of CacheRepository
+type MockCacheRepository struct {
+	mock.Mock
+}
+
+func (m *MockCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCacheRepository) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	args := m.Called(ctx, key, value, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepository) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepository) Exists(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCacheRepository) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepository) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, token, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCacheRepository) Unlock(ctx context.Context, key string, token string) error {
+	args := m.Called(ctx, key, token)
+	return args.Error(0)
+}
+
+// issueTestKey issues a key through the service and returns the stored record
+func issueTestKey(t *testing.T, scopes []string) (*domain.APIKey, string) {
+	repo := new(MockAPIKeyRepository)
+	svc := NewAPIKeyService(repo, new(MockCacheRepository))
+
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	apiKey := &domain.APIKey{Owner: "billing-service", Scopes: scopes}
+	require.NoError(t, svc.IssueAPIKey(context.Background(), apiKey))
+
+	plaintext := apiKey.Key
+	stored := *apiKey
+	stored.Key = ""
+	return &stored, plaintext
+}
+
+func TestAPIKeyService_IssueAPIKey(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(k *domain.APIKey) bool {
+		// Only the hash is handed to the repository, never the plaintext
+		return k.ID != "" && len(k.Prefix) == 8 && len(k.KeyHash) == 64 && k.Key == ""
+	})).Return(nil)
+
+	apiKey := &domain.APIKey{Owner: "billing-service", Scopes: []string{"items:read"}}
+	err := svc.IssueAPIKey(context.Background(), apiKey)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(apiKey.Key, "ak_"+apiKey.Prefix+"_"))
+	assert.Equal(t, hashAPIKey(apiKey.Key), apiKey.KeyHash)
+	assert.False(t, apiKey.Revoked)
+	repo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_IssueAPIKey_RepoError(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	svc := NewAPIKeyService(repo, new(MockCacheRepository))
+
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	apiKey := &domain.APIKey{Owner: "billing-service"}
+	err := svc.IssueAPIKey(context.Background(), apiKey)
+
+	assert.Error(t, err)
+	assert.Empty(t, apiKey.Key)
+}
+
+func TestAPIKeyService_Authenticate_CacheMiss(t *testing.T) {
+	stored, plaintext := issueTestKey(t, []string{"items:read"})
+	stored.TenantID = "acme"
+
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	cacheKey := "api_key:" + stored.Prefix
+	cache.On("Get", mock.Anything, cacheKey).Return("", errors.New("cache miss"))
+	repo.On("GetByPrefix", mock.Anything, stored.Prefix).Return(stored, nil)
+	cache.On("Set", mock.Anything, cacheKey, mock.Anything, time.Minute).Return(nil)
+
+	// The cached copy is dropped once last_used_at has been written
+	touched := make(chan struct{})
+	repo.On("UpdateLastUsed", mock.Anything, stored.ID, mock.Anything).Return(nil)
+	cache.On("Delete", mock.Anything, cacheKey).Return(nil).Run(func(mock.Arguments) {
+		close(touched)
+	})
+
+	principal, err := svc.Authenticate(context.Background(), plaintext)
+
+	require.NoError(t, err)
+	assert.Equal(t, "billing-service", principal.Subject)
+	assert.Equal(t, domain.AuthMethodAPIKey, principal.AuthMethod)
+	assert.Equal(t, []string{"items:read"}, principal.Scopes)
+	// The key only authenticates within the tenant it was issued in
+	assert.Equal(t, "acme", principal.TenantID)
+
+	// last_used_at is updated asynchronously
+	select {
+	case <-touched:
+	case <-time.After(time.Second):
+		t.Fatal("last_used_at was not updated")
+	}
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Authenticate_CacheHit(t *testing.T) {
+	stored, plaintext := issueTestKey(t, []string{"items:read"})
+	recentlyUsed := time.Now()
+	stored.LastUsedAt = &recentlyUsed
+	cached, err := json.Marshal(stored)
+	require.NoError(t, err)
+
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	cache.On("Get", mock.Anything, "api_key:"+stored.Prefix).Return(string(cached), nil)
+
+	principal, err := svc.Authenticate(context.Background(), plaintext)
+
+	require.NoError(t, err)
+	assert.Equal(t, "billing-service", principal.Subject)
+	// Repository is not hit, and last_used_at was refreshed too recently to be written again
+	repo.AssertNotCalled(t, "GetByPrefix", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	stored, plaintext := issueTestKey(t, nil)
+	past := time.Now().Add(-time.Hour)
+
+	revoked := *stored
+	revoked.Revoked = true
+	expired := *stored
+	expired.ExpiresAt = &past
+
+	tests := []struct {
+		name      string
+		plaintext string
+		record    *domain.APIKey
+	}{
+		{name: "Malformed", plaintext: "not-an-api-key"},
+		{name: "Tampered Secret", plaintext: plaintext + "x", record: stored},
+		{name: "Revoked", plaintext: plaintext, record: &revoked},
+		{name: "Expired", plaintext: plaintext, record: &expired},
+		{name: "Unknown", plaintext: plaintext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			cache := new(MockCacheRepository)
+			svc := NewAPIKeyService(repo, cache)
+
+			cache.On("Get", mock.Anything, mock.Anything).Return("", errors.New("cache miss"))
+			cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			if tt.record != nil {
+				repo.On("GetByPrefix", mock.Anything, stored.Prefix).Return(tt.record, nil)
+			} else {
+				repo.On("GetByPrefix", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+			}
+
+			principal, err := svc.Authenticate(context.Background(), tt.plaintext)
+
+			assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+			assert.Nil(t, principal)
+			repo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	stored, oldPlaintext := issueTestKey(t, []string{"items:read"})
+	oldPrefix := stored.Prefix
+
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	repo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	cache.On("Delete", mock.Anything, "api_key:"+oldPrefix).Return(nil)
+
+	rotated, err := svc.RotateAPIKey(context.Background(), stored.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, rotated.ID)
+	assert.NotEqual(t, oldPrefix, rotated.Prefix)
+	assert.NotEqual(t, oldPlaintext, rotated.Key)
+	assert.Equal(t, hashAPIKey(rotated.Key), rotated.KeyHash)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestAPIKeyService_RotateAPIKey_Revoked(t *testing.T) {
+	stored, _ := issueTestKey(t, nil)
+	stored.Revoked = true
+
+	repo := new(MockAPIKeyRepository)
+	svc := NewAPIKeyService(repo, new(MockCacheRepository))
+
+	repo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil)
+
+	_, err := svc.RotateAPIKey(context.Background(), stored.ID)
+
+	assert.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	stored, _ := issueTestKey(t, nil)
+
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	repo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(k *domain.APIKey) bool {
+		return k.ID == stored.ID && k.Revoked
+	})).Return(nil)
+	cache.On("Delete", mock.Anything, "api_key:"+stored.Prefix).Return(nil)
+
+	err := svc.RevokeAPIKey(context.Background(), stored.ID)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestAPIKeyService_RevokeAPIKey_NotFound(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewAPIKeyService(repo, cache)
+
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, errors.New("not found"))
+
+	err := svc.RevokeAPIKey(context.Background(), "missing")
+
+	assert.Error(t, err)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtVerifier implements domain.TokenVerifier for signed JWTs (HS256, RS256 and ES256).
+type jwtVerifier struct {
+	secret  []byte
+	rsaKeys []verificationKey
+	ecKeys  []verificationKey
+	parser  *jwt.Parser
+	// tenantClaim names the claim binding a token to a tenant
+	tenantClaim string
+}
+
+// NewJWTVerifier creates a JWT verifier from the key material in the configuration.
+// HS256 is enabled by JWTSecret, RS256/ES256 by JWTPublicKeyFile and/or JWTJWKSFile.
+// If no key material is configured, every token is rejected.
+func NewJWTVerifier(cfg *config.Config) (domain.TokenVerifier, error) {
+	v := &jwtVerifier{tenantClaim: cfg.TenantClaim}
+
+	if cfg.JWTSecret != "" {
+		v.secret = []byte(cfg.JWTSecret)
+	}
+
+	var keys []verificationKey
+	if cfg.JWTPublicKeyFile != "" {
+		key, err := loadPublicKeyFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, verificationKey{key: key})
+	}
+	if cfg.JWTJWKSFile != "" {
+		jwks, err := loadJWKSFile(cfg.JWTJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+
+	for _, k := range keys {
+		switch k.key.(type) {
+		case *rsa.PublicKey:
+			v.rsaKeys = append(v.rsaKeys, k)
+		case *ecdsa.PublicKey:
+			v.ecKeys = append(v.ecKeys, k)
+		}
+	}
+
+	// A non-nil (possibly empty) list makes the parser reject any other algorithm
+	methods := []string{}
+	if v.secret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(v.ecKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodES256.Alg())
+	}
+	if len(methods) == 0 {
+		log.Printf("No JWT key material configured (JWT_SECRET, JWT_PUBLIC_KEY_FILE, JWT_JWKS_FILE); all bearer tokens will be rejected")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.JWTLeeway),
+	}
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
+
+// Verify validates the token signature, exp/nbf and the configured iss/aud claims.
+func (v *jwtVerifier) Verify(ctx context.Context, tokenString string) (*domain.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnauthenticated, err)
+	}
+	return principalFromClaims(claims, v.tenantClaim)
+}
+
+// keyFunc selects the verification key matching the token's algorithm and optional kid header.
+func (v *jwtVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if v.secret == nil {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return v.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		return selectKey(v.rsaKeys, kid)
+	case jwt.SigningMethodES256.Alg():
+		return selectKey(v.ecKeys, kid)
+	default:
+		return nil, jwt.ErrTokenUnverifiable
+	}
+}
+
+// selectKey returns the key with the given kid. Tokens without a kid are only
+// accepted when exactly one candidate key is configured. Tokens with a kid that no key
+// has fall back to the key without a kid (e.g. JWT_PUBLIC_KEY_FILE), when there is one.
+func selectKey(keys []verificationKey, kid string) (interface{}, error) {
+	if kid == "" {
+		if len(keys) == 1 {
+			return keys[0].key, nil
+		}
+		return nil, errors.New("token has no kid header and multiple keys are configured")
+	}
+	var unnamed []verificationKey
+	for _, k := range keys {
+		if k.kid == kid {
+			return k.key, nil
+		}
+		if k.kid == "" {
+			unnamed = append(unnamed, k)
+		}
+	}
+	if len(unnamed) == 1 {
+		return unnamed[0].key, nil
+	}
+	return nil, fmt.Errorf("no key found for kid %q", kid)
+}
+
+// principalFromClaims maps verified JWT claims to a domain.Principal.
+// Scopes are read from the space-delimited "scope" claim or the "scp" claim,
+// roles from the "roles" claim and the tenant from tenantClaim (if not empty).
+func principalFromClaims(claims jwt.MapClaims, tenantClaim string) (*domain.Principal, error) {
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", domain.ErrUnauthenticated)
+	}
+
+	principal := &domain.Principal{
+		Subject:    subject,
+		AuthMethod: domain.AuthMethodJWT,
+		Claims:     claims,
+	}
+	principal.Issuer, _ = claims.GetIssuer()
+	principal.Audience, _ = claims.GetAudience()
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		expiresAt := exp.Time
+		principal.ExpiresAt = &expiresAt
+	}
+
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = strings.Fields(scope)
+	} else {
+		principal.Scopes = stringList(claims["scp"])
+	}
+	principal.Roles = stringList(claims["roles"])
+	if tenantClaim != "" {
+		principal.TenantID, _ = claims[tenantClaim].(string)
+	}
+
+	return principal, nil
+}
+
+// stringList converts a claim holding a string or an array of strings into a slice.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
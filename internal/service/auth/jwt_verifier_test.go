@@ -0,0 +1,377 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func newHS256Config() *config.Config {
+	return &config.Config{
+		JWTSecret:   testSecret,
+		JWTIssuer:   "https://issuer.example.com",
+		JWTAudience: "go-api-boilerplate",
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-123",
+		"iss":   "https://issuer.example.com",
+		"aud":   "go-api-boilerplate",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "items:read items:write",
+		"roles": []string{"editor"},
+	}
+}
+
+func signHS256(t *testing.T, claims jwt.MapClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTVerifier_HS256_Valid(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(context.Background(), signHS256(t, validClaims(), testSecret))
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+	assert.Equal(t, "https://issuer.example.com", principal.Issuer)
+	assert.Equal(t, []string{"go-api-boilerplate"}, principal.Audience)
+	assert.Equal(t, []string{"items:read", "items:write"}, principal.Scopes)
+	assert.Equal(t, []string{"editor"}, principal.Roles)
+	assert.NotNil(t, principal.ExpiresAt)
+}
+
+func TestJWTVerifier_TenantClaim(t *testing.T) {
+	cfg := newHS256Config()
+	cfg.TenantClaim = "tenant_id"
+	verifier, err := NewJWTVerifier(cfg)
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["tenant_id"] = "acme"
+	principal, err := verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	require.NoError(t, err)
+	assert.Equal(t, "acme", principal.TenantID)
+
+	// Tokens without the claim are not bound to a tenant
+	principal, err = verifier.Verify(context.Background(), signHS256(t, validClaims(), testSecret))
+
+	require.NoError(t, err)
+	assert.Empty(t, principal.TenantID)
+}
+
+func TestJWTVerifier_Expired(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestJWTVerifier_MissingExpiration(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	delete(claims, "exp")
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestJWTVerifier_NotYetValid(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["nbf"] = time.Now().Add(time.Hour).Unix()
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, jwt.ErrTokenNotValidYet)
+}
+
+func TestJWTVerifier_WrongAudience(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["aud"] = "another-api"
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+}
+
+func TestJWTVerifier_WrongIssuer(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["iss"] = "https://evil.example.com"
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+}
+
+func TestJWTVerifier_TamperedPayload(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	token := signHS256(t, validClaims(), testSecret)
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	// Swap in a payload claiming a different subject while keeping the original signature
+	claims := validClaims()
+	claims["sub"] = "admin"
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+
+	_, err = verifier.Verify(context.Background(), strings.Join(parts, "."))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestJWTVerifier_WrongSecret(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, validClaims(), "other-secret"))
+
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestJWTVerifier_RejectsUnsignedToken(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), token)
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestJWTVerifier_MissingSubject(t *testing.T) {
+	verifier, err := NewJWTVerifier(newHS256Config())
+	require.NoError(t, err)
+
+	claims := validClaims()
+	delete(claims, "sub")
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, claims, testSecret))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestJWTVerifier_NoKeyMaterialRejectsTokens(t *testing.T) {
+	verifier, err := NewJWTVerifier(&config.Config{})
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), signHS256(t, validClaims(), testSecret))
+
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestJWTVerifier_RS256_PublicKeyFile(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	verifier, err := NewJWTVerifier(&config.Config{JWTPublicKeyFile: keyFile, JWTAudience: "go-api-boilerplate"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims()).SignedString(privateKey)
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+
+	// An HS256 token must not be accepted when only an RSA key is configured
+	_, err = verifier.Verify(context.Background(), signHS256(t, validClaims(), testSecret))
+	assert.Error(t, err)
+}
+
+func TestJWTVerifier_ES256_JWKSFile(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PublicKey{
+		"key-1": &privateKey.PublicKey,
+		"key-2": &otherKey.PublicKey,
+	})
+
+	verifier, err := NewJWTVerifier(&config.Config{JWTJWKSFile: jwksFile})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims())
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(context.Background(), signed)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+
+	// Same token announced with the wrong kid fails signature verification
+	token.Header["kid"] = "key-2"
+	signed, err = token.SignedString(privateKey)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signed)
+	assert.True(t, errors.Is(err, jwt.ErrTokenSignatureInvalid))
+
+	// Unknown kid
+	token.Header["kid"] = "key-3"
+	signed, err = token.SignedString(privateKey)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signed)
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestJWTVerifier_JWKSFile_UnsupportedCurve(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PublicKey{"key-1": &privateKey.PublicKey})
+	// Add a P-384 key, which ES256 cannot use
+	data, err := os.ReadFile(jwksFile)
+	require.NoError(t, err)
+	var set jwkSet
+	require.NoError(t, json.Unmarshal(data, &set))
+	point, err := p384Key.PublicKey.Bytes()
+	require.NoError(t, err)
+	set.Keys = append(set.Keys, jwk{
+		Kty: "EC",
+		Kid: "key-2",
+		Use: "sig",
+		Crv: "P-384",
+		X:   base64.RawURLEncoding.EncodeToString(point[1:49]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[49:]),
+	})
+	data, err = json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(jwksFile, data, 0600))
+
+	verifier, err := NewJWTVerifier(&config.Config{JWTJWKSFile: jwksFile})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims())
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signed)
+	assert.NoError(t, err)
+}
+
+func TestJWTVerifier_UnknownKidFallsBackToPublicKeyFile(t *testing.T) {
+	pemKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwksKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	der, err := x509.MarshalPKIXPublicKey(&pemKey.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	jwksFile := filepath.Join(dir, "jwks.json")
+	writeJWKS(t, jwksFile, map[string]*ecdsa.PublicKey{"key-1": &jwksKey.PublicKey})
+
+	verifier, err := NewJWTVerifier(&config.Config{JWTPublicKeyFile: keyFile, JWTJWKSFile: jwksFile})
+	require.NoError(t, err)
+
+	// A kid that no key has is verified with the key without a kid
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims())
+	token.Header["kid"] = "pem-key"
+	signed, err := token.SignedString(pemKey)
+	require.NoError(t, err)
+	principal, err := verifier.Verify(context.Background(), signed)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+
+	// A kid that a key has is verified with that key only
+	token.Header["kid"] = "key-1"
+	signed, err = token.SignedString(pemKey)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signed)
+	assert.True(t, errors.Is(err, jwt.ErrTokenSignatureInvalid))
+}
+
+func TestNewJWTVerifier_InvalidKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0600))
+
+	_, err := NewJWTVerifier(&config.Config{JWTPublicKeyFile: keyFile})
+	assert.Error(t, err)
+
+	_, err = NewJWTVerifier(&config.Config{JWTJWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
+
+func writeJWKS(t *testing.T, path string, keys map[string]*ecdsa.PublicKey) {
+	set := jwkSet{}
+	for kid, key := range keys {
+		point, err := key.Bytes()
+		require.NoError(t, err)
+		set.Keys = append(set.Keys, jwk{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+		})
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
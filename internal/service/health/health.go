@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"go.uber.org/fx"
+)
+
+type healthService struct {
+	checks       []domain.HealthCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(checks []domain.HealthCheck, cfg *config.Config) domain.HealthService {
+	return &healthService{checks: checks, timeout: cfg.HealthCheckTimeout}
+}
+
+// Readiness runs the checks at the same time, each within the configured timeout, and
+// reports the application ready when it is not shutting down and every check passed.
+// The errors of the checks are logged rather than reported, since readiness is public.
+func (s *healthService) Readiness(ctx context.Context) *domain.Readiness {
+	results := make([]domain.DependencyHealth, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(checkCtx)
+			results[i] = domain.DependencyHealth{
+				Status:   domain.HealthStatusUp,
+				Backend:  check.Backend,
+				Fallback: check.Fallback,
+				Latency:  time.Since(start),
+			}
+			if err != nil {
+				log.Printf("Health check %s failed: %v", check.Name, err)
+				results[i].Status = domain.HealthStatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	shuttingDown := s.shuttingDown.Load()
+	readiness := &domain.Readiness{
+		Ready:        !shuttingDown,
+		ShuttingDown: shuttingDown,
+		Dependencies: make(map[string]domain.DependencyHealth, len(s.checks)),
+	}
+	for i, check := range s.checks {
+		readiness.Dependencies[check.Name] = results[i]
+		if results[i].Status != domain.HealthStatusUp {
+			readiness.Ready = false
+		}
+		if check.Fallback {
+			readiness.Degraded = true
+		}
+	}
+	return readiness
+}
+
+func (s *healthService) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+// RegisterHooks makes the application unready when it stops, then waits for the
+// configured delay before the hooks registered earlier, the server's among them, stop.
+// Load balancers polling readiness meanwhile stop sending requests before the server
+// stops accepting them. It must be invoked after server.RegisterHooks.
+func RegisterHooks(lc fx.Lifecycle, service domain.HealthService, cfg *config.Config) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			service.ShutDown()
+			if cfg.ShutdownDelay <= 0 {
+				return nil
+			}
+			log.Printf("Reporting unready for %s before stopping", cfg.ShutdownDelay)
+			timer := time.NewTimer(cfg.ShutdownDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+)
+
+var testConfig = &config.Config{HealthCheckTimeout: 100 * time.Millisecond}
+
+func up(context.Context) error { return nil }
+
+func TestHealthService_Readiness(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{
+		{Name: "database", Backend: "mysql", Check: up},
+		{Name: "cache", Backend: "redis", Check: up},
+	}, testConfig)
+
+	readiness := svc.Readiness(context.Background())
+
+	assert.True(t, readiness.Ready)
+	assert.False(t, readiness.Degraded)
+	assert.False(t, readiness.ShuttingDown)
+	assert.Equal(t, domain.HealthStatusUp, readiness.Dependencies["database"].Status)
+	assert.Equal(t, "mysql", readiness.Dependencies["database"].Backend)
+	assert.Equal(t, domain.HealthStatusUp, readiness.Dependencies["cache"].Status)
+}
+
+func TestHealthService_Readiness_DependencyDown(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{
+		{Name: "database", Backend: "mysql", Check: up},
+		{Name: "cache", Backend: "redis", Check: func(context.Context) error { return errors.New("connection refused") }},
+	}, testConfig)
+
+	readiness := svc.Readiness(context.Background())
+
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, domain.HealthStatusUp, readiness.Dependencies["database"].Status)
+	assert.Equal(t, domain.HealthStatusDown, readiness.Dependencies["cache"].Status)
+}
+
+func TestHealthService_Readiness_Timeout(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{
+		{Name: "database", Backend: "mysql", Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, testConfig)
+
+	start := time.Now()
+	readiness := svc.Readiness(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, domain.HealthStatusDown, readiness.Dependencies["database"].Status)
+	assert.GreaterOrEqual(t, readiness.Dependencies["database"].Latency, testConfig.HealthCheckTimeout)
+}
+
+func TestHealthService_Readiness_Fallback(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{
+		{Name: "database", Backend: "sqlite", Fallback: true, Check: up},
+		{Name: "cache", Backend: "redis", Check: up},
+	}, testConfig)
+
+	readiness := svc.Readiness(context.Background())
+
+	// A fallback degrades the application without making it unready
+	assert.True(t, readiness.Ready)
+	assert.True(t, readiness.Degraded)
+	assert.True(t, readiness.Dependencies["database"].Fallback)
+}
+
+func TestHealthService_ShutDown(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{{Name: "database", Backend: "mysql", Check: up}}, testConfig)
+
+	svc.ShutDown()
+	readiness := svc.Readiness(context.Background())
+
+	assert.False(t, readiness.Ready)
+	assert.True(t, readiness.ShuttingDown)
+	assert.Equal(t, domain.HealthStatusUp, readiness.Dependencies["database"].Status)
+}
+
+func TestRegisterHooks(t *testing.T) {
+	svc := NewHealthService([]domain.HealthCheck{{Name: "database", Backend: "mysql", Check: up}}, testConfig)
+	cfg := &config.Config{ShutdownDelay: 50 * time.Millisecond}
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, svc, cfg)
+	lc.RequireStart()
+
+	assert.True(t, svc.Readiness(context.Background()).Ready)
+
+	start := time.Now()
+	lc.RequireStop()
+
+	// The application reported unready for the whole delay
+	assert.GreaterOrEqual(t, time.Since(start), cfg.ShutdownDelay)
+	assert.True(t, svc.Readiness(context.Background()).ShuttingDown)
+}
@@ -0,0 +1,122 @@
+package items
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+)
+
+// errBulkRolledBack rolls back an all-or-nothing bulk write in which an element failed.
+var errBulkRolledBack = errors.New("bulk write rolled back")
+
+// CreateItems validates the items and creates the valid ones with batched inserts, without
+// properties, then invalidates the items list caches they can appear in.
+func (s *itemService) CreateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	errs := s.validateItems(items)
+	err := s.writeBulk(ctx, mode, items, errs, func(ctx context.Context, items []*domain.Item) ([]error, error) {
+		return make([]error, len(items)), s.itemRepo.CreateBatch(ctx, items)
+	}, cacheKeys.addItemLists)
+	return errs, err
+}
+
+// UpdateItems validates the items and updates the valid ones, each at its Version when it is
+// set, with batched upserts, then invalidates their single item and items list caches.
+func (s *itemService) UpdateItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	errs := s.validateItems(items)
+	err := s.writeBulk(ctx, mode, items, errs, s.itemRepo.UpdateBatch, func(keys cacheKeys, item *domain.Item) {
+		keys.addItem(item)
+		keys.addItemLists(item)
+	})
+	return errs, err
+}
+
+// DeleteItems soft-deletes the items, each at its Version when it is set, then invalidates
+// their single item and items list caches and the caches of their properties.
+func (s *itemService) DeleteItems(ctx context.Context, items []*domain.Item, mode domain.BulkMode) ([]error, error) {
+	errs := make([]error, len(items))
+	err := s.writeBulk(ctx, mode, items, errs, s.itemRepo.DeleteBatch, func(keys cacheKeys, item *domain.Item) {
+		keys.addItem(item)
+		keys.addItemLists(item)
+		keys.addItemProperties(item)
+	})
+	return errs, err
+}
+
+// validateItems returns the validation errors of each item, indexed like items.
+func (s *itemService) validateItems(items []*domain.Item) []error {
+	errs := make([]error, len(items))
+	for i, item := range items {
+		if validationErrors := s.validator.Validate(item); len(validationErrors) > 0 {
+			errs[i] = validationErrors
+		}
+	}
+	return errs
+}
+
+// writeBulk writes the items that have no error in errs yet with write, in one transaction,
+// and sets the errors write returns for them in errs. In BulkAllOrNothing mode nothing is
+// written unless every item succeeds: the transaction is rolled back, and the items that
+// did not fail are given domain.ErrBulkAborted. The cache keys invalidate adds for the
+// written items are invalidated in one pass once the transaction commits.
+func (s *itemService) writeBulk(
+	ctx context.Context,
+	mode domain.BulkMode,
+	items []*domain.Item,
+	errs []error,
+	write func(ctx context.Context, items []*domain.Item) ([]error, error),
+	invalidate func(keys cacheKeys, item *domain.Item),
+) error {
+	allOrNothing := mode != domain.BulkBestEffort
+	pending := make([]*domain.Item, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		if errs[i] == nil {
+			pending = append(pending, item)
+			indexes = append(indexes, i)
+		}
+	}
+	if allOrNothing && len(pending) < len(items) {
+		abortBulk(errs)
+		return nil
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		written, err := write(ctx, pending)
+		if err != nil {
+			return err
+		}
+		keys := cacheKeys{}
+		failed := false
+		for j, err := range written {
+			errs[indexes[j]] = err
+			if err != nil {
+				failed = true
+				continue
+			}
+			invalidate(keys, pending[j])
+		}
+		if allOrNothing && failed {
+			return errBulkRolledBack
+		}
+		keys.invalidate(ctx, s.cacheRepo)
+		return nil
+	})
+	if errors.Is(err, errBulkRolledBack) {
+		abortBulk(errs)
+		return nil
+	}
+	return err
+}
+
+// abortBulk gives domain.ErrBulkAborted to the elements of a bulk write that did not fail.
+func abortBulk(errs []error) {
+	for i, err := range errs {
+		if err == nil {
+			errs[i] = domain.ErrBulkAborted
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package items
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkItem returns a valid item of user-1 in the test tenant, for bulk tests
+func bulkItem(id string) *domain.Item {
+	return &domain.Item{ID: id, Title: "Lamp", OwnerID: "user-1", TenantID: testTenant}
+}
+
+func TestItemService_CreateItems_AllOrNothing_Invalid(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	items := []*domain.Item{bulkItem(itemUUID), {ID: propertyUUID, OwnerID: "user-1"}}
+	errs, err := svc.CreateItems(userCtx("user-1"), items, domain.BulkAllOrNothing)
+
+	require.NoError(t, err)
+	// The valid item is not written since the other one is invalid
+	assert.ErrorIs(t, errs[0], domain.ErrBulkAborted)
+	assert.ErrorIs(t, errs[1], domain.ErrValidation)
+	repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestItemService_CreateItems_BestEffort(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	valid, other := bulkItem(itemUUID), bulkItem(propertyUUID)
+	items := []*domain.Item{valid, {ID: "invalid", OwnerID: "user-1"}, other}
+	repo.On("CreateBatch", mock.Anything, []*domain.Item{valid, other}).Return(nil)
+	// Both items share their lists, which are invalidated once
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil).Once()
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil).Once()
+
+	errs, err := svc.CreateItems(userCtx("user-1"), items, domain.BulkBestEffort)
+
+	require.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], domain.ErrValidation)
+	assert.NoError(t, errs[2])
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_UpdateItems_AllOrNothing_RollsBack(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	items := []*domain.Item{bulkItem(itemUUID), bulkItem(propertyUUID)}
+	repo.On("UpdateBatch", mock.Anything, items).Return([]error{nil, domain.ErrPreconditionFailed}, nil)
+
+	errs, err := svc.UpdateItems(userCtx("user-1"), items, domain.BulkAllOrNothing)
+
+	require.NoError(t, err)
+	assert.ErrorIs(t, errs[0], domain.ErrBulkAborted)
+	assert.ErrorIs(t, errs[1], domain.ErrPreconditionFailed)
+	// Nothing is invalidated for a rolled back write
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestItemService_UpdateItems_BestEffort(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	items := []*domain.Item{bulkItem(itemUUID), bulkItem(propertyUUID)}
+	repo.On("UpdateBatch", mock.Anything, items).Return([]error{nil, domain.ErrNotFound}, nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:"+itemUUID).Return(nil).Once()
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:"+itemUUID).Return(nil).Once()
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil).Once()
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil).Once()
+
+	errs, err := svc.UpdateItems(userCtx("user-1"), items, domain.BulkBestEffort)
+
+	require.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], domain.ErrNotFound)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_DeleteItems(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	// Items to delete are identified by ID only; the repository reads back the rest
+	items := []*domain.Item{{ID: itemUUID}}
+	repo.On("DeleteBatch", mock.Anything, items).Run(func(args mock.Arguments) {
+		*args.Get(1).([]*domain.Item)[0] = *bulkItem(itemUUID)
+	}).Return([]error{nil}, nil)
+	for _, key := range []string{
+		"item:tenant:acme:owner:user-1:" + itemUUID,
+		"item:tenant:acme:all:" + itemUUID,
+		"items:list:tenant:acme:owner:user-1",
+		"items:list:tenant:acme:all",
+		"item_properties:list:tenant:acme:owner:user-1:" + itemUUID,
+		"item_properties:list:tenant:acme:all:" + itemUUID,
+	} {
+		cache.On("Delete", mock.Anything, key).Return(nil).Once()
+	}
+
+	errs, err := svc.DeleteItems(userCtx("user-1"), items, domain.BulkAllOrNothing)
+
+	require.NoError(t, err)
+	assert.Equal(t, []error{nil}, errs)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
@@ -0,0 +1,208 @@
+package items
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+)
+
+// allOwnersCacheScope is the cache namespace shared by a tenant's admins, who see every item of the tenant.
+const allOwnersCacheScope = "all"
+
+// cacheScope returns the cache namespace for the items visible to the caller.
+// Namespaces are per tenant, and every non-admin caller gets a namespace of
+// its own within the tenant, so an entry cached for one caller is never
+// served to another.
+func cacheScope(ctx context.Context) (string, error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return "", domain.ErrUnauthenticated
+	}
+	tenantID, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return "", domain.ErrTenantRequired
+	}
+	if principal.HasRole(domain.RoleAdmin) {
+		return tenantCacheScope(tenantID) + ":" + allOwnersCacheScope, nil
+	}
+	return ownerCacheScope(tenantID, principal.Subject), nil
+}
+
+// tenantCacheScope returns the prefix of every cache namespace of a tenant.
+// The tenant is escaped so that it cannot spill into the rest of the key.
+func tenantCacheScope(tenantID string) string {
+	return "tenant:" + url.QueryEscape(tenantID)
+}
+
+// ownerCacheScope returns the cache namespace of a single owner within a tenant.
+func ownerCacheScope(tenantID string, ownerID string) string {
+	return tenantCacheScope(tenantID) + ":owner:" + url.QueryEscape(ownerID)
+}
+
+// ownerCacheScopes returns every namespace an item of the given tenant and owner can be cached in.
+func ownerCacheScopes(tenantID string, ownerID string) []string {
+	return []string{ownerCacheScope(tenantID, ownerID), tenantCacheScope(tenantID) + ":" + allOwnersCacheScope}
+}
+
+func itemCacheKey(scope string, id string) string {
+	return fmt.Sprintf("%s%s:%s", itemCacheKeyPrefix, scope, id)
+}
+
+func itemsListCacheKey(scope string) string {
+	return itemsListCacheKeyPrefix + scope
+}
+
+// cacheGeneration returns the current generation of a group of cached entries,
+// stored under groupKey: the pages of a list, or the shapes of a resource.
+// Entries are cached under keys that include the generation (see
+// queryCacheKey), so deleting groupKey invalidates every entry of the group at
+// once; entries of older generations are never read again and expire.
+func cacheGeneration(ctx context.Context, cacheRepo domain.CacheRepository, groupKey string, ttl time.Duration) string {
+	if generation, err := cacheRepo.Get(ctx, groupKey); err == nil && generation != "" {
+		return generation
+	}
+
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := cacheRepo.Set(ctx, groupKey, generation, ttl); err != nil {
+		log.Printf("Failed to store cache generation (%s): %v", groupKey, err)
+	}
+	return generation
+}
+
+// generationTime returns when a generation was started: at the first read of its
+// group after the group was last invalidated, hence after the last change to it.
+// It returns the zero time for a generation that is not a timestamp.
+func generationTime(generation string) time.Time {
+	nanos, err := strconv.ParseInt(generation, 36, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// digest returns a hash of a cached representation.
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// itemValidators returns the validators of an item cached as data under generation of its group.
+// The item was last modified at its UpdatedAt, unless opts includes its properties, which are
+// changed without it: then at the start of the generation, which changes of the properties
+// invalidate.
+func itemValidators(item *domain.Item, data []byte, opts domain.QueryOptions, generation string) domain.Validators {
+	lastModified := item.UpdatedAt
+	if len(opts.Include) > 0 {
+		lastModified = generationTime(generation)
+	}
+	return domain.Validators{Digest: digest(data), Version: item.Version, LastModified: lastModified}
+}
+
+// listValidators returns the validators of a page cached as data under generation of its list.
+// Pages change without any of their items being updated (e.g. when one is deleted), so they
+// were last modified at the start of the generation, which every change to the list invalidates.
+func listValidators(data []byte, generation string) domain.Validators {
+	return domain.Validators{Digest: digest(data), LastModified: generationTime(generation)}
+}
+
+// queryCacheKey returns the key an entry of the group stored under groupKey is
+// cached under. query holds everything that shapes the entry (filters, sort,
+// page, includes and fields) and is identified by a hash of its JSON encoding,
+// which is stable: struct fields keep their order and map keys are sorted.
+func queryCacheKey(groupKey string, generation string, query any) string {
+	data, _ := json.Marshal(query)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s:%s:%s", groupKey, generation, hex.EncodeToString(sum[:16]))
+}
+
+func itemPropertyCacheKey(scope string, itemID string, id string) string {
+	return fmt.Sprintf("%s%s:%s:%s", itemPropertyCacheKeyPrefix, scope, itemID, id)
+}
+
+func itemPropertiesListCacheKey(scope string, itemID string) string {
+	return fmt.Sprintf(itemPropertiesListCacheKeyFmt, scope, itemID)
+}
+
+// invalidateItem drops every cached shape of an item, with or without its
+// properties included, from every scope it can be cached in.
+//
+// Like the other invalidations, it is deferred until the transaction of ctx commits,
+// if any (see domain.AfterCommit): until then the cache is refilled from the state
+// the transaction is about to replace, and a rolled back change leaves it intact.
+func invalidateItem(ctx context.Context, cacheRepo domain.CacheRepository, item *domain.Item) {
+	domain.AfterCommit(ctx, func() {
+		for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+			if err := cacheRepo.Delete(ctx, itemCacheKey(scope, item.ID)); err != nil {
+				log.Printf("Failed to invalidate item cache %s (%s): %v", item.ID, scope, err)
+			}
+		}
+	})
+}
+
+// invalidateItemLists drops every cached page of the items lists and searches that can contain an item.
+func invalidateItemLists(ctx context.Context, cacheRepo domain.CacheRepository, item *domain.Item) {
+	domain.AfterCommit(ctx, func() {
+		for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+			if err := cacheRepo.Delete(ctx, itemsListCacheKey(scope)); err != nil {
+				log.Printf("Failed to invalidate items list cache (%s): %v", scope, err)
+			}
+		}
+	})
+}
+
+// invalidateItemProperties drops every cached property and properties list page of an item.
+func invalidateItemProperties(ctx context.Context, cacheRepo domain.CacheRepository, item *domain.Item) {
+	domain.AfterCommit(ctx, func() {
+		for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+			if err := cacheRepo.Delete(ctx, itemPropertiesListCacheKey(scope, item.ID)); err != nil {
+				log.Printf("Failed to invalidate item properties cache (item: %s): %v", item.ID, err)
+			}
+		}
+	})
+}
+
+// cacheKeys collects the cache keys a bulk write invalidates, so that each key is deleted
+// once in a single pass, however many of the written items share it.
+type cacheKeys map[string]struct{}
+
+// addItem adds the keys invalidateItem deletes for item.
+func (k cacheKeys) addItem(item *domain.Item) {
+	for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+		k[itemCacheKey(scope, item.ID)] = struct{}{}
+	}
+}
+
+// addItemLists adds the keys invalidateItemLists deletes for item.
+func (k cacheKeys) addItemLists(item *domain.Item) {
+	for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+		k[itemsListCacheKey(scope)] = struct{}{}
+	}
+}
+
+// addItemProperties adds the keys invalidateItemProperties deletes for item.
+func (k cacheKeys) addItemProperties(item *domain.Item) {
+	for _, scope := range ownerCacheScopes(item.TenantID, item.OwnerID) {
+		k[itemPropertiesListCacheKey(scope, item.ID)] = struct{}{}
+	}
+}
+
+// invalidate deletes the collected keys, once the transaction of ctx commits like invalidateItem.
+func (k cacheKeys) invalidate(ctx context.Context, cacheRepo domain.CacheRepository) {
+	domain.AfterCommit(ctx, func() {
+		for _, key := range slices.Sorted(maps.Keys(k)) {
+			if err := cacheRepo.Delete(ctx, key); err != nil {
+				log.Printf("Failed to invalidate cache %s: %v", key, err)
+			}
+		}
+	})
+}
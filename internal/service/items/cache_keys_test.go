@@ -0,0 +1,281 @@
+package items
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache is a CacheRepository keeping entries in memory, for tests that
+// follow cached entries across reads and writes
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: map[string]string{}}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	if !ok {
+		return "", errors.New("cache miss")
+	}
+	return value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (c *memoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memoryCache) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return false, nil
+	}
+	c.entries[key] = token
+	return true, nil
+}
+
+func (c *memoryCache) Unlock(ctx context.Context, key string, token string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == token {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// withProperties includes the properties of items
+var withProperties = domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}}
+
+func TestItemService_GetItemByID_CachesEachShape(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	bare := &domain.Item{ID: "1", Title: "Lamp", OwnerID: "user-1", TenantID: "acme"}
+	full := &domain.Item{ID: "1", Title: "Lamp", OwnerID: "user-1", TenantID: "acme",
+		ItemProperties: []*domain.ItemProperty{{ID: "p1", ItemID: "1", Name: "color", Value: "red"}}}
+	titleOnly := domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"title"}}}
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(bare, nil).Once()
+	repo.On("GetByID", mock.Anything, "1", withProperties).Return(full, nil).Once()
+	repo.On("GetByID", mock.Anything, "1", titleOnly).Return(&domain.Item{ID: "1", Title: "Lamp"}, nil).Once()
+
+	// A read without include does not answer a later read with include, nor the reverse
+	for range 2 {
+		item, _, err := svc.GetItemByID(ctx, "1", domain.QueryOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, item.ItemProperties)
+
+		item, _, err = svc.GetItemByID(ctx, "1", withProperties)
+		require.NoError(t, err)
+		assert.Len(t, item.ItemProperties, 1)
+
+		item, _, err = svc.GetItemByID(ctx, "1", titleOnly)
+		require.NoError(t, err)
+		assert.Empty(t, item.OwnerID)
+	}
+
+	// Each shape was read from the database once, then from the cache
+	repo.AssertExpectations(t)
+}
+
+func TestItemService_GetAllItems_CachesEachShape(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	withInclude := domain.ItemQuery{Page: testPage, Options: withProperties}
+	repo.On("GetAll", mock.Anything, testQuery).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1"}}, Total: 1}, nil).Once()
+	repo.On("GetAll", mock.Anything, withInclude).
+		Return(&domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", ItemProperties: []*domain.ItemProperty{{ID: "p1"}}}}, Total: 1}, nil).Once()
+
+	for range 2 {
+		items, _, err := svc.GetAllItems(ctx, testQuery)
+		require.NoError(t, err)
+		assert.Empty(t, items.Items[0].ItemProperties)
+
+		items, _, err = svc.GetAllItems(ctx, withInclude)
+		require.NoError(t, err)
+		assert.Len(t, items.Items[0].ItemProperties, 1)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestItemPropertyService_UpdateInvalidatesIncludingItem(t *testing.T) {
+	itemRepo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := newMemoryCache()
+	itemSvc := NewItemService(itemRepo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+	propertySvc := NewItemPropertyService(propertyRepo, itemRepo, cache, newTestValidator())
+	ctx := userCtx("user-1")
+
+	item := func(value string) *domain.Item {
+		return &domain.Item{ID: "1", OwnerID: "user-1", TenantID: "acme",
+			ItemProperties: []*domain.ItemProperty{{ID: "p1", ItemID: "1", Name: "color", Value: value}}}
+	}
+	page := func(value string) *domain.Page[*domain.Item] {
+		return &domain.Page[*domain.Item]{Items: []*domain.Item{item(value)}, Total: 1}
+	}
+	withInclude := domain.ItemQuery{Page: testPage, Options: withProperties}
+	byColor := domain.ItemQuery{Properties: map[string]string{"color": "red"}, Page: testPage}
+	search := domain.ItemSearch{Text: "red", Page: testPage}
+	property := &domain.ItemProperty{ID: "p1", ItemID: "1", Name: "color", Value: "red"}
+
+	itemRepo.On("GetByID", mock.Anything, "1", withProperties).Return(item("red"), nil).Once()
+	itemRepo.On("GetByID", mock.Anything, "1", withProperties).Return(item("blue"), nil).Once()
+	itemRepo.On("GetAll", mock.Anything, withInclude).Return(page("red"), nil).Once()
+	itemRepo.On("GetAll", mock.Anything, withInclude).Return(page("blue"), nil).Once()
+	itemRepo.On("GetAll", mock.Anything, byColor).Return(page("red"), nil).Once()
+	itemRepo.On("GetAll", mock.Anything, byColor).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, nil).Once()
+	itemRepo.On("Search", mock.Anything, search).Return(&domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{{Item: item("red"), Score: 1}}, Total: 1}, nil).Once()
+	itemRepo.On("Search", mock.Anything, search).Return(&domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{}}, nil).Once()
+	propertyRepo.On("GetByID", mock.Anything, "1", "p1", domain.QueryOptions{}).Return(property, nil).Once()
+	propertyRepo.On("GetByID", mock.Anything, "1", "p1", domain.QueryOptions{}).Return(&domain.ItemProperty{ID: "p1", ItemID: "1", Name: "color", Value: "blue"}, nil).Once()
+
+	// Cache every entry holding the property
+	found, _, err := itemSvc.GetItemByID(ctx, "1", withProperties)
+	require.NoError(t, err)
+	assert.Equal(t, "red", found.ItemProperties[0].Value)
+	items, _, err := itemSvc.GetAllItems(ctx, withInclude)
+	require.NoError(t, err)
+	assert.Equal(t, "red", items.Items[0].ItemProperties[0].Value)
+	items, _, err = itemSvc.GetAllItems(ctx, byColor)
+	require.NoError(t, err)
+	assert.Len(t, items.Items, 1)
+	results, err := itemSvc.SearchItems(ctx, search)
+	require.NoError(t, err)
+	assert.Len(t, results.Items, 1)
+	cachedProperty, err := propertySvc.GetItemPropertyByID(ctx, "1", "p1", domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "red", cachedProperty.Value)
+
+	// Changing the property drops each of them, so none is served stale
+	itemRepo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(item("red"), nil)
+	propertyRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	require.NoError(t, propertySvc.UpdateItemProperty(ctx, &domain.ItemProperty{ID: "p1", ItemID: "1", Name: "color", Value: "blue"}))
+
+	found, _, err = itemSvc.GetItemByID(ctx, "1", withProperties)
+	require.NoError(t, err)
+	assert.Equal(t, "blue", found.ItemProperties[0].Value)
+	items, _, err = itemSvc.GetAllItems(ctx, withInclude)
+	require.NoError(t, err)
+	assert.Equal(t, "blue", items.Items[0].ItemProperties[0].Value)
+	items, _, err = itemSvc.GetAllItems(ctx, byColor)
+	require.NoError(t, err)
+	assert.Empty(t, items.Items)
+	results, err = itemSvc.SearchItems(ctx, search)
+	require.NoError(t, err)
+	assert.Empty(t, results.Items)
+	cachedProperty, err = propertySvc.GetItemPropertyByID(ctx, "1", "p1", domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "blue", cachedProperty.Value)
+
+	itemRepo.AssertExpectations(t)
+	propertyRepo.AssertExpectations(t)
+}
+
+func TestItemService_DeleteItemInvalidatesItsProperties(t *testing.T) {
+	itemRepo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := newMemoryCache()
+	itemSvc := NewItemService(itemRepo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+	propertySvc := NewItemPropertyService(propertyRepo, itemRepo, cache, newTestValidator())
+	ctx := userCtx("user-1")
+
+	propertyRepo.On("GetByID", mock.Anything, "1", "p1", domain.QueryOptions{}).Return(&domain.ItemProperty{ID: "p1", ItemID: "1"}, nil).Once()
+	propertyRepo.On("GetByID", mock.Anything, "1", "p1", domain.QueryOptions{}).Return(nil, domain.ErrNotFound).Once()
+	itemRepo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(&domain.Item{ID: "1", OwnerID: "user-1", TenantID: "acme"}, nil)
+	itemRepo.On("Delete", mock.Anything, "1", int64(0)).Return(nil)
+
+	_, err := propertySvc.GetItemPropertyByID(ctx, "1", "p1", domain.QueryOptions{})
+	require.NoError(t, err)
+
+	// The properties are deleted with their item
+	require.NoError(t, itemSvc.DeleteItem(ctx, "1", 0))
+
+	_, err = propertySvc.GetItemPropertyByID(ctx, "1", "p1", domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	propertyRepo.AssertExpectations(t)
+}
+
+func TestItemService_ValidatorsFromCache(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	item := func(title string, version int64) *domain.Item {
+		return &domain.Item{ID: "1", Title: title, OwnerID: "user-1", TenantID: "acme", Version: version, UpdatedAt: updated}
+	}
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(item("Lamp", 1), nil).Once()
+	repo.On("GetAll", mock.Anything, testQuery).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{item("Lamp", 1)}, Total: 1}, nil).Once()
+
+	_, first, err := svc.GetItemByID(ctx, "1", domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.Digest)
+	assert.Equal(t, int64(1), first.Version)
+	assert.Equal(t, updated, first.LastModified.UTC())
+	_, firstPage, err := svc.GetAllItems(ctx, testQuery)
+	require.NoError(t, err)
+	assert.Zero(t, firstPage.Version)
+	assert.False(t, firstPage.LastModified.IsZero())
+
+	// A cached representation has the validators it had when it was read from the database
+	_, cached, err := svc.GetItemByID(ctx, "1", domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, first.Digest, cached.Digest)
+	_, cachedPage, err := svc.GetAllItems(ctx, testQuery)
+	require.NoError(t, err)
+	assert.Equal(t, firstPage, cachedPage)
+	repo.AssertNumberOfCalls(t, "GetByID", 1)
+	repo.AssertNumberOfCalls(t, "GetAll", 1)
+
+	// A change gives the item and the lists holding it new validators
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+	require.NoError(t, svc.UpdateItem(ctx, item("Desk lamp", 1)))
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(item("Desk lamp", 2), nil).Once()
+	repo.On("GetAll", mock.Anything, testQuery).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{item("Desk lamp", 2)}, Total: 1}, nil).Once()
+
+	_, changed, err := svc.GetItemByID(ctx, "1", domain.QueryOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Digest, changed.Digest)
+	assert.Equal(t, int64(2), changed.Version)
+	_, changedPage, err := svc.GetAllItems(ctx, testQuery)
+	require.NoError(t, err)
+	assert.NotEqual(t, firstPage.Digest, changedPage.Digest)
+	repo.AssertExpectations(t)
+}
@@ -11,43 +11,62 @@ import (
 )
 
 const (
-	// Cache key prefixes for item properties
+	// Cache key prefixes for item properties, followed by the caller's cache scope (see cacheScope)
 	itemPropertyCacheKeyPrefix    = "item_property:"
-	itemPropertiesListCacheKeyFmt = "item_properties:list:%s"
+	itemPropertiesListCacheKeyFmt = "item_properties:list:%s:%s"
 
 	// Default cache TTL for item properties
 	defaultPropertyCacheTTL = 5 * time.Minute
 )
 
+// propertiesQuery selects a page of a properties list, and is hashed into its cache key.
+type propertiesQuery struct {
+	Page    domain.PageRequest  `json:"page"`
+	Options domain.QueryOptions `json:"options"`
+}
+
 type itemPropertyService struct {
 	itemPropertyRepo domain.ItemPropertyRepository
+	itemRepo         domain.ItemRepository
 	cacheRepo        domain.CacheRepository
+	validator        domain.Validator
 }
 
-func NewItemPropertyService(itemPropertyRepo domain.ItemPropertyRepository, cacheRepo domain.CacheRepository) domain.ItemPropertyService {
+func NewItemPropertyService(itemPropertyRepo domain.ItemPropertyRepository, itemRepo domain.ItemRepository, cacheRepo domain.CacheRepository, validator domain.Validator) domain.ItemPropertyService {
 	return &itemPropertyService{
 		itemPropertyRepo: itemPropertyRepo,
+		itemRepo:         itemRepo,
 		cacheRepo:        cacheRepo,
+		validator:        validator,
 	}
 }
 
-// GetItemPropertiesByItemID retrieves all properties for an item with lazy caching strategy.
-func (s *itemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string) ([]*domain.ItemProperty, error) {
-	cacheKey := fmt.Sprintf(itemPropertiesListCacheKeyFmt, itemID)
+// GetItemPropertiesByItemID retrieves a page of the properties of an item with lazy caching strategy.
+func (s *itemPropertyService) GetItemPropertiesByItemID(ctx context.Context, itemID string, page domain.PageRequest, opts domain.QueryOptions) (*domain.Page[*domain.ItemProperty], error) {
+	scope, err := cacheScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listKey := itemPropertiesListCacheKey(scope, itemID)
+	cacheKey := queryCacheKey(listKey, cacheGeneration(ctx, s.cacheRepo, listKey, defaultPropertyCacheTTL), propertiesQuery{Page: page, Options: opts})
 
 	// Try to get from cache first
 	cached, err := s.cacheRepo.Get(ctx, cacheKey)
 	if err == nil && cached != "" {
-		var properties []*domain.ItemProperty
+		var properties domain.Page[*domain.ItemProperty]
 		if err := json.Unmarshal([]byte(cached), &properties); err == nil {
 			log.Printf("Cache hit for item properties list (item: %s)", itemID)
-			return properties, nil
+			return &properties, nil
 		}
 	}
 
 	// Cache miss - fetch from database
 	log.Printf("Cache miss for item properties list (item: %s), fetching from database", itemID)
-	properties, err := s.itemPropertyRepo.GetAllByItemID(ctx, itemID)
+	// An item that is not visible has no properties list rather than an empty one
+	if _, err := s.itemRepo.GetByID(ctx, itemID, domain.QueryOptions{}); err != nil {
+		return nil, err
+	}
+	properties, err := s.itemPropertyRepo.GetAllByItemID(ctx, itemID, page, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +82,16 @@ func (s *itemPropertyService) GetItemPropertiesByItemID(ctx context.Context, ite
 }
 
 // GetItemPropertyByID retrieves a single item property with lazy caching strategy.
-func (s *itemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string) (*domain.ItemProperty, error) {
-	cacheKey := fmt.Sprintf("%s%s:%s", itemPropertyCacheKeyPrefix, itemID, id)
+// Each shape of the property (fields in opts) is cached under a key of its own. Properties
+// share the generation of the properties list of their item, which every change to the
+// properties of the item or to the item itself invalidates.
+func (s *itemPropertyService) GetItemPropertyByID(ctx context.Context, itemID string, id string, opts domain.QueryOptions) (*domain.ItemProperty, error) {
+	scope, err := cacheScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listKey := itemPropertiesListCacheKey(scope, itemID)
+	cacheKey := queryCacheKey(itemPropertyCacheKey(scope, itemID, id), cacheGeneration(ctx, s.cacheRepo, listKey, defaultPropertyCacheTTL), opts)
 
 	// Try to get from cache first
 	cached, err := s.cacheRepo.Get(ctx, cacheKey)
@@ -78,7 +105,7 @@ func (s *itemPropertyService) GetItemPropertyByID(ctx context.Context, itemID st
 
 	// Cache miss - fetch from database
 	log.Printf("Cache miss for item property %s (item: %s), fetching from database", id, itemID)
-	property, err := s.itemPropertyRepo.GetByID(ctx, itemID, id)
+	property, err := s.itemPropertyRepo.GetByID(ctx, itemID, id, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -93,59 +120,145 @@ func (s *itemPropertyService) GetItemPropertyByID(ctx context.Context, itemID st
 	return property, nil
 }
 
-// CreateItemProperty creates a new item property and invalidates the properties list cache.
+// FindItemProperty retrieves an item property by ID alone, uncached.
+func (s *itemPropertyService) FindItemProperty(ctx context.Context, id string) (*domain.ItemProperty, error) {
+	return s.itemPropertyRepo.Find(ctx, id)
+}
+
+// CreateItemProperty creates a new item property and invalidates the caches that depend on the properties of its item.
 func (s *itemPropertyService) CreateItemProperty(ctx context.Context, itemProperty *domain.ItemProperty) error {
-	if err := s.itemPropertyRepo.Create(ctx, itemProperty); err != nil {
+	item, err := s.itemRepo.GetByID(ctx, itemProperty.ItemID, domain.QueryOptions{})
+	if err != nil {
 		return err
 	}
 
-	// Invalidate the item properties list cache since a new property was added
-	listCacheKey := fmt.Sprintf(itemPropertiesListCacheKeyFmt, itemProperty.ItemID)
-	if err := s.cacheRepo.Delete(ctx, listCacheKey); err != nil {
-		log.Printf("Failed to invalidate item properties list cache (item: %s): %v", itemProperty.ItemID, err)
+	if err := s.itemPropertyRepo.Create(ctx, itemProperty); err != nil {
+		return err
 	}
 
+	s.invalidateDependents(ctx, item)
+
 	return nil
 }
 
-// UpdateItemProperty updates an item property and invalidates both the single property cache and the list cache.
+// UpdateItemProperty updates an item property, at itemProperty.Version when it is set, and
+// invalidates the caches that depend on the properties of its item.
 func (s *itemPropertyService) UpdateItemProperty(ctx context.Context, itemProperty *domain.ItemProperty) error {
+	item, err := s.itemRepo.GetByID(ctx, itemProperty.ItemID, domain.QueryOptions{})
+	if err != nil {
+		return err
+	}
+
 	if err := s.itemPropertyRepo.Update(ctx, itemProperty); err != nil {
 		return err
 	}
 
-	// Invalidate the single property cache
-	cacheKey := fmt.Sprintf("%s%s:%s", itemPropertyCacheKeyPrefix, itemProperty.ItemID, itemProperty.ID)
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		log.Printf("Failed to invalidate item property cache %s (item: %s): %v", itemProperty.ID, itemProperty.ItemID, err)
+	s.invalidateDependents(ctx, item)
+
+	return nil
+}
+
+// PatchItemProperty applies the fields of patch in mask to the stored property, validates
+// the result and saves it like UpdateItemProperty. The stored property is read from the
+// database, not the cache, so the patch applies to its latest state. Like PatchItem, the
+// save is conditional on the version read, which must be patch.Version when it is set,
+// and is recorded in the audit log as a patch.
+func (s *itemPropertyService) PatchItemProperty(ctx context.Context, itemID string, id string, patch *domain.ItemProperty, mask domain.FieldMask) (*domain.ItemProperty, error) {
+	itemProperty, err := s.itemPropertyRepo.GetByID(ctx, itemID, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if patch.Version != 0 && patch.Version != itemProperty.Version {
+		return nil, fmt.Errorf("%w: item property %s is at version %d", domain.ErrPreconditionFailed, id, itemProperty.Version)
+	}
+
+	itemProperty.Patch(patch, mask)
+	if validationErrors := s.validator.Validate(itemProperty); len(validationErrors) > 0 {
+		return nil, validationErrors
+	}
+
+	if err := s.UpdateItemProperty(domain.ContextWithAuditAction(ctx, domain.AuditActionPatch), itemProperty); err != nil {
+		return nil, err
+	}
+	return itemProperty, nil
+}
+
+// DeleteItemProperty deletes an item property, at version when it is not 0, and invalidates
+// the caches that depend on the properties of its item.
+func (s *itemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string, version int64) error {
+	item, err := s.itemRepo.GetByID(ctx, itemID, domain.QueryOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := s.itemPropertyRepo.Delete(ctx, itemID, id, version); err != nil {
+		return err
 	}
 
-	// Invalidate the item properties list cache since a property was updated
-	listCacheKey := fmt.Sprintf(itemPropertiesListCacheKeyFmt, itemProperty.ItemID)
-	if err := s.cacheRepo.Delete(ctx, listCacheKey); err != nil {
-		log.Printf("Failed to invalidate item properties list cache (item: %s): %v", itemProperty.ItemID, err)
+	s.invalidateDependents(ctx, item)
+
+	return nil
+}
+
+// AttachItemProperties moves properties to the item and invalidates the caches that depend
+// on the properties of the item and of the items they were moved from.
+func (s *itemPropertyService) AttachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	item, err := s.itemRepo.GetByID(ctx, itemID, domain.QueryOptions{})
+	if err != nil {
+		return err
+	}
+
+	sources, err := s.itemPropertyRepo.Attach(ctx, itemID, ids)
+	if err != nil {
+		return err
 	}
 
+	s.invalidateDependents(ctx, append(sources, item)...)
+
 	return nil
 }
 
-// DeleteItemProperty deletes an item property and invalidates both the single property cache and the list cache.
-func (s *itemPropertyService) DeleteItemProperty(ctx context.Context, itemID string, id string) error {
-	if err := s.itemPropertyRepo.Delete(ctx, itemID, id); err != nil {
+// ReplaceItemProperties makes the properties identified by ids those of the item, deleting
+// its others, and invalidates the caches like AttachItemProperties.
+func (s *itemPropertyService) ReplaceItemProperties(ctx context.Context, itemID string, ids []string) error {
+	item, err := s.itemRepo.GetByID(ctx, itemID, domain.QueryOptions{})
+	if err != nil {
 		return err
 	}
 
-	// Invalidate the single property cache
-	cacheKey := fmt.Sprintf("%s%s:%s", itemPropertyCacheKeyPrefix, itemID, id)
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		log.Printf("Failed to invalidate item property cache %s (item: %s): %v", id, itemID, err)
+	sources, err := s.itemPropertyRepo.Replace(ctx, itemID, ids)
+	if err != nil {
+		return err
 	}
 
-	// Invalidate the item properties list cache since a property was deleted
-	listCacheKey := fmt.Sprintf(itemPropertiesListCacheKeyFmt, itemID)
-	if err := s.cacheRepo.Delete(ctx, listCacheKey); err != nil {
-		log.Printf("Failed to invalidate item properties list cache (item: %s): %v", itemID, err)
+	s.invalidateDependents(ctx, append(sources, item)...)
+
+	return nil
+}
+
+// DetachItemProperties deletes properties of the item and invalidates the caches that depend on them.
+func (s *itemPropertyService) DetachItemProperties(ctx context.Context, itemID string, ids []string) error {
+	item, err := s.itemRepo.GetByID(ctx, itemID, domain.QueryOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := s.itemPropertyRepo.Detach(ctx, itemID, ids); err != nil {
+		return err
 	}
 
+	s.invalidateDependents(ctx, item)
+
 	return nil
 }
+
+// invalidateDependents drops every cached entry that can hold the properties of an item:
+// its properties and properties lists, the item itself (which can include them) and the
+// items lists and searches (which can include, filter on or match them), for each item.
+func (s *itemPropertyService) invalidateDependents(ctx context.Context, items ...*domain.Item) {
+	for _, item := range items {
+		invalidateItemProperties(ctx, s.cacheRepo, item)
+		invalidateItem(ctx, s.cacheRepo, item)
+		invalidateItemLists(ctx, s.cacheRepo, item)
+	}
+}
@@ -16,16 +16,24 @@ type MockItemPropertyRepository struct {
 	mock.Mock
 }
 
-func (m *MockItemPropertyRepository) GetAllByItemID(ctx context.Context, itemID string) ([]*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID)
+func (m *MockItemPropertyRepository) GetAllByItemID(ctx context.Context, itemID string, page domain.PageRequest, opts domain.QueryOptions) (*domain.Page[*domain.ItemProperty], error) {
+	args := m.Called(ctx, itemID, page, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*domain.ItemProperty), args.Error(1)
+	return args.Get(0).(*domain.Page[*domain.ItemProperty]), args.Error(1)
 }
 
-func (m *MockItemPropertyRepository) GetByID(ctx context.Context, itemID string, id string) (*domain.ItemProperty, error) {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyRepository) GetByID(ctx context.Context, itemID string, id string, opts domain.QueryOptions) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, itemID, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemProperty), args.Error(1)
+}
+
+func (m *MockItemPropertyRepository) Find(ctx context.Context, id string) (*domain.ItemProperty, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -42,28 +50,55 @@ func (m *MockItemPropertyRepository) Update(ctx context.Context, itemProperty *d
 	return args.Error(0)
 }
 
-func (m *MockItemPropertyRepository) Delete(ctx context.Context, itemID string, id string) error {
-	args := m.Called(ctx, itemID, id)
+func (m *MockItemPropertyRepository) Delete(ctx context.Context, itemID string, id string, version int64) error {
+	args := m.Called(ctx, itemID, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemPropertyRepository) Attach(ctx context.Context, itemID string, ids []string) ([]*domain.Item, error) {
+	args := m.Called(ctx, itemID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Item), args.Error(1)
+}
+
+func (m *MockItemPropertyRepository) Replace(ctx context.Context, itemID string, ids []string) ([]*domain.Item, error) {
+	args := m.Called(ctx, itemID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Item), args.Error(1)
+}
+
+func (m *MockItemPropertyRepository) Detach(ctx context.Context, itemID string, ids []string) error {
+	args := m.Called(ctx, itemID, ids)
 	return args.Error(0)
 }
 
 func TestItemPropertyService_GetItemPropertiesByItemID_CacheMiss(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
-	expectedProperties := []*domain.ItemProperty{
-		{ID: "prop-1", ItemID: itemID, Name: "color", Value: "red"},
-		{ID: "prop-2", ItemID: itemID, Name: "size", Value: "large"},
+	expectedProperties := &domain.Page[*domain.ItemProperty]{
+		Items: []*domain.ItemProperty{
+			{ID: "prop-1", ItemID: itemID, Name: "color", Value: "red"},
+			{ID: "prop-2", ItemID: itemID, Name: "size", Value: "large"},
+		},
+		Total: 2,
 	}
 
 	// Cache miss scenario
-	cache.On("Get", mock.Anything, "item_properties:list:item-123").Return("", errors.New("cache miss"))
-	repo.On("GetAllByItemID", mock.Anything, itemID).Return(expectedProperties, nil)
-	cache.On("Set", mock.Anything, "item_properties:list:item-123", mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_properties:list:tenant:acme:owner:user-1:item-123", propertiesQuery{Page: testPage})).Return("", errors.New("cache miss"))
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("GetAllByItemID", mock.Anything, itemID, testPage, domain.QueryOptions{}).Return(expectedProperties, nil)
+	cache.On("Set", mock.Anything, pageKey("item_properties:list:tenant:acme:owner:user-1:item-123", propertiesQuery{Page: testPage}), mock.Anything, 5*time.Minute).Return(nil)
 
-	properties, err := svc.GetItemPropertiesByItemID(context.Background(), itemID)
+	properties, err := svc.GetItemPropertiesByItemID(userCtx("user-1"), itemID, testPage, domain.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedProperties, properties)
@@ -73,37 +108,42 @@ func TestItemPropertyService_GetItemPropertiesByItemID_CacheMiss(t *testing.T) {
 
 func TestItemPropertyService_GetItemPropertiesByItemID_CacheHit(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
-	cachedJSON := `[{"ID":"prop-1","ItemID":"item-123","Name":"color","Value":"red"}]`
+	cachedJSON := `{"items":[{"ID":"prop-1","ItemID":"item-123","Name":"color","Value":"red"}],"total":1}`
 
 	// Cache hit scenario - repo should NOT be called
-	cache.On("Get", mock.Anything, "item_properties:list:item-123").Return(cachedJSON, nil)
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_properties:list:tenant:acme:owner:user-1:item-123", propertiesQuery{Page: testPage})).Return(cachedJSON, nil)
 
-	properties, err := svc.GetItemPropertiesByItemID(context.Background(), itemID)
+	properties, err := svc.GetItemPropertiesByItemID(userCtx("user-1"), itemID, testPage, domain.QueryOptions{})
 
 	assert.NoError(t, err)
-	assert.Len(t, properties, 1)
-	assert.Equal(t, "prop-1", properties[0].ID)
-	assert.Equal(t, "color", properties[0].Name)
+	assert.Len(t, properties.Items, 1)
+	assert.Equal(t, "prop-1", properties.Items[0].ID)
+	assert.Equal(t, "color", properties.Items[0].Name)
 	cache.AssertExpectations(t)
-	repo.AssertNotCalled(t, "GetAllByItemID", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetAllByItemID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestItemPropertyService_GetItemPropertiesByItemID_RepoError(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 
 	// Cache miss, then repo error
-	cache.On("Get", mock.Anything, "item_properties:list:item-123").Return("", errors.New("cache miss"))
-	repo.On("GetAllByItemID", mock.Anything, itemID).Return(nil, errors.New("database error"))
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_properties:list:tenant:acme:owner:user-1:item-123", propertiesQuery{Page: testPage})).Return("", errors.New("cache miss"))
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("GetAllByItemID", mock.Anything, itemID, testPage, domain.QueryOptions{}).Return(nil, errors.New("database error"))
 
-	properties, err := svc.GetItemPropertiesByItemID(context.Background(), itemID)
+	properties, err := svc.GetItemPropertiesByItemID(userCtx("user-1"), itemID, testPage, domain.QueryOptions{})
 
 	assert.Error(t, err)
 	assert.Nil(t, properties)
@@ -113,19 +153,21 @@ func TestItemPropertyService_GetItemPropertiesByItemID_RepoError(t *testing.T) {
 
 func TestItemPropertyService_GetItemPropertyByID_CacheMiss(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 	expectedProperty := &domain.ItemProperty{ID: propID, ItemID: itemID, Name: "color", Value: "red"}
 
 	// Cache miss scenario
-	cache.On("Get", mock.Anything, "item_property:item-123:prop-1").Return("", errors.New("cache miss"))
-	repo.On("GetByID", mock.Anything, itemID, propID).Return(expectedProperty, nil)
-	cache.On("Set", mock.Anything, "item_property:item-123:prop-1", mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_property:tenant:acme:owner:user-1:item-123:prop-1", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, itemID, propID, domain.QueryOptions{}).Return(expectedProperty, nil)
+	cache.On("Set", mock.Anything, pageKey("item_property:tenant:acme:owner:user-1:item-123:prop-1", domain.QueryOptions{}), mock.Anything, 5*time.Minute).Return(nil)
 
-	property, err := svc.GetItemPropertyByID(context.Background(), itemID, propID)
+	property, err := svc.GetItemPropertyByID(userCtx("user-1"), itemID, propID, domain.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedProperty, property)
@@ -135,17 +177,19 @@ func TestItemPropertyService_GetItemPropertyByID_CacheMiss(t *testing.T) {
 
 func TestItemPropertyService_GetItemPropertyByID_CacheHit(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 	cachedJSON := `{"ID":"prop-1","ItemID":"item-123","Name":"color","Value":"red"}`
 
 	// Cache hit scenario - repo should NOT be called
-	cache.On("Get", mock.Anything, "item_property:item-123:prop-1").Return(cachedJSON, nil)
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_property:tenant:acme:owner:user-1:item-123:prop-1", domain.QueryOptions{})).Return(cachedJSON, nil)
 
-	property, err := svc.GetItemPropertyByID(context.Background(), itemID, propID)
+	property, err := svc.GetItemPropertyByID(userCtx("user-1"), itemID, propID, domain.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, propID, property.ID)
@@ -156,17 +200,19 @@ func TestItemPropertyService_GetItemPropertyByID_CacheHit(t *testing.T) {
 
 func TestItemPropertyService_GetItemPropertyByID_NotFound(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-nonexistent"
 
 	// Cache miss, then repo returns not found
-	cache.On("Get", mock.Anything, "item_property:item-123:prop-nonexistent").Return("", errors.New("cache miss"))
-	repo.On("GetByID", mock.Anything, itemID, propID).Return(nil, errors.New("not found"))
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_property:tenant:acme:owner:user-1:item-123:prop-nonexistent", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, itemID, propID, domain.QueryOptions{}).Return(nil, errors.New("not found"))
 
-	property, err := svc.GetItemPropertyByID(context.Background(), itemID, propID)
+	property, err := svc.GetItemPropertyByID(userCtx("user-1"), itemID, propID, domain.QueryOptions{})
 
 	assert.Error(t, err)
 	assert.Nil(t, property)
@@ -176,17 +222,24 @@ func TestItemPropertyService_GetItemPropertyByID_NotFound(t *testing.T) {
 
 func TestItemPropertyService_CreateItemProperty(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	property := &domain.ItemProperty{ID: "prop-1", ItemID: itemID, Name: "color", Value: "red"}
 
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
 	repo.On("Create", mock.Anything, property).Return(nil)
-	// Cache invalidation for item properties list
-	cache.On("Delete", mock.Anything, "item_properties:list:item-123").Return(nil)
+	// Every cached entry holding the properties of the item, in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
 
-	err := svc.CreateItemProperty(context.Background(), property)
+	err := svc.CreateItemProperty(userCtx("user-1"), property)
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
@@ -195,15 +248,17 @@ func TestItemPropertyService_CreateItemProperty(t *testing.T) {
 
 func TestItemPropertyService_CreateItemProperty_RepoError(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	property := &domain.ItemProperty{ID: "prop-1", ItemID: itemID, Name: "color", Value: "red"}
 
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
 	repo.On("Create", mock.Anything, property).Return(errors.New("database error"))
 
-	err := svc.CreateItemProperty(context.Background(), property)
+	err := svc.CreateItemProperty(userCtx("user-1"), property)
 
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
@@ -213,20 +268,25 @@ func TestItemPropertyService_CreateItemProperty_RepoError(t *testing.T) {
 
 func TestItemPropertyService_UpdateItemProperty(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 	property := &domain.ItemProperty{ID: propID, ItemID: itemID, Name: "color", Value: "blue"}
 
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
 	repo.On("Update", mock.Anything, property).Return(nil)
-	// Cache invalidation for single property
-	cache.On("Delete", mock.Anything, "item_property:item-123:prop-1").Return(nil)
-	// Cache invalidation for item properties list
-	cache.On("Delete", mock.Anything, "item_properties:list:item-123").Return(nil)
+	// Every cached entry holding the properties of the item, in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
 
-	err := svc.UpdateItemProperty(context.Background(), property)
+	err := svc.UpdateItemProperty(userCtx("user-1"), property)
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
@@ -235,16 +295,18 @@ func TestItemPropertyService_UpdateItemProperty(t *testing.T) {
 
 func TestItemPropertyService_UpdateItemProperty_RepoError(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 	property := &domain.ItemProperty{ID: propID, ItemID: itemID, Name: "color", Value: "blue"}
 
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
 	repo.On("Update", mock.Anything, property).Return(errors.New("database error"))
 
-	err := svc.UpdateItemProperty(context.Background(), property)
+	err := svc.UpdateItemProperty(userCtx("user-1"), property)
 
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
@@ -254,19 +316,24 @@ func TestItemPropertyService_UpdateItemProperty_RepoError(t *testing.T) {
 
 func TestItemPropertyService_DeleteItemProperty(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 
-	repo.On("Delete", mock.Anything, itemID, propID).Return(nil)
-	// Cache invalidation for single property
-	cache.On("Delete", mock.Anything, "item_property:item-123:prop-1").Return(nil)
-	// Cache invalidation for item properties list
-	cache.On("Delete", mock.Anything, "item_properties:list:item-123").Return(nil)
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Delete", mock.Anything, itemID, propID, int64(0)).Return(nil)
+	// Every cached entry holding the properties of the item, in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
 
-	err := svc.DeleteItemProperty(context.Background(), itemID, propID)
+	err := svc.DeleteItemProperty(userCtx("user-1"), itemID, propID, 0)
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
@@ -275,18 +342,194 @@ func TestItemPropertyService_DeleteItemProperty(t *testing.T) {
 
 func TestItemPropertyService_DeleteItemProperty_RepoError(t *testing.T) {
 	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemPropertyService(repo, cache)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
 
 	itemID := "item-123"
 	propID := "prop-1"
 
-	repo.On("Delete", mock.Anything, itemID, propID).Return(errors.New("database error"))
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Delete", mock.Anything, itemID, propID, int64(0)).Return(errors.New("database error"))
 
-	err := svc.DeleteItemProperty(context.Background(), itemID, propID)
+	err := svc.DeleteItemProperty(userCtx("user-1"), itemID, propID, 0)
 
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
 	// Cache should NOT be invalidated on error
 	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 }
+
+func TestItemPropertyService_AdminMutationInvalidatesOwnerCache(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	itemID := "item-123"
+	propID := "prop-1"
+
+	// An admin deleting someone else's property must drop the owner's cached copies too
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(&domain.Item{ID: itemID, OwnerID: "alice", TenantID: "acme"}, nil)
+	repo.On("Delete", mock.Anything, itemID, propID, int64(0)).Return(nil)
+	// Every cached entry holding the properties of the item, in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:alice:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:alice:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:item-123").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:alice").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
+
+	err := svc.DeleteItemProperty(adminCtx(), itemID, propID, 0)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestItemPropertyService_MutationOnInvisibleItem(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	itemID := "item-123"
+	property := &domain.ItemProperty{ID: "prop-1", ItemID: itemID, Name: "color", Value: "red"}
+
+	// The parent item belongs to someone else, so the scoped lookup finds nothing
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(nil, errors.New("not found"))
+
+	assert.Error(t, svc.CreateItemProperty(userCtx("bob"), property))
+	assert.Error(t, svc.UpdateItemProperty(userCtx("bob"), property))
+	assert.Error(t, svc.DeleteItemProperty(userCtx("bob"), itemID, "prop-1", 0))
+
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestItemPropertyService_GetItemPropertiesByItemID_ItemNotVisible(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	itemID := "item-123"
+
+	// The item belongs to another tenant or owner: not found rather than an empty list
+	cache.On("Get", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item_properties:list:tenant:acme:owner:user-1:item-123", propertiesQuery{Page: testPage})).Return("", errors.New("cache miss"))
+	itemRepo.On("GetByID", mock.Anything, itemID, domain.QueryOptions{}).Return(nil, domain.ErrNotFound)
+
+	properties, err := svc.GetItemPropertiesByItemID(userCtx("user-1"), itemID, testPage, domain.QueryOptions{})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, properties)
+	repo.AssertNotCalled(t, "GetAllByItemID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemPropertyService_PatchItemProperty(t *testing.T) {
+	propertyRepo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	svc := NewItemPropertyService(propertyRepo, itemRepo, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	propertyRepo.On("GetByID", mock.Anything, itemUUID, propertyUUID, domain.QueryOptions{}).
+		Return(&domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID, Name: "color", Value: "red"}, nil)
+	itemRepo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(&domain.Item{ID: itemUUID, OwnerID: "user-1", TenantID: "acme"}, nil)
+	propertyRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	property, err := svc.PatchItemProperty(ctx, itemUUID, propertyUUID, &domain.ItemProperty{Value: "blue"}, domain.FieldMask{"value"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID, Name: "color", Value: "blue"}, property)
+	propertyRepo.AssertCalled(t, "Update", mock.Anything, property)
+	// The audit log records the save as a patch
+	propertyRepo.AssertCalled(t, "Update", mock.MatchedBy(func(ctx context.Context) bool {
+		action, _ := domain.AuditActionFromContext(ctx)
+		return action == domain.AuditActionPatch
+	}), property)
+}
+
+func TestItemPropertyService_PatchItemProperty_ValidatesMergedProperty(t *testing.T) {
+	propertyRepo := new(MockItemPropertyRepository)
+	svc := NewItemPropertyService(propertyRepo, new(MockItemRepository), newMemoryCache(), newTestValidator())
+
+	propertyRepo.On("GetByID", mock.Anything, itemUUID, propertyUUID, domain.QueryOptions{}).
+		Return(&domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID, Name: "color", Value: "red"}, nil)
+
+	_, err := svc.PatchItemProperty(userCtx("user-1"), itemUUID, propertyUUID, &domain.ItemProperty{}, domain.FieldMask{"name", "value"})
+
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	propertyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestItemPropertyService_PatchItemProperty_VersionMismatch(t *testing.T) {
+	propertyRepo := new(MockItemPropertyRepository)
+	svc := NewItemPropertyService(propertyRepo, new(MockItemRepository), newMemoryCache(), newTestValidator())
+
+	propertyRepo.On("GetByID", mock.Anything, itemUUID, propertyUUID, domain.QueryOptions{}).
+		Return(&domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID, Name: "color", Value: "red", Version: 2}, nil)
+
+	_, err := svc.PatchItemProperty(userCtx("user-1"), itemUUID, propertyUUID, &domain.ItemProperty{Value: "blue", Version: 1}, domain.FieldMask{"value"})
+
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	propertyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestItemPropertyService_AttachItemProperties(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	ids := []string{"prop-1", "prop-2"}
+	itemRepo.On("GetByID", mock.Anything, "item-123", domain.QueryOptions{}).Return(&domain.Item{ID: "item-123", OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Attach", mock.Anything, "item-123", ids).Return([]*domain.Item{{ID: "item-456", OwnerID: "user-1", TenantID: "acme"}}, nil)
+	cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	err := svc.AttachItemProperties(userCtx("user-1"), "item-123", ids)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	// The caches of the item the properties moved from are dropped too
+	cache.AssertCalled(t, "Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123")
+	cache.AssertCalled(t, "Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-456")
+	cache.AssertCalled(t, "Delete", mock.Anything, "item:tenant:acme:owner:user-1:item-456")
+}
+
+func TestItemPropertyService_ReplaceItemProperties_RepoError(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	ids := []string{"prop-1"}
+	itemRepo.On("GetByID", mock.Anything, "item-123", domain.QueryOptions{}).Return(&domain.Item{ID: "item-123", OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Replace", mock.Anything, "item-123", ids).Return(nil, domain.ErrNotFound)
+
+	err := svc.ReplaceItemProperties(userCtx("user-1"), "item-123", ids)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestItemPropertyService_DetachItemProperties(t *testing.T) {
+	repo := new(MockItemPropertyRepository)
+	itemRepo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemPropertyService(repo, itemRepo, cache, newTestValidator())
+
+	ids := []string{"prop-1"}
+	itemRepo.On("GetByID", mock.Anything, "item-123", domain.QueryOptions{}).Return(&domain.Item{ID: "item-123", OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Detach", mock.Anything, "item-123", ids).Return(nil)
+	cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	err := svc.DetachItemProperties(userCtx("user-1"), "item-123", ids)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertCalled(t, "Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:item-123")
+}
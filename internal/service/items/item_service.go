@@ -11,60 +11,123 @@ import (
 )
 
 const (
-	// Cache key prefixes
-	itemCacheKeyPrefix = "item:"
-	itemsListCacheKey  = "items:list"
+	// Cache key prefixes, followed by the caller's cache scope (see cacheScope)
+	itemCacheKeyPrefix      = "item:"
+	itemsListCacheKeyPrefix = "items:list:"
+	// Appended to the items list key of a scope for the search results of the scope
+	itemsSearchCacheKeySuffix = ":search"
 
 	// Default cache TTL
 	defaultCacheTTL = 5 * time.Minute
 )
 
 type itemService struct {
-	itemRepo  domain.ItemRepository
-	cacheRepo domain.CacheRepository
+	itemRepo         domain.ItemRepository
+	itemPropertyRepo domain.ItemPropertyRepository
+	transactions     domain.TransactionManager
+	cacheRepo        domain.CacheRepository
+	validator        domain.Validator
 }
 
-func NewItemService(itemRepo domain.ItemRepository, cacheRepo domain.CacheRepository) domain.ItemService {
+func NewItemService(itemRepo domain.ItemRepository, itemPropertyRepo domain.ItemPropertyRepository, transactions domain.TransactionManager, cacheRepo domain.CacheRepository, validator domain.Validator) domain.ItemService {
 	return &itemService{
-		itemRepo:  itemRepo,
-		cacheRepo: cacheRepo,
+		itemRepo:         itemRepo,
+		itemPropertyRepo: itemPropertyRepo,
+		transactions:     transactions,
+		cacheRepo:        cacheRepo,
+		validator:        validator,
 	}
 }
 
-// GetAllItems retrieves all items with lazy caching strategy.
+// GetAllItems retrieves a page of the items visible to the caller that match the query, with lazy caching strategy.
 // It first checks the cache, and if not found, fetches from the database and caches the result.
-func (s *itemService) GetAllItems(ctx context.Context) ([]*domain.Item, error) {
+// The page was last modified when the items lists of the caller were last invalidated (see listValidators).
+func (s *itemService) GetAllItems(ctx context.Context, query domain.ItemQuery) (*domain.Page[*domain.Item], domain.Validators, error) {
+	scope, err := cacheScope(ctx)
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+	listKey := itemsListCacheKey(scope)
+	generation := cacheGeneration(ctx, s.cacheRepo, listKey, defaultCacheTTL)
+	cacheKey := queryCacheKey(listKey, generation, query)
+
 	// Try to get from cache first
-	cached, err := s.cacheRepo.Get(ctx, itemsListCacheKey)
+	cached, err := s.cacheRepo.Get(ctx, cacheKey)
 	if err == nil && cached != "" {
-		var items []*domain.Item
+		var items domain.Page[*domain.Item]
 		if err := json.Unmarshal([]byte(cached), &items); err == nil {
-			log.Printf("Cache hit for items list")
-			return items, nil
+			log.Printf("Cache hit for items list (%s)", scope)
+			return &items, listValidators([]byte(cached), generation), nil
+		}
+	}
+
+	// Cache miss - fetch from database
+	log.Printf("Cache miss for items list (%s), fetching from database", scope)
+	items, err := s.itemRepo.GetAll(ctx, query)
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+
+	// Cache the result
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+	if err := s.cacheRepo.Set(ctx, cacheKey, string(data), defaultCacheTTL); err != nil {
+		log.Printf("Failed to cache items list (%s): %v", scope, err)
+	}
+
+	return items, listValidators(data, generation), nil
+}
+
+// SearchItems retrieves a page of the items visible to the caller that match the search, with lazy caching strategy.
+// Results are cached with the pages of the items list, so that changing an item invalidates both.
+func (s *itemService) SearchItems(ctx context.Context, search domain.ItemSearch) (*domain.Page[*domain.ItemSearchResult], error) {
+	scope, err := cacheScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listKey := itemsListCacheKey(scope)
+	cacheKey := queryCacheKey(listKey+itemsSearchCacheKeySuffix, cacheGeneration(ctx, s.cacheRepo, listKey, defaultCacheTTL), search)
+
+	// Try to get from cache first
+	cached, err := s.cacheRepo.Get(ctx, cacheKey)
+	if err == nil && cached != "" {
+		var results domain.Page[*domain.ItemSearchResult]
+		if err := json.Unmarshal([]byte(cached), &results); err == nil {
+			log.Printf("Cache hit for items search (%s)", scope)
+			return &results, nil
 		}
 	}
 
 	// Cache miss - fetch from database
-	log.Printf("Cache miss for items list, fetching from database")
-	items, err := s.itemRepo.GetAll(ctx)
+	log.Printf("Cache miss for items search (%s), fetching from database", scope)
+	results, err := s.itemRepo.Search(ctx, search)
 	if err != nil {
 		return nil, err
 	}
 
 	// Cache the result
-	if data, err := json.Marshal(items); err == nil {
-		if err := s.cacheRepo.Set(ctx, itemsListCacheKey, string(data), defaultCacheTTL); err != nil {
-			log.Printf("Failed to cache items list: %v", err)
+	if data, err := json.Marshal(results); err == nil {
+		if err := s.cacheRepo.Set(ctx, cacheKey, string(data), defaultCacheTTL); err != nil {
+			log.Printf("Failed to cache items search (%s): %v", scope, err)
 		}
 	}
 
-	return items, nil
+	return results, nil
 }
 
 // GetItemByID retrieves an item by ID with lazy caching strategy.
 // It first checks the cache, and if not found, fetches from the database and caches the result.
-func (s *itemService) GetItemByID(ctx context.Context, id string) (*domain.Item, error) {
-	cacheKey := fmt.Sprintf("%s%s", itemCacheKeyPrefix, id)
+// Each shape of the item (includes and fields in opts) is cached under a key of its own.
+func (s *itemService) GetItemByID(ctx context.Context, id string, opts domain.QueryOptions) (*domain.Item, domain.Validators, error) {
+	scope, err := cacheScope(ctx)
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+	itemKey := itemCacheKey(scope, id)
+	generation := cacheGeneration(ctx, s.cacheRepo, itemKey, defaultCacheTTL)
+	cacheKey := queryCacheKey(itemKey, generation, opts)
 
 	// Try to get from cache first
 	cached, err := s.cacheRepo.Get(ctx, cacheKey)
@@ -72,77 +135,211 @@ func (s *itemService) GetItemByID(ctx context.Context, id string) (*domain.Item,
 		var item domain.Item
 		if err := json.Unmarshal([]byte(cached), &item); err == nil {
 			log.Printf("Cache hit for item %s", id)
-			return &item, nil
+			return &item, itemValidators(&item, []byte(cached), opts, generation), nil
 		}
 	}
 
 	// Cache miss - fetch from database
 	log.Printf("Cache miss for item %s, fetching from database", id)
-	item, err := s.itemRepo.GetByID(ctx, id)
+	item, err := s.itemRepo.GetByID(ctx, id, opts)
 	if err != nil {
-		return nil, err
+		return nil, domain.Validators{}, err
 	}
 
 	// Cache the result
-	if data, err := json.Marshal(item); err == nil {
-		if err := s.cacheRepo.Set(ctx, cacheKey, string(data), defaultCacheTTL); err != nil {
-			log.Printf("Failed to cache item %s: %v", id, err)
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, domain.Validators{}, err
+	}
+	if err := s.cacheRepo.Set(ctx, cacheKey, string(data), defaultCacheTTL); err != nil {
+		log.Printf("Failed to cache item %s: %v", id, err)
+	}
+
+	return item, itemValidators(item, data, opts, generation), nil
+}
+
+// CreateItem creates a new item along with its properties, in one transaction: either
+// all of them are stored or none is. The items list caches that can contain the item are
+// invalidated once the transaction commits.
+func (s *itemService) CreateItem(ctx context.Context, item *domain.Item) error {
+	// The properties are created through their own repository, which checks and records each of them
+	properties := item.ItemProperties
+	item.ItemProperties = nil
+	defer func() { item.ItemProperties = properties }()
+
+	return s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.itemRepo.Create(ctx, item); err != nil {
+			return err
 		}
+		for _, property := range properties {
+			property.ItemID = item.ID
+			if err := s.itemPropertyRepo.Create(ctx, property); err != nil {
+				return err
+			}
+		}
+
+		// Invalidate the items list cache since a new item was added
+		invalidateItemLists(ctx, s.cacheRepo, item)
+		return nil
+	})
+}
+
+// UpdateItem updates an item, at item.Version when it is set, and invalidates both the single
+// item caches and the items list caches.
+func (s *itemService) UpdateItem(ctx context.Context, item *domain.Item) error {
+	if err := s.itemRepo.Update(ctx, item); err != nil {
+		return err
 	}
 
+	// The repository fills in the tenant, owner and new version of the stored item
+	invalidateItem(ctx, s.cacheRepo, item)
+	invalidateItemLists(ctx, s.cacheRepo, item)
+
+	return nil
+}
+
+// PatchItem applies the fields of patch in mask to the stored item, validates the
+// result and saves it like UpdateItem. The stored item is read from the database,
+// not the cache, so the patch applies to its latest state. The save is conditional
+// on the version read: an item changed in between is not overwritten and the patch
+// fails with domain.ErrPreconditionFailed, as it does when patch.Version is set
+// and is not the version read. The save is recorded in the audit log as a patch.
+func (s *itemService) PatchItem(ctx context.Context, id string, patch *domain.Item, mask domain.FieldMask) (*domain.Item, error) {
+	item, err := s.itemRepo.GetByID(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if patch.Version != 0 && patch.Version != item.Version {
+		return nil, fmt.Errorf("%w: item %s is at version %d", domain.ErrPreconditionFailed, id, item.Version)
+	}
+
+	item.Patch(patch, mask)
+	if validationErrors := s.validator.Validate(item); len(validationErrors) > 0 {
+		return nil, validationErrors
+	}
+
+	if err := s.UpdateItem(domain.ContextWithAuditAction(ctx, domain.AuditActionPatch), item); err != nil {
+		return nil, err
+	}
 	return item, nil
 }
 
-// CreateItem creates a new item and invalidates the items list cache.
-func (s *itemService) CreateItem(ctx context.Context, item *domain.Item) error {
-	if err := s.itemRepo.Create(ctx, item); err != nil {
+// DeleteItem soft-deletes an item, at version when it is not 0, and invalidates the single item
+// caches, the items list caches and the caches of its properties, which are deleted with it.
+func (s *itemService) DeleteItem(ctx context.Context, id string, version int64) error {
+	// Look the item up first: its owner's caches have to be invalidated as well
+	item, err := s.itemRepo.GetByID(ctx, id, domain.QueryOptions{})
+	if err != nil {
 		return err
 	}
 
-	// Invalidate the items list cache since a new item was added
-	if err := s.cacheRepo.Delete(ctx, itemsListCacheKey); err != nil {
-		log.Printf("Failed to invalidate items list cache: %v", err)
+	if err := s.itemRepo.Delete(ctx, id, version); err != nil {
+		return err
 	}
 
+	invalidateItem(ctx, s.cacheRepo, item)
+	invalidateItemLists(ctx, s.cacheRepo, item)
+	invalidateItemProperties(ctx, s.cacheRepo, item)
+
 	return nil
 }
 
-// UpdateItem updates an item and invalidates both the single item cache and the items list cache.
-func (s *itemService) UpdateItem(ctx context.Context, item *domain.Item) error {
-	if err := s.itemRepo.Update(ctx, item); err != nil {
-		return err
+// ListDeletedItems retrieves a page of the soft-deleted items visible to the caller.
+// The trash is not cached: it is only read by admins, rarely.
+func (s *itemService) ListDeletedItems(ctx context.Context, page domain.PageRequest) (*domain.Page[*domain.Item], error) {
+	return s.itemRepo.GetDeleted(ctx, page)
+}
+
+// RestoreItem undeletes an item and the properties deleted with it, and invalidates the
+// single item caches, the items list caches and the caches of its properties, which
+// were filled without them while the item was deleted.
+func (s *itemService) RestoreItem(ctx context.Context, id string) (*domain.Item, error) {
+	if err := s.itemRepo.Restore(ctx, id); err != nil {
+		return nil, err
 	}
 
-	// Invalidate the single item cache
-	cacheKey := fmt.Sprintf("%s%s", itemCacheKeyPrefix, item.ID)
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		log.Printf("Failed to invalidate item cache %s: %v", item.ID, err)
+	item, err := s.itemRepo.GetByID(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, err
 	}
+	invalidateItem(ctx, s.cacheRepo, item)
+	invalidateItemLists(ctx, s.cacheRepo, item)
+	invalidateItemProperties(ctx, s.cacheRepo, item)
+
+	return item, nil
+}
+
+// PurgeItem permanently deletes a soft-deleted item and its properties. Their caches
+// were invalidated when the item was deleted, so none is left to invalidate.
+func (s *itemService) PurgeItem(ctx context.Context, id string) error {
+	return s.itemRepo.Purge(ctx, id)
+}
 
-	// Invalidate the items list cache since an item was updated
-	if err := s.cacheRepo.Delete(ctx, itemsListCacheKey); err != nil {
-		log.Printf("Failed to invalidate items list cache: %v", err)
+// ListItemRevisions retrieves a page of the revisions of an item, most recent first.
+// Revisions are not cached: they never change, but are rarely read.
+func (s *itemService) ListItemRevisions(ctx context.Context, id string, page domain.PageRequest) (*domain.Page[*domain.ItemRevision], error) {
+	revisions, err := s.itemRepo.GetRevisions(ctx, id, page)
+	if err != nil {
+		return nil, err
+	}
+	if revisions.Total == 0 {
+		// Items stored before revisions were introduced have none yet; tell them
+		// apart from items that do not exist
+		if _, err := s.itemRepo.GetByID(ctx, id, domain.QueryOptions{}); err != nil {
+			return nil, err
+		}
 	}
+	return revisions, nil
+}
 
-	return nil
+// GetItemAsOf retrieves an item as its latest revision at the given time recorded it.
+// An item that did not exist yet or was deleted then is domain.ErrNotFound.
+func (s *itemService) GetItemAsOf(ctx context.Context, id string, at time.Time, opts domain.QueryOptions) (*domain.Item, error) {
+	revision, err := s.itemRepo.GetRevisionAt(ctx, id, at)
+	if err != nil {
+		return nil, err
+	}
+	if revision.Deleted() {
+		return nil, fmt.Errorf("%w: item %s was deleted at %s", domain.ErrNotFound, id, at.Format(time.RFC3339))
+	}
+
+	item := revision.Item
+	if !opts.Includes(domain.ItemIncludeProperties) {
+		item.ItemProperties = nil
+	}
+	return item, nil
 }
 
-// DeleteItem deletes an item and invalidates both the single item cache and the items list cache.
-func (s *itemService) DeleteItem(ctx context.Context, id string) error {
-	if err := s.itemRepo.Delete(ctx, id); err != nil {
-		return err
+// RevertItem sets the attributes of an item back to those recorded by one of its revisions
+// and saves it like UpdateItem, so the revert is a change of its own: it gets a new version
+// and revision and is recorded in the audit log as a revert. Properties are not reverted;
+// they are changed through their own endpoints. Like PatchItem, the save is conditional on
+// the version read, and on version when it is not 0.
+func (s *itemService) RevertItem(ctx context.Context, id string, revision int64, version int64) (*domain.Item, error) {
+	item, err := s.itemRepo.GetByID(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != item.Version {
+		return nil, fmt.Errorf("%w: item %s is at version %d", domain.ErrPreconditionFailed, id, item.Version)
 	}
 
-	// Invalidate the single item cache
-	cacheKey := fmt.Sprintf("%s%s", itemCacheKeyPrefix, id)
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		log.Printf("Failed to invalidate item cache %s: %v", id, err)
+	target, err := s.itemRepo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	if target.Item == nil {
+		return nil, fmt.Errorf("%w: revision %d of item %s has no snapshot", domain.ErrNotFound, revision, id)
 	}
 
-	// Invalidate the items list cache since an item was deleted
-	if err := s.cacheRepo.Delete(ctx, itemsListCacheKey); err != nil {
-		log.Printf("Failed to invalidate items list cache: %v", err)
+	item.Title = target.Item.Title
+	item.Description = target.Item.Description
+	if validationErrors := s.validator.Validate(item); len(validationErrors) > 0 {
+		return nil, validationErrors
 	}
 
-	return nil
+	if err := s.UpdateItem(domain.ContextWithAuditAction(ctx, domain.AuditActionRevert), item); err != nil {
+		return nil, err
+	}
+	return item, nil
 }
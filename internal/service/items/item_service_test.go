@@ -3,12 +3,15 @@ package items
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/gadz82/go-api-boilerplate/internal/validation"
+	"gorm.io/gorm"
 )
 
 // MockItemRepository is a mock of ItemRepository
@@ -16,13 +19,24 @@ type MockItemRepository struct {
 	mock.Mock
 }
 
-func (m *MockItemRepository) GetAll(ctx context.Context) ([]*domain.Item, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*domain.Item), args.Error(1)
+func (m *MockItemRepository) GetAll(ctx context.Context, query domain.ItemQuery) (*domain.Page[*domain.Item], error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Error(1)
 }
 
-func (m *MockItemRepository) GetByID(ctx context.Context, id string) (*domain.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemRepository) Search(ctx context.Context, search domain.ItemSearch) (*domain.Page[*domain.ItemSearchResult], error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.ItemSearchResult]), args.Error(1)
+}
+
+func (m *MockItemRepository) GetByID(ctx context.Context, id string, opts domain.QueryOptions) (*domain.Item, error) {
+	args := m.Called(ctx, id, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -39,11 +53,79 @@ func (m *MockItemRepository) Update(ctx context.Context, item *domain.Item) erro
 	return args.Error(0)
 }
 
-func (m *MockItemRepository) Delete(ctx context.Context, id string) error {
+func (m *MockItemRepository) Delete(ctx context.Context, id string, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) GetDeleted(ctx context.Context, page domain.PageRequest) (*domain.Page[*domain.Item], error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.Item]), args.Error(1)
+}
+
+func (m *MockItemRepository) Restore(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockItemRepository) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepository) GetRevisions(ctx context.Context, id string, page domain.PageRequest) (*domain.Page[*domain.ItemRevision], error) {
+	args := m.Called(ctx, id, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Page[*domain.ItemRevision]), args.Error(1)
+}
+
+func (m *MockItemRepository) GetRevision(ctx context.Context, id string, revision int64) (*domain.ItemRevision, error) {
+	args := m.Called(ctx, id, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemRevision), args.Error(1)
+}
+
+func (m *MockItemRepository) GetRevisionAt(ctx context.Context, id string, at time.Time) (*domain.ItemRevision, error) {
+	args := m.Called(ctx, id, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemRevision), args.Error(1)
+}
+
+func (m *MockItemRepository) CreateBatch(ctx context.Context, items []*domain.Item) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) UpdateBatch(ctx context.Context, items []*domain.Item) ([]error, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemRepository) DeleteBatch(ctx context.Context, items []*domain.Item) ([]error, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
 // MockCacheRepository is a mock of CacheRepository
 type MockCacheRepository struct {
 	mock.Mock
@@ -74,19 +156,81 @@ func (m *MockCacheRepository) Ping(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockCacheRepository) Lock(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, token, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCacheRepository) Unlock(ctx context.Context, key string, token string) error {
+	args := m.Called(ctx, key, token)
+	return args.Error(0)
+}
+
+// testTenant is the tenant of every test request unless stated otherwise
+const testTenant = "acme"
+
+// userCtx returns a context authenticated as a regular caller who only sees their own items
+func userCtx(subject string) context.Context {
+	ctx := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: subject})
+	return domain.ContextWithTenant(ctx, testTenant)
+}
+
+// adminCtx returns a context authenticated as an admin who sees every item of the tenant
+func adminCtx() context.Context {
+	ctx := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "admin-1", Roles: []string{domain.RoleAdmin}})
+	return domain.ContextWithTenant(ctx, testTenant)
+}
+
+// testTransactions runs units of work without a database, committing those that succeed:
+// the functions they register with domain.AfterCommit run only then.
+type testTransactions struct{}
+
+func (testTransactions) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, hooks := domain.ContextWithCommitHooks(ctx)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	hooks.Run()
+	return nil
+}
+
+// newTestValidator returns the real validator, for tests of validated changes
+func newTestValidator() domain.Validator {
+	return validation.NewValidator()
+}
+
+// itemUUID and propertyUUID identify the resources of tests that validate them
+const (
+	itemUUID     = "550e8400-e29b-41d4-a716-446655440000"
+	propertyUUID = "550e8400-e29b-41d4-a716-446655440001"
+)
+
+// testPage is the page requested by list tests
+var testPage = domain.PageRequest{Number: 1, Size: 20}
+
+// testQuery is the items query of list tests
+var testQuery = domain.ItemQuery{Page: testPage}
+
+// pageKey returns the cache key of the entry of the group under groupKey (a page of a list,
+// or a shape of a resource) selected by query, in generation "g1"
+func pageKey(groupKey string, query any) string {
+	return queryCacheKey(groupKey, "g1", query)
+}
+
 func TestItemService_GetAllItems_CacheMiss(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
-	expectedItems := []*domain.Item{{ID: "1", Title: "Test"}}
+	expectedItems := &domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", Title: "Test"}}, Total: 1}
 
 	// Cache miss scenario
-	cache.On("Get", mock.Anything, "items:list").Return("", errors.New("cache miss"))
-	repo.On("GetAll", mock.Anything).Return(expectedItems, nil)
-	cache.On("Set", mock.Anything, "items:list", mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", testQuery)).Return("", errors.New("cache miss"))
+	repo.On("GetAll", mock.Anything, testQuery).Return(expectedItems, nil)
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", testQuery), mock.Anything, 5*time.Minute).Return(nil)
 
-	items, err := svc.GetAllItems(context.Background())
+	items, _, err := svc.GetAllItems(userCtx("user-1"), testQuery)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedItems, items)
@@ -97,35 +241,81 @@ func TestItemService_GetAllItems_CacheMiss(t *testing.T) {
 func TestItemService_GetAllItems_CacheHit(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
-	cachedJSON := `[{"ID":"1","Title":"Test","Description":"","ItemProperties":null}]`
+	cachedJSON := `{"items":[{"ID":"1","Title":"Test","Description":"","ItemProperties":null}],"total":1}`
 
 	// Cache hit scenario - repo should NOT be called
-	cache.On("Get", mock.Anything, "items:list").Return(cachedJSON, nil)
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", testQuery)).Return(cachedJSON, nil)
+
+	items, _, err := svc.GetAllItems(userCtx("user-1"), testQuery)
+
+	assert.NoError(t, err)
+	assert.Len(t, items.Items, 1)
+	assert.Equal(t, "1", items.Items[0].ID)
+	assert.Equal(t, int64(1), items.Total)
+	cache.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestItemService_SearchItems_CacheMiss(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	search := domain.ItemSearch{Text: "teak", Page: testPage}
+	expected := &domain.Page[*domain.ItemSearchResult]{Items: []*domain.ItemSearchResult{{Item: &domain.Item{ID: "1", Title: "Teak table"}, Score: 1.5}}, Total: 1}
+
+	// Search results share the generation of the items list, under keys of their own
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1:search", search)).Return("", errors.New("cache miss"))
+	repo.On("Search", mock.Anything, search).Return(expected, nil)
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1:search", search), mock.Anything, 5*time.Minute).Return(nil)
+
+	results, err := svc.SearchItems(userCtx("user-1"), search)
 
-	items, err := svc.GetAllItems(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, results)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_SearchItems_CacheHit(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	search := domain.ItemSearch{Text: "teak", Page: testPage}
+	cachedJSON := `{"items":[{"item":{"ID":"1","Title":"Teak table"},"score":1.5}],"total":1}`
+
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1:search", search)).Return(cachedJSON, nil)
+
+	results, err := svc.SearchItems(userCtx("user-1"), search)
 
 	assert.NoError(t, err)
-	assert.Len(t, items, 1)
-	assert.Equal(t, "1", items[0].ID)
+	assert.Len(t, results.Items, 1)
+	assert.Equal(t, "1", results.Items[0].Item.ID)
+	assert.Equal(t, 1.5, results.Items[0].Score)
 	cache.AssertExpectations(t)
-	repo.AssertNotCalled(t, "GetAll", mock.Anything)
+	repo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
 }
 
 func TestItemService_GetItemByID_CacheMiss(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
 	expectedItem := &domain.Item{ID: "1", Title: "Test"}
 
 	// Cache miss scenario
-	cache.On("Get", mock.Anything, "item:1").Return("", errors.New("cache miss"))
-	repo.On("GetByID", mock.Anything, "1").Return(expectedItem, nil)
-	cache.On("Set", mock.Anything, "item:1", mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Get", mock.Anything, "item:tenant:acme:owner:user-1:1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item:tenant:acme:owner:user-1:1", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(expectedItem, nil)
+	cache.On("Set", mock.Anything, pageKey("item:tenant:acme:owner:user-1:1", domain.QueryOptions{}), mock.Anything, 5*time.Minute).Return(nil)
 
-	item, err := svc.GetItemByID(context.Background(), "1")
+	item, _, err := svc.GetItemByID(userCtx("user-1"), "1", domain.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedItem, item)
@@ -136,51 +326,101 @@ func TestItemService_GetItemByID_CacheMiss(t *testing.T) {
 func TestItemService_GetItemByID_CacheHit(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
 	cachedJSON := `{"ID":"1","Title":"Test","Description":"","ItemProperties":null}`
 
 	// Cache hit scenario - repo should NOT be called
-	cache.On("Get", mock.Anything, "item:1").Return(cachedJSON, nil)
+	cache.On("Get", mock.Anything, "item:tenant:acme:owner:user-1:1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item:tenant:acme:owner:user-1:1", domain.QueryOptions{})).Return(cachedJSON, nil)
 
-	item, err := svc.GetItemByID(context.Background(), "1")
+	item, _, err := svc.GetItemByID(userCtx("user-1"), "1", domain.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, "1", item.ID)
 	assert.Equal(t, "Test", item.Title)
 	cache.AssertExpectations(t)
-	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestItemService_CreateItem(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
-	item := &domain.Item{Title: "New Item"}
+	item := &domain.Item{Title: "New Item", OwnerID: "user-1", TenantID: "acme"}
 	repo.On("Create", mock.Anything, item).Return(nil)
-	// Cache invalidation for items list
-	cache.On("Delete", mock.Anything, "items:list").Return(nil)
+	// Cache invalidation for the owner's and the admins' items lists
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
 
-	err := svc.CreateItem(context.Background(), item)
+	err := svc.CreateItem(userCtx("user-1"), item)
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
 
+func TestItemService_CreateItem_WithProperties(t *testing.T) {
+	repo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, propertyRepo, testTransactions{}, cache, newTestValidator())
+
+	properties := []*domain.ItemProperty{{ID: propertyUUID, Name: "color", Value: "red"}}
+	item := &domain.Item{ID: itemUUID, Title: "Lamp", OwnerID: "user-1", TenantID: "acme", ItemProperties: properties}
+	// The item is created alone, then each property through its repository
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(item *domain.Item) bool { return item.ItemProperties == nil })).Return(nil)
+	propertyRepo.On("Create", mock.Anything, mock.MatchedBy(func(property *domain.ItemProperty) bool {
+		return property.ItemID == itemUUID && property.Name == "color"
+	})).Return(nil)
+	cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	err := svc.CreateItem(userCtx("user-1"), item)
+
+	assert.NoError(t, err)
+	assert.Equal(t, properties, item.ItemProperties)
+	repo.AssertExpectations(t)
+	propertyRepo.AssertExpectations(t)
+}
+
+func TestItemService_CreateItem_RollsBack(t *testing.T) {
+	repo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, propertyRepo, testTransactions{}, cache, newTestValidator())
+
+	item := &domain.Item{ID: itemUUID, Title: "Lamp", OwnerID: "user-1", TenantID: "acme", ItemProperties: []*domain.ItemProperty{
+		{ID: propertyUUID, Name: "color", Value: "red"},
+	}}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	propertyRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrConflict)
+
+	err := svc.CreateItem(userCtx("user-1"), item)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	// The lists are not invalidated for a creation that is rolled back
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestItemService_UpdateItem(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
 	item := &domain.Item{ID: "1", Title: "Updated"}
-	repo.On("Update", mock.Anything, item).Return(nil)
-	// Cache invalidation for single item and items list
-	cache.On("Delete", mock.Anything, "item:1").Return(nil)
-	cache.On("Delete", mock.Anything, "items:list").Return(nil)
-
-	err := svc.UpdateItem(context.Background(), item)
+	// The repository fills in the owner of the stored item
+	repo.On("Update", mock.Anything, item).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Item).OwnerID = "user-1"
+		args.Get(1).(*domain.Item).TenantID = "acme"
+	}).Return(nil)
+	// Cache invalidation for single item and items list in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:1").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
+
+	err := svc.UpdateItem(userCtx("user-1"), item)
 
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
@@ -190,33 +430,480 @@ func TestItemService_UpdateItem(t *testing.T) {
 func TestItemService_DeleteItem(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(&domain.Item{ID: "1", OwnerID: "user-1", TenantID: "acme"}, nil)
+	repo.On("Delete", mock.Anything, "1", int64(0)).Return(nil)
+	// Cache invalidation for every shape of the single item and the items list in the owner's and the admins' scopes
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:1").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
+	// The properties of the item are deleted with it
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:1").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:1").Return(nil)
+
+	err := svc.DeleteItem(userCtx("user-1"), "1", 0)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_RestoreItem(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
-	repo.On("Delete", mock.Anything, "1").Return(nil)
-	// Cache invalidation for single item and items list
-	cache.On("Delete", mock.Anything, "item:1").Return(nil)
-	cache.On("Delete", mock.Anything, "items:list").Return(nil)
+	repo.On("Restore", mock.Anything, "1").Return(nil)
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(&domain.Item{ID: "1", OwnerID: "user-1", TenantID: "acme", Version: 2}, nil)
+	// The caches were filled without the item while it was deleted
+	cache.On("Delete", mock.Anything, "item:tenant:acme:owner:user-1:1").Return(nil)
+	cache.On("Delete", mock.Anything, "item:tenant:acme:all:1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:owner:user-1").Return(nil)
+	cache.On("Delete", mock.Anything, "items:list:tenant:acme:all").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:1").Return(nil)
+	cache.On("Delete", mock.Anything, "item_properties:list:tenant:acme:all:1").Return(nil)
 
-	err := svc.DeleteItem(context.Background(), "1")
+	item, err := svc.RestoreItem(adminCtx(), "1")
 
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), item.Version)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
 
+func TestItemService_RestoreItem_NotDeleted(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	repo.On("Restore", mock.Anything, "1").Return(domain.ErrNotFound)
+
+	_, err := svc.RestoreItem(adminCtx(), "1")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestItemService_GetItemByID_Error(t *testing.T) {
 	repo := new(MockItemRepository)
 	cache := new(MockCacheRepository)
-	svc := NewItemService(repo, cache)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
 
 	// Cache miss, then repo returns error
-	cache.On("Get", mock.Anything, "item:1").Return("", errors.New("cache miss"))
-	repo.On("GetByID", mock.Anything, "1").Return(nil, errors.New("not found"))
+	cache.On("Get", mock.Anything, "item:tenant:acme:owner:user-1:1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item:tenant:acme:owner:user-1:1", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(nil, errors.New("not found"))
 
-	item, err := svc.GetItemByID(context.Background(), "1")
+	item, _, err := svc.GetItemByID(userCtx("user-1"), "1", domain.QueryOptions{})
 
 	assert.Error(t, err)
 	assert.Nil(t, item)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
+
+func TestItemService_CacheIsScopedByOwner(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	aliceItems := &domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "1", Title: "Alice's", OwnerID: "alice", TenantID: "acme"}}, Total: 1}
+	bobItems := &domain.Page[*domain.Item]{Items: []*domain.Item{{ID: "2", Title: "Bob's", OwnerID: "bob", TenantID: "acme"}}, Total: 1}
+
+	// Each caller reads and writes their own cache entry, so Alice's cached list is never served to Bob
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:alice").Return("g1", nil)
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:bob").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:alice", testQuery)).Return("", errors.New("cache miss"))
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:bob", testQuery)).Return("", errors.New("cache miss"))
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:alice", testQuery), mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:bob", testQuery), mock.Anything, 5*time.Minute).Return(nil)
+	repo.On("GetAll", mock.MatchedBy(func(ctx context.Context) bool {
+		p, _ := domain.PrincipalFromContext(ctx)
+		return p.Subject == "alice"
+	}), testQuery).Return(aliceItems, nil)
+	repo.On("GetAll", mock.MatchedBy(func(ctx context.Context) bool {
+		p, _ := domain.PrincipalFromContext(ctx)
+		return p.Subject == "bob"
+	}), testQuery).Return(bobItems, nil)
+
+	items, _, err := svc.GetAllItems(userCtx("alice"), testQuery)
+	assert.NoError(t, err)
+	assert.Equal(t, aliceItems, items)
+
+	items, _, err = svc.GetAllItems(userCtx("bob"), testQuery)
+	assert.NoError(t, err)
+	assert.Equal(t, bobItems, items)
+
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_CacheKeyEscapesOwner(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	// A subject containing separators cannot address another caller's key
+	cache.On("Get", mock.Anything, "item:tenant:acme:owner:eve%3A1:2").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item:tenant:acme:owner:eve%3A1:2", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, "2", domain.QueryOptions{}).Return(nil, errors.New("not found"))
+
+	_, _, err := svc.GetItemByID(userCtx("eve:1"), "2", domain.QueryOptions{})
+
+	assert.Error(t, err)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_AdminCacheScope(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:all").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:all", testQuery)).Return(`{"items":[{"ID":"1","OwnerID":"alice"},{"ID":"2","OwnerID":"bob"}],"total":2}`, nil)
+
+	items, _, err := svc.GetAllItems(adminCtx(), testQuery)
+
+	assert.NoError(t, err)
+	assert.Len(t, items.Items, 2)
+	repo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestItemService_RequiresPrincipal(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	_, _, err := svc.GetAllItems(context.Background(), testQuery)
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	_, _, err = svc.GetItemByID(context.Background(), "1", domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	cache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestItemService_DeleteItem_NotVisible(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(nil, errors.New("not found"))
+
+	err := svc.DeleteItem(userCtx("bob"), "1", 0)
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestItemService_CacheIsScopedByTenant(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	// The same subject in another tenant reads a different cache entry
+	globexCtx := domain.ContextWithTenant(userCtx("user-1"), "globex")
+	cache.On("Get", mock.Anything, "item:tenant:globex:owner:user-1:1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("item:tenant:globex:owner:user-1:1", domain.QueryOptions{})).Return("", errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, "1", domain.QueryOptions{}).Return(nil, domain.ErrNotFound)
+
+	_, _, err := svc.GetItemByID(globexCtx, "1", domain.QueryOptions{})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	cache.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Get", mock.Anything, "item:tenant:acme:owner:user-1:1")
+}
+
+func TestItemService_RequiresTenant(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	ctx := domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: "user-1"})
+
+	_, _, err := svc.GetAllItems(ctx, testQuery)
+	assert.ErrorIs(t, err, domain.ErrTenantRequired)
+
+	_, _, err = svc.GetItemByID(ctx, "1", domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrTenantRequired)
+
+	cache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestItemService_GetAllItems_CachesEachPage(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	cursorPage := domain.PageRequest{Size: 20, Cursor: "abc"}
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("g1", nil)
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", domain.ItemQuery{Page: domain.PageRequest{Number: 2, Size: 20}})).Return("", errors.New("cache miss"))
+	cache.On("Get", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", domain.ItemQuery{Page: cursorPage})).Return("", errors.New("cache miss"))
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", domain.ItemQuery{Page: domain.PageRequest{Number: 2, Size: 20}}), mock.Anything, 5*time.Minute).Return(nil)
+	cache.On("Set", mock.Anything, pageKey("items:list:tenant:acme:owner:user-1", domain.ItemQuery{Page: cursorPage}), mock.Anything, 5*time.Minute).Return(nil)
+	repo.On("GetAll", mock.Anything, mock.Anything).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, nil)
+
+	_, _, err := svc.GetAllItems(userCtx("user-1"), domain.ItemQuery{Page: domain.PageRequest{Number: 2, Size: 20}})
+	assert.NoError(t, err)
+	_, _, err = svc.GetAllItems(userCtx("user-1"), domain.ItemQuery{Page: cursorPage})
+	assert.NoError(t, err)
+
+	repo.AssertNumberOfCalls(t, "GetAll", 2)
+	cache.AssertExpectations(t)
+}
+
+func TestItemService_GetAllItems_StartsListGeneration(t *testing.T) {
+	repo := new(MockItemRepository)
+	cache := new(MockCacheRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, cache, newTestValidator())
+
+	// Without a generation (e.g. right after an invalidation) a new one is started,
+	// so pages cached under the previous generation are not read again
+	cache.On("Get", mock.Anything, "items:list:tenant:acme:owner:user-1").Return("", errors.New("cache miss"))
+	cache.On("Set", mock.Anything, "items:list:tenant:acme:owner:user-1", mock.AnythingOfType("string"), 5*time.Minute).Return(nil)
+	cache.On("Get", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "items:list:tenant:acme:owner:user-1:") && key != "items:list:tenant:acme:owner:user-1"
+	})).Return("", errors.New("cache miss"))
+	cache.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "items:list:tenant:acme:owner:user-1:")
+	}), mock.Anything, 5*time.Minute).Return(nil)
+	repo.On("GetAll", mock.Anything, testQuery).Return(&domain.Page[*domain.Item]{Items: []*domain.Item{}}, nil)
+
+	_, _, err := svc.GetAllItems(userCtx("user-1"), testQuery)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	query := domain.ItemQuery{
+		TitlePrefix: "Ap",
+		CreatedAt:   domain.TimeRange{GTE: &from},
+		Properties:  map[string]string{"color": "red", "size": "large"},
+		Sort:        []domain.SortField{{Field: domain.ItemSortCreatedAt, Desc: true}},
+		Page:        testPage,
+	}
+
+	// Equal queries share a key, whatever order their properties were added in
+	same := query
+	same.Properties = map[string]string{}
+	same.Properties["size"] = "large"
+	same.Properties["color"] = "red"
+	assert.Equal(t, queryCacheKey("items:list:x", "g1", query), queryCacheKey("items:list:x", "g1", same))
+	assert.True(t, strings.HasPrefix(queryCacheKey("items:list:x", "g1", query), "items:list:x:g1:"))
+
+	// Any difference in filters, sort, page, includes or fields selects another key
+	for _, change := range []func(q *domain.ItemQuery){
+		func(q *domain.ItemQuery) { q.TitlePrefix = "Apr" },
+		func(q *domain.ItemQuery) { q.CreatedAt = domain.TimeRange{LT: &from} },
+		func(q *domain.ItemQuery) { q.Properties = map[string]string{"color": "red"} },
+		func(q *domain.ItemQuery) { q.Sort = []domain.SortField{{Field: domain.ItemSortCreatedAt}} },
+		func(q *domain.ItemQuery) { q.Page.Number = 2 },
+		func(q *domain.ItemQuery) { q.Options = withProperties },
+		func(q *domain.ItemQuery) {
+			q.Options = domain.QueryOptions{Fields: map[string][]string{domain.ResourceItems: {"title"}}}
+		},
+	} {
+		other := query
+		change(&other)
+		assert.NotEqual(t, queryCacheKey("items:list:x", "g1", query), queryCacheKey("items:list:x", "g1", other))
+	}
+}
+
+func TestItemService_PatchItem(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(&domain.Item{
+		ID: itemUUID, Title: "Lamp", Description: "Teak lamp", OwnerID: "user-1", TenantID: "acme", CreatedAt: &createdAt,
+	}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// The patch sets a description too, outside the mask
+	item, err := svc.PatchItem(ctx, itemUUID, &domain.Item{Title: "Desk lamp", Description: "ignored"}, domain.FieldMask{"title"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Desk lamp", item.Title)
+	assert.Equal(t, "Teak lamp", item.Description)
+	assert.Equal(t, &createdAt, item.CreatedAt)
+	repo.AssertCalled(t, "Update", mock.Anything, item)
+	// The audit log records the save as a patch
+	repo.AssertCalled(t, "Update", mock.MatchedBy(func(ctx context.Context) bool {
+		action, _ := domain.AuditActionFromContext(ctx)
+		return action == domain.AuditActionPatch
+	}), item)
+}
+
+func TestItemService_PatchItem_ValidatesMergedItem(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).
+		Return(&domain.Item{ID: itemUUID, Title: "Lamp", OwnerID: "user-1", TenantID: "acme"}, nil)
+
+	_, err := svc.PatchItem(userCtx("user-1"), itemUUID, &domain.Item{Title: ""}, domain.FieldMask{"title"})
+
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	var validationErrors domain.ValidationErrors
+	if assert.ErrorAs(t, err, &validationErrors) {
+		assert.Equal(t, "/data/attributes/title", validationErrors[0].Pointer)
+	}
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestItemService_PatchItem_NotFound(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(nil, domain.ErrNotFound)
+
+	_, err := svc.PatchItem(userCtx("user-1"), itemUUID, &domain.Item{Title: "Lamp"}, domain.FieldMask{"title"})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestItemService_PatchItem_Versions(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).
+		Return(&domain.Item{ID: itemUUID, Title: "Lamp", OwnerID: "user-1", TenantID: "acme", Version: 3}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// A patch expecting another version fails before anything is saved
+	_, err := svc.PatchItem(ctx, itemUUID, &domain.Item{Title: "Desk lamp", Version: 2}, domain.FieldMask{"title"})
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	// Otherwise the save is conditional on the version read
+	_, err = svc.PatchItem(ctx, itemUUID, &domain.Item{Title: "Desk lamp"}, domain.FieldMask{"title"})
+	assert.NoError(t, err)
+	repo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(item *domain.Item) bool {
+		return item.Version == 3 && item.Title == "Desk lamp"
+	}))
+}
+
+func TestItemService_ListItemRevisions(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	revisions := &domain.Page[*domain.ItemRevision]{Items: []*domain.ItemRevision{{ItemID: itemUUID, Revision: 1}}, Total: 1}
+	repo.On("GetRevisions", mock.Anything, itemUUID, domain.PageRequest{Number: 1, Size: 10}).Return(revisions, nil)
+
+	result, err := svc.ListItemRevisions(ctx, itemUUID, domain.PageRequest{Number: 1, Size: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, revisions, result)
+	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemService_ListItemRevisions_NoRevisions(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+	empty := &domain.Page[*domain.ItemRevision]{Items: []*domain.ItemRevision{}}
+	missing := "0c5e3b1f-7f1e-4a8e-9b7a-3f1d2c4b5a69"
+
+	repo.On("GetRevisions", mock.Anything, mock.Anything, mock.Anything).Return(empty, nil)
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(&domain.Item{ID: itemUUID}, nil)
+	repo.On("GetByID", mock.Anything, missing, domain.QueryOptions{}).Return(nil, domain.ErrNotFound)
+
+	// An item stored before revisions were introduced has none
+	result, err := svc.ListItemRevisions(ctx, itemUUID, domain.PageRequest{Number: 1, Size: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Items)
+
+	_, err = svc.ListItemRevisions(ctx, missing, domain.PageRequest{Number: 1, Size: 10})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemService_GetItemAsOf(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	snapshot := func() *domain.ItemRevision {
+		return &domain.ItemRevision{ItemID: itemUUID, Revision: 2, Item: &domain.Item{
+			ID: itemUUID, Title: "Lamp", ItemProperties: []*domain.ItemProperty{{ItemID: itemUUID, Name: "color", Value: "red"}},
+		}}
+	}
+	repo.On("GetRevisionAt", mock.Anything, itemUUID, at).Return(snapshot(), nil).Once()
+	repo.On("GetRevisionAt", mock.Anything, itemUUID, at).Return(snapshot(), nil).Once()
+
+	item, err := svc.GetItemAsOf(ctx, itemUUID, at, domain.QueryOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Lamp", item.Title)
+	assert.Nil(t, item.ItemProperties)
+
+	item, err = svc.GetItemAsOf(ctx, itemUUID, at, domain.QueryOptions{Include: []string{domain.ItemIncludeProperties}})
+	assert.NoError(t, err)
+	assert.Len(t, item.ItemProperties, 1)
+}
+
+func TestItemService_GetItemAsOf_Deleted(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	deleted := &domain.Item{ID: itemUUID, Title: "Lamp", DeletedAt: gorm.DeletedAt{Time: at, Valid: true}}
+	repo.On("GetRevisionAt", mock.Anything, itemUUID, at).Return(&domain.ItemRevision{ItemID: itemUUID, Revision: 3, Item: deleted}, nil)
+
+	_, err := svc.GetItemAsOf(userCtx("user-1"), itemUUID, at, domain.QueryOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestItemService_RevertItem(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+	ctx := userCtx("user-1")
+
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(&domain.Item{
+		ID: itemUUID, Title: "Desk lamp", Description: "Oak", OwnerID: "user-1", TenantID: "acme", Version: 4,
+	}, nil)
+	repo.On("GetRevision", mock.Anything, itemUUID, int64(1)).Return(&domain.ItemRevision{ItemID: itemUUID, Revision: 1, Item: &domain.Item{
+		ID: itemUUID, Title: "Lamp", Description: "Teak", OwnerID: "user-1", TenantID: "acme", Version: 1,
+	}}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// A revert expecting another version fails before anything is saved
+	_, err := svc.RevertItem(ctx, itemUUID, 1, 3)
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	item, err := svc.RevertItem(ctx, itemUUID, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Lamp", item.Title)
+	assert.Equal(t, "Teak", item.Description)
+	// The revert is a new change, conditional on the version read, recorded as a revert
+	repo.AssertCalled(t, "Update", mock.MatchedBy(func(ctx context.Context) bool {
+		action, _ := domain.AuditActionFromContext(ctx)
+		return action == domain.AuditActionRevert
+	}), mock.MatchedBy(func(item *domain.Item) bool {
+		return item.Version == 4 && item.Title == "Lamp"
+	}))
+}
+
+func TestItemService_RevertItem_RevisionNotFound(t *testing.T) {
+	repo := new(MockItemRepository)
+	svc := NewItemService(repo, new(MockItemPropertyRepository), testTransactions{}, newMemoryCache(), newTestValidator())
+
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(&domain.Item{ID: itemUUID, Title: "Lamp", Version: 2}, nil)
+	repo.On("GetRevision", mock.Anything, itemUUID, int64(9)).Return(nil, domain.ErrNotFound)
+
+	_, err := svc.RevertItem(userCtx("user-1"), itemUUID, 9, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+package items
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+)
+
+type operationService struct {
+	itemService         domain.ItemService
+	itemPropertyService domain.ItemPropertyService
+	transactions        domain.TransactionManager
+	validator           domain.Validator
+}
+
+func NewOperationService(itemService domain.ItemService, itemPropertyService domain.ItemPropertyService, transactions domain.TransactionManager, validator domain.Validator) domain.OperationService {
+	return &operationService{
+		itemService:         itemService,
+		itemPropertyService: itemPropertyService,
+		transactions:        transactions,
+		validator:           validator,
+	}
+}
+
+// ApplyOperations applies each operation through the item or item property service, in a
+// transaction their writes join. The caches they invalidate are only dropped once it
+// commits, so a rolled back request leaves them as they were.
+func (s *operationService) ApplyOperations(ctx context.Context, operations []*domain.Operation) error {
+	return s.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, operation := range operations {
+			if err := s.apply(ctx, operation); err != nil {
+				return &domain.OperationError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *operationService) apply(ctx context.Context, operation *domain.Operation) error {
+	switch {
+	case operation.Item != nil:
+		return s.applyToItem(ctx, operation.Code, operation.Item, operation.Mask)
+	case operation.Property != nil:
+		return s.applyToItemProperty(ctx, operation.Code, operation.Property, operation.Mask)
+	default:
+		return fmt.Errorf("%w: the operation has no resource", domain.ErrValidation)
+	}
+}
+
+func (s *operationService) applyToItem(ctx context.Context, code domain.OperationCode, item *domain.Item, mask domain.FieldMask) error {
+	switch code {
+	case domain.OperationAdd:
+		if validationErrors := s.validator.Validate(item); len(validationErrors) > 0 {
+			return validationErrors
+		}
+		return s.itemService.CreateItem(ctx, item)
+	case domain.OperationUpdate:
+		updated, err := s.itemService.PatchItem(ctx, item.ID, item, mask)
+		if err != nil {
+			return err
+		}
+		*item = *updated
+		return nil
+	case domain.OperationRemove:
+		return s.itemService.DeleteItem(ctx, item.ID, item.Version)
+	default:
+		return fmt.Errorf("%w: unknown operation %q", domain.ErrValidation, code)
+	}
+}
+
+func (s *operationService) applyToItemProperty(ctx context.Context, code domain.OperationCode, property *domain.ItemProperty, mask domain.FieldMask) error {
+	// Properties are changed within their item, which the request need not name
+	if property.ItemID == "" && code != domain.OperationAdd {
+		stored, err := s.itemPropertyService.FindItemProperty(ctx, property.ID)
+		if err != nil {
+			return err
+		}
+		property.ItemID = stored.ItemID
+	}
+
+	switch code {
+	case domain.OperationAdd:
+		if validationErrors := s.validator.Validate(property); len(validationErrors) > 0 {
+			return validationErrors
+		}
+		return s.itemPropertyService.CreateItemProperty(ctx, property)
+	case domain.OperationUpdate:
+		updated, err := s.itemPropertyService.PatchItemProperty(ctx, property.ItemID, property.ID, property, mask)
+		if err != nil {
+			return err
+		}
+		*property = *updated
+		return nil
+	case domain.OperationRemove:
+		return s.itemPropertyService.DeleteItemProperty(ctx, property.ItemID, property.ID, property.Version)
+	default:
+		return fmt.Errorf("%w: unknown operation %q", domain.ErrValidation, code)
+	}
+}
@@ -0,0 +1,101 @@
+package items
+
+import (
+	"testing"
+
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOperationService returns an operation service over the item services of the mocks
+func newTestOperationService(repo *MockItemRepository, propertyRepo *MockItemPropertyRepository, cache *MockCacheRepository) domain.OperationService {
+	validator := newTestValidator()
+	itemService := NewItemService(repo, propertyRepo, testTransactions{}, cache, validator)
+	itemPropertyService := NewItemPropertyService(propertyRepo, repo, cache, validator)
+	return NewOperationService(itemService, itemPropertyService, testTransactions{}, validator)
+}
+
+func TestOperationService_ApplyOperations(t *testing.T) {
+	repo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := new(MockCacheRepository)
+	svc := newTestOperationService(repo, propertyRepo, cache)
+
+	const otherItemUUID = "550e8400-e29b-41d4-a716-446655440002"
+	added := bulkItem(itemUUID)
+	patch := &domain.Item{ID: otherItemUUID, Title: "Desk lamp", Version: 2}
+	removed := &domain.ItemProperty{ID: propertyUUID}
+	operations := []*domain.Operation{
+		{Code: domain.OperationAdd, Item: added},
+		{Code: domain.OperationUpdate, Item: patch, Mask: domain.FieldMask{"title"}},
+		{Code: domain.OperationRemove, Property: removed},
+	}
+
+	repo.On("Create", mock.Anything, added).Return(nil)
+	repo.On("GetByID", mock.Anything, otherItemUUID, domain.QueryOptions{}).
+		Return(&domain.Item{ID: otherItemUUID, Title: "Lamp", Description: "Teak", OwnerID: "user-1", TenantID: testTenant, Version: 2}, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(item *domain.Item) bool {
+		return item.Title == "Desk lamp" && item.Description == "Teak"
+	})).Run(func(args mock.Arguments) { args.Get(1).(*domain.Item).Version = 3 }).Return(nil)
+	// The property is looked up to find its item
+	propertyRepo.On("Find", mock.Anything, propertyUUID).Return(&domain.ItemProperty{ID: propertyUUID, ItemID: otherItemUUID}, nil)
+	propertyRepo.On("Delete", mock.Anything, otherItemUUID, propertyUUID, int64(0)).Return(nil)
+	cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, svc.ApplyOperations(userCtx("user-1"), operations))
+
+	// Updated resources are read back into their operations
+	assert.Equal(t, "Desk lamp", patch.Title)
+	assert.Equal(t, "Teak", patch.Description)
+	assert.Equal(t, int64(3), patch.Version)
+	assert.Equal(t, otherItemUUID, removed.ItemID)
+	repo.AssertExpectations(t)
+	propertyRepo.AssertExpectations(t)
+	cache.AssertCalled(t, "Delete", mock.Anything, "item_properties:list:tenant:acme:owner:user-1:"+otherItemUUID)
+}
+
+func TestOperationService_ApplyOperations_RollsBack(t *testing.T) {
+	repo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	cache := new(MockCacheRepository)
+	svc := newTestOperationService(repo, propertyRepo, cache)
+
+	operations := []*domain.Operation{
+		{Code: domain.OperationRemove, Property: &domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID}},
+		{Code: domain.OperationRemove, Item: &domain.Item{ID: itemUUID, Version: 4}},
+		{Code: domain.OperationAdd, Item: bulkItem(itemUUID)},
+	}
+	item := bulkItem(itemUUID)
+	repo.On("GetByID", mock.Anything, itemUUID, domain.QueryOptions{}).Return(item, nil)
+	propertyRepo.On("Delete", mock.Anything, itemUUID, propertyUUID, int64(0)).Return(nil)
+	repo.On("Delete", mock.Anything, itemUUID, int64(4)).Return(domain.ErrPreconditionFailed)
+
+	err := svc.ApplyOperations(userCtx("user-1"), operations)
+
+	var operationErr *domain.OperationError
+	require.ErrorAs(t, err, &operationErr)
+	assert.Equal(t, 1, operationErr.Index)
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	// The operations after the failure are not applied, and the rolled back ones invalidate nothing
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestOperationService_ApplyOperations_ValidatesAdditions(t *testing.T) {
+	repo := new(MockItemRepository)
+	propertyRepo := new(MockItemPropertyRepository)
+	svc := newTestOperationService(repo, propertyRepo, new(MockCacheRepository))
+
+	operations := []*domain.Operation{
+		{Code: domain.OperationAdd, Property: &domain.ItemProperty{ID: propertyUUID, ItemID: itemUUID, Value: "red"}},
+	}
+
+	err := svc.ApplyOperations(userCtx("user-1"), operations)
+
+	var validationErrs domain.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	assert.Equal(t, "/data/attributes/name", validationErrs[0].Pointer)
+	propertyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
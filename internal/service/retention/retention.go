@@ -0,0 +1,89 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"go.uber.org/fx"
+)
+
+// Job purges the items and item properties that have been soft-deleted for longer
+// than the retention period. Purging is idempotent, so several instances of the
+// server may run the job at once.
+type Job struct {
+	itemRepo  domain.ItemRepository
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewJob(itemRepo domain.ItemRepository, cfg *config.Config) *Job {
+	return &Job{
+		itemRepo:  itemRepo,
+		retention: cfg.TrashRetention,
+		interval:  cfg.TrashPurgeInterval,
+		now:       time.Now,
+	}
+}
+
+// Enabled reports whether deleted rows are purged at all.
+func (j *Job) Enabled() bool {
+	return j.retention > 0 && j.interval > 0
+}
+
+// Run purges the rows deleted before the retention period once and returns how many it purged.
+func (j *Job) Run(ctx context.Context) (int64, error) {
+	return j.itemRepo.PurgeDeleted(ctx, j.now().Add(-j.retention))
+}
+
+// Start runs the job right away, then at every interval until ctx is done.
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := j.Run(ctx); err != nil {
+			log.Printf("Failed to purge deleted items: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted rows older than %s", purged, j.retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RegisterHooks starts the job with the fx application, unless it is disabled,
+// and waits for the run in progress to finish when the application stops.
+func RegisterHooks(lc fx.Lifecycle, job *Job) {
+	if !job.Enabled() {
+		log.Println("Trash retention is disabled, deleted items are kept until purged")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				job.Start(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
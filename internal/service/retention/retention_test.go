@@ -0,0 +1,73 @@
+package retention
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gadz82/go-api-boilerplate/internal/config"
+	"github.com/gadz82/go-api-boilerplate/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeItemRepository records the cutoffs it is asked to purge before
+type fakeItemRepository struct {
+	domain.ItemRepository
+	mu      sync.Mutex
+	cutoffs []time.Time
+}
+
+func (r *fakeItemRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cutoffs = append(r.cutoffs, before)
+	return 1, nil
+}
+
+func (r *fakeItemRepository) runs() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.cutoffs)
+}
+
+func TestJob_Run(t *testing.T) {
+	repo := &fakeItemRepository{}
+	job := NewJob(repo, &config.Config{TrashRetention: 48 * time.Hour, TrashPurgeInterval: time.Hour})
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+
+	purged, err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Equal(t, []time.Time{time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)}, repo.cutoffs)
+}
+
+func TestJob_Enabled(t *testing.T) {
+	assert.True(t, NewJob(nil, &config.Config{TrashRetention: time.Hour, TrashPurgeInterval: time.Minute}).Enabled())
+	assert.False(t, NewJob(nil, &config.Config{TrashRetention: 0, TrashPurgeInterval: time.Minute}).Enabled())
+	assert.False(t, NewJob(nil, &config.Config{TrashRetention: time.Hour, TrashPurgeInterval: 0}).Enabled())
+}
+
+func TestJob_Start(t *testing.T) {
+	repo := &fakeItemRepository{}
+	job := NewJob(repo, &config.Config{TrashRetention: time.Hour, TrashPurgeInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		job.Start(ctx)
+	}()
+
+	// The job runs right away, then at every interval
+	assert.Eventually(t, func() bool { return repo.runs() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop when its context was cancelled")
+	}
+}
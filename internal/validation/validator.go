@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -45,7 +46,7 @@ func (pv *PlaygroundValidator) Validate(obj interface{}) domain.ValidationErrors
 		return nil
 	}
 
-	return pv.translateErrors(err)
+	return pv.translateErrors(err, reflect.TypeOf(obj))
 }
 
 // ValidateField validates a single field value against a tag
@@ -55,11 +56,12 @@ func (pv *PlaygroundValidator) ValidateField(field interface{}, tag string) doma
 		return nil
 	}
 
-	return pv.translateErrors(err)
+	return pv.translateErrors(err, nil)
 }
 
-// translateErrors converts validator.ValidationErrors to domain.ValidationErrors
-func (pv *PlaygroundValidator) translateErrors(err error) domain.ValidationErrors {
+// translateErrors converts validator.ValidationErrors to domain.ValidationErrors.
+// Errors in fields of resource, when given, are located by a JSON:API pointer.
+func (pv *PlaygroundValidator) translateErrors(err error, resource reflect.Type) domain.ValidationErrors {
 	var validationErrors domain.ValidationErrors
 
 	if errs, ok := err.(validator.ValidationErrors); ok {
@@ -67,6 +69,7 @@ func (pv *PlaygroundValidator) translateErrors(err error) domain.ValidationError
 			validationErrors = append(validationErrors, domain.ValidationError{
 				Field:   toSnakeCase(e.Field()),
 				Message: formatErrorMessage(e),
+				Pointer: jsonapiPointer(resource, e),
 			})
 		}
 	}
@@ -74,6 +77,47 @@ func (pv *PlaygroundValidator) translateErrors(err error) domain.ValidationError
 	return validationErrors
 }
 
+// jsonapiPointer returns the JSON:API pointer to the value of the field of
+// resource that failed validation, read from the field's jsonapi tag, e.g.
+// "/data/attributes/scopes/0" for the first element of the scopes attribute.
+// It returns "" for values of nested structs or fields without a jsonapi tag.
+func jsonapiPointer(resource reflect.Type, e validator.FieldError) string {
+	if resource == nil {
+		return ""
+	}
+	for resource.Kind() == reflect.Pointer {
+		resource = resource.Elem()
+	}
+
+	// StructNamespace is "Type.Field", followed by "[index]" for elements
+	_, path, _ := strings.Cut(e.StructNamespace(), ".")
+	name, index, _ := strings.Cut(path, "[")
+	if resource.Kind() != reflect.Struct || strings.Contains(name, ".") {
+		return ""
+	}
+	field, ok := resource.FieldByName(name)
+	if !ok {
+		return ""
+	}
+
+	var pointer string
+	parts := strings.Split(field.Tag.Get("jsonapi"), ",")
+	switch {
+	case parts[0] == "primary":
+		pointer = "/data/id"
+	case parts[0] == "attr" && len(parts) > 1:
+		pointer = "/data/attributes/" + parts[1]
+	case parts[0] == "relation" && len(parts) > 1:
+		pointer = "/data/relationships/" + parts[1]
+	default:
+		return ""
+	}
+	if index != "" {
+		pointer += "/" + strings.TrimSuffix(index, "]")
+	}
+	return pointer
+}
+
 // formatErrorMessage creates a human-readable error message
 func formatErrorMessage(e validator.FieldError) string {
 	field := toSnakeCase(e.Field())
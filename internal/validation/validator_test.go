@@ -39,6 +39,9 @@ func TestPlaygroundValidator_Validate_RequiredField(t *testing.T) {
 	assert.Len(t, errors, 1)
 	assert.Equal(t, "title", errors[0].Field)
 	assert.Contains(t, errors[0].Message, "required")
+	assert.Equal(t, "/data/attributes/title", errors[0].Pointer)
+	// Validation errors match the domain error they stand for
+	assert.ErrorIs(t, errors, domain.ErrValidation)
 }
 
 func TestPlaygroundValidator_Validate_InvalidUUID(t *testing.T) {
@@ -55,6 +58,7 @@ func TestPlaygroundValidator_Validate_InvalidUUID(t *testing.T) {
 	assert.Len(t, errors, 1)
 	assert.Equal(t, "i_d", errors[0].Field) // toSnakeCase converts "ID" to "i_d"
 	assert.Contains(t, errors[0].Message, "UUID")
+	assert.Equal(t, "/data/id", errors[0].Pointer)
 }
 
 func TestPlaygroundValidator_Validate_EmptyUUID(t *testing.T) {
@@ -169,6 +173,25 @@ func TestPlaygroundValidator_Validate_ItemProperty_MissingRequired(t *testing.T)
 	errors := v.Validate(property)
 	assert.NotNil(t, errors)
 	assert.Len(t, errors, 2)
+	assert.Equal(t, "/data/attributes/name", errors[0].Pointer)
+	assert.Equal(t, "/data/attributes/value", errors[1].Pointer)
+}
+
+func TestPlaygroundValidator_Validate_PointerToElement(t *testing.T) {
+	v := NewValidator()
+
+	apiKey := &domain.APIKey{Owner: "billing", Scopes: []string{"items:read", ""}}
+
+	errors := v.Validate(apiKey)
+	if assert.Len(t, errors, 1) {
+		assert.Equal(t, "/data/attributes/scopes/1", errors[0].Pointer)
+	}
+
+	// Values validated on their own are not located in a document
+	errors = v.ValidateField("", "required")
+	if assert.Len(t, errors, 1) {
+		assert.Empty(t, errors[0].Pointer)
+	}
 }
 
 func TestToSnakeCase(t *testing.T) {